@@ -0,0 +1,399 @@
+package willow
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// BindingKind identifies the physical input an InputBinding reads.
+type BindingKind uint8
+
+const (
+	// BindingKey reads a keyboard key.
+	BindingKey BindingKind = iota
+	// BindingGamepadButton reads a standard-layout gamepad button.
+	BindingGamepadButton
+	// BindingGamepadAxis reads a standard-layout gamepad stick axis.
+	BindingGamepadAxis
+)
+
+// AnyGamepad makes a gamepad binding read every connected gamepad.
+const AnyGamepad = -1
+
+// actionPressThreshold is the binding value at which an action counts as
+// pressed, so sticks and analog triggers act as buttons past half travel.
+const actionPressThreshold = 0.5
+
+// InputBinding binds one physical input to an action.
+type InputBinding struct {
+	Kind   BindingKind
+	Key    ebiten.Key                   // for BindingKey
+	Button ebiten.StandardGamepadButton // for BindingGamepadButton
+	Axis   ebiten.StandardGamepadAxis   // for BindingGamepadAxis
+	// Gamepad is the index of the gamepad in connection order, or
+	// AnyGamepad to read all of them.
+	Gamepad int
+	// Scale multiplies the binding's contribution to the action's Value.
+	// Bind a key with -1 to push an axis action negative. Zero means 1.
+	Scale float64
+	// Deadzone ignores axis values whose magnitude is below it. Values
+	// beyond it are rescaled to start from 0.
+	Deadzone float64
+}
+
+// KeyBinding returns a binding for a keyboard key.
+func KeyBinding(key ebiten.Key) InputBinding {
+	return InputBinding{Kind: BindingKey, Key: key, Gamepad: AnyGamepad}
+}
+
+// GamepadButtonBinding returns a binding for a button on any gamepad.
+func GamepadButtonBinding(button ebiten.StandardGamepadButton) InputBinding {
+	return InputBinding{Kind: BindingGamepadButton, Button: button, Gamepad: AnyGamepad}
+}
+
+// GamepadAxisBinding returns a binding for a stick axis on any gamepad.
+func GamepadAxisBinding(axis ebiten.StandardGamepadAxis, deadzone float64) InputBinding {
+	return InputBinding{Kind: BindingGamepadAxis, Axis: axis, Gamepad: AnyGamepad, Deadzone: deadzone}
+}
+
+// Scaled returns a copy of the binding with Scale set to s.
+func (b InputBinding) Scaled(s float64) InputBinding {
+	b.Scale = s
+	return b
+}
+
+// OnGamepad returns a copy of the binding that reads only the gamepad at
+// index i in connection order.
+func (b InputBinding) OnGamepad(i int) InputBinding {
+	b.Gamepad = i
+	return b
+}
+
+// applyDeadzone zeroes v inside the deadzone and rescales the rest to [0, 1].
+func applyDeadzone(v, dz float64) float64 {
+	if dz <= 0 {
+		return v
+	}
+	a := math.Abs(v)
+	if a <= dz {
+		return 0
+	}
+	if dz >= 1 {
+		return math.Copysign(1, v)
+	}
+	return math.Copysign((a-dz)/(1-dz), v)
+}
+
+// --- ActionMap ---
+
+// ActionMap maps named actions such as "jump" or "move_x" to keys,
+// gamepad buttons and gamepad axes, so game code can query actions instead
+// of raw devices. Each scene owns one, polled at the start of Scene.Update;
+// see Scene.Actions.
+type ActionMap struct {
+	actions map[string]*actionState
+
+	pads   []ebiten.GamepadID
+	tick   int64
+	polled bool
+}
+
+type actionState struct {
+	bindings []InputBinding
+	value    float64
+	pressed  bool
+	prev     bool
+}
+
+// NewActionMap creates an empty action map.
+func NewActionMap() *ActionMap {
+	return &ActionMap{actions: make(map[string]*actionState)}
+}
+
+// Actions returns a scene's action map.
+func (s *Scene) Actions() *ActionMap {
+	return s.actions
+}
+
+// Bind adds bindings to an action, creating it if needed.
+func (m *ActionMap) Bind(action string, bindings ...InputBinding) {
+	a := m.actions[action]
+	if a == nil {
+		a = &actionState{}
+		m.actions[action] = a
+	}
+	a.bindings = append(a.bindings, bindings...)
+}
+
+// Rebind replaces an action's bindings. Use it to apply player remapping
+// at runtime.
+func (m *ActionMap) Rebind(action string, bindings ...InputBinding) {
+	if a := m.actions[action]; a != nil {
+		a.bindings = a.bindings[:0]
+	}
+	m.Bind(action, bindings...)
+}
+
+// Unbind removes an action and its bindings.
+func (m *ActionMap) Unbind(action string) {
+	delete(m.actions, action)
+}
+
+// Bindings returns a copy of an action's bindings.
+func (m *ActionMap) Bindings(action string) []InputBinding {
+	a := m.actions[action]
+	if a == nil {
+		return nil
+	}
+	return append([]InputBinding(nil), a.bindings...)
+}
+
+// Actions returns the names of all bound actions, sorted.
+func (m *ActionMap) Actions() []string {
+	names := make([]string, 0, len(m.actions))
+	for name := range m.actions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Pressed reports whether any of the action's bindings is held. Axis
+// bindings count once pushed past half way.
+func (m *ActionMap) Pressed(action string) bool {
+	a := m.actions[action]
+	return a != nil && a.pressed
+}
+
+// JustPressed reports whether the action became pressed this tick.
+func (m *ActionMap) JustPressed(action string) bool {
+	a := m.actions[action]
+	return a != nil && a.pressed && !a.prev
+}
+
+// JustReleased reports whether the action stopped being pressed this tick.
+func (m *ActionMap) JustReleased(action string) bool {
+	a := m.actions[action]
+	return a != nil && !a.pressed && a.prev
+}
+
+// Value returns the action's value in [-1, 1]: the sum of its bindings'
+// values times their Scale. Held keys and buttons count as 1.
+func (m *ActionMap) Value(action string) float64 {
+	if a := m.actions[action]; a != nil {
+		return a.value
+	}
+	return 0
+}
+
+// Update polls the keyboard and gamepads. The scene calls it each tick, and
+// repeated calls within one tick are ignored, so calling it early (for
+// example from an update func) is safe.
+func (m *ActionMap) Update() {
+	t := ebiten.Tick()
+	if m.polled && t == m.tick {
+		return
+	}
+	m.polled, m.tick = true, t
+	m.pads = ebiten.AppendGamepadIDs(m.pads[:0])
+	m.update(m.read)
+}
+
+// update recomputes every action from read, which returns a binding's
+// current value before Scale.
+func (m *ActionMap) update(read func(InputBinding) float64) {
+	for _, a := range m.actions {
+		a.prev = a.pressed
+		a.pressed = false
+		sum := 0.0
+		for _, b := range a.bindings {
+			v := read(b)
+			if math.Abs(v) >= actionPressThreshold {
+				a.pressed = true
+			}
+			scale := b.Scale
+			if scale == 0 {
+				scale = 1
+			}
+			sum += v * scale
+		}
+		a.value = math.Max(-1, math.Min(sum, 1))
+	}
+}
+
+// read returns a binding's current value from the devices.
+func (m *ActionMap) read(b InputBinding) float64 {
+	if b.Kind == BindingKey {
+		if ebiten.IsKeyPressed(b.Key) {
+			return 1
+		}
+		return 0
+	}
+	best := 0.0
+	for i, id := range m.pads {
+		if (b.Gamepad != AnyGamepad && b.Gamepad != i) || !ebiten.IsStandardGamepadLayoutAvailable(id) {
+			continue
+		}
+		var v float64
+		if b.Kind == BindingGamepadButton {
+			v = ebiten.StandardGamepadButtonValue(id, b.Button)
+		} else {
+			v = applyDeadzone(ebiten.StandardGamepadAxisValue(id, b.Axis), b.Deadzone)
+		}
+		if math.Abs(v) > math.Abs(best) {
+			best = v
+		}
+	}
+	return best
+}
+
+// CaptureBinding returns a binding for the first key or gamepad button
+// pressed this tick, or a stick pushed past half way, for building rebinding
+// menus. It reports false if nothing was pressed.
+func (m *ActionMap) CaptureBinding() (InputBinding, bool) {
+	var keys [4]ebiten.Key
+	if k := inpututil.AppendJustPressedKeys(keys[:0]); len(k) > 0 {
+		return KeyBinding(k[0]), true
+	}
+	for i, id := range ebiten.AppendGamepadIDs(nil) {
+		if !ebiten.IsStandardGamepadLayoutAvailable(id) {
+			continue
+		}
+		for btn := ebiten.StandardGamepadButton(0); btn <= ebiten.StandardGamepadButtonMax; btn++ {
+			if inpututil.IsStandardGamepadButtonJustPressed(id, btn) {
+				return GamepadButtonBinding(btn).OnGamepad(i), true
+			}
+		}
+		for axis := ebiten.StandardGamepadAxis(0); axis <= ebiten.StandardGamepadAxisMax; axis++ {
+			if v := ebiten.StandardGamepadAxisValue(id, axis); math.Abs(v) >= actionPressThreshold {
+				return GamepadAxisBinding(axis, 0.2).Scaled(math.Copysign(1, v)).OnGamepad(i), true
+			}
+		}
+	}
+	return InputBinding{}, false
+}
+
+// --- Serialization ---
+
+var gamepadButtonNames = [...]string{
+	ebiten.StandardGamepadButtonRightBottom:      "RightBottom",
+	ebiten.StandardGamepadButtonRightRight:       "RightRight",
+	ebiten.StandardGamepadButtonRightLeft:        "RightLeft",
+	ebiten.StandardGamepadButtonRightTop:         "RightTop",
+	ebiten.StandardGamepadButtonFrontTopLeft:     "FrontTopLeft",
+	ebiten.StandardGamepadButtonFrontTopRight:    "FrontTopRight",
+	ebiten.StandardGamepadButtonFrontBottomLeft:  "FrontBottomLeft",
+	ebiten.StandardGamepadButtonFrontBottomRight: "FrontBottomRight",
+	ebiten.StandardGamepadButtonCenterLeft:       "CenterLeft",
+	ebiten.StandardGamepadButtonCenterRight:      "CenterRight",
+	ebiten.StandardGamepadButtonLeftStick:        "LeftStick",
+	ebiten.StandardGamepadButtonRightStick:       "RightStick",
+	ebiten.StandardGamepadButtonLeftTop:          "LeftTop",
+	ebiten.StandardGamepadButtonLeftBottom:       "LeftBottom",
+	ebiten.StandardGamepadButtonLeftLeft:         "LeftLeft",
+	ebiten.StandardGamepadButtonLeftRight:        "LeftRight",
+	ebiten.StandardGamepadButtonCenterCenter:     "CenterCenter",
+}
+
+var gamepadAxisNames = [...]string{
+	ebiten.StandardGamepadAxisLeftStickHorizontal:  "LeftStickHorizontal",
+	ebiten.StandardGamepadAxisLeftStickVertical:    "LeftStickVertical",
+	ebiten.StandardGamepadAxisRightStickHorizontal: "RightStickHorizontal",
+	ebiten.StandardGamepadAxisRightStickVertical:   "RightStickVertical",
+}
+
+// bindingJSON is the serialized form of an InputBinding. Exactly one of
+// Key, Button and Axis is set.
+type bindingJSON struct {
+	Key      *ebiten.Key `json:"key,omitempty"`
+	Button   string      `json:"button,omitempty"`
+	Axis     string      `json:"axis,omitempty"`
+	Gamepad  *int        `json:"gamepad,omitempty"`
+	Scale    float64     `json:"scale,omitempty"`
+	Deadzone float64     `json:"deadzone,omitempty"`
+}
+
+// MarshalJSON encodes the bindings as an object mapping action names to
+// binding lists, e.g. {"jump": [{"key": "Space"}, {"button": "RightBottom"}]}.
+// Keys use Ebitengine's key names and gamepad inputs use the standard
+// layout names without their prefix.
+func (m *ActionMap) MarshalJSON() ([]byte, error) {
+	out := make(map[string][]bindingJSON, len(m.actions))
+	for name, a := range m.actions {
+		list := make([]bindingJSON, 0, len(a.bindings))
+		for _, b := range a.bindings {
+			j := bindingJSON{Scale: b.Scale, Deadzone: b.Deadzone}
+			switch b.Kind {
+			case BindingKey:
+				k := b.Key
+				j.Key = &k
+			case BindingGamepadButton:
+				j.Button = gamepadButtonNames[b.Button]
+			case BindingGamepadAxis:
+				j.Axis = gamepadAxisNames[b.Axis]
+			}
+			if b.Kind != BindingKey && b.Gamepad != AnyGamepad {
+				g := b.Gamepad
+				j.Gamepad = &g
+			}
+			list = append(list, j)
+		}
+		out[name] = list
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON replaces the map's bindings with those encoded by
+// MarshalJSON.
+func (m *ActionMap) UnmarshalJSON(data []byte) error {
+	var in map[string][]bindingJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return fmt.Errorf("willow: failed to parse action bindings: %w", err)
+	}
+	actions := make(map[string]*actionState, len(in))
+	for name, list := range in {
+		a := &actionState{}
+		for _, j := range list {
+			b := InputBinding{Gamepad: AnyGamepad, Scale: j.Scale, Deadzone: j.Deadzone}
+			switch {
+			case j.Key != nil:
+				b.Kind, b.Key = BindingKey, *j.Key
+			case j.Button != "":
+				i := indexOf(gamepadButtonNames[:], j.Button)
+				if i < 0 {
+					return fmt.Errorf("willow: action %q: unknown gamepad button %q", name, j.Button)
+				}
+				b.Kind, b.Button = BindingGamepadButton, ebiten.StandardGamepadButton(i)
+			case j.Axis != "":
+				i := indexOf(gamepadAxisNames[:], j.Axis)
+				if i < 0 {
+					return fmt.Errorf("willow: action %q: unknown gamepad axis %q", name, j.Axis)
+				}
+				b.Kind, b.Axis = BindingGamepadAxis, ebiten.StandardGamepadAxis(i)
+			default:
+				return fmt.Errorf("willow: action %q: binding needs a key, button or axis", name)
+			}
+			if j.Gamepad != nil {
+				b.Gamepad = *j.Gamepad
+			}
+			a.bindings = append(a.bindings, b)
+		}
+		actions[name] = a
+	}
+	m.actions = actions
+	return nil
+}
+
+func indexOf(names []string, name string) int {
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
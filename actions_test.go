@@ -0,0 +1,123 @@
+package willow
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// fakeInput returns a read func backed by a map of binding values.
+func fakeInput(vals map[InputBinding]float64) func(InputBinding) float64 {
+	return func(b InputBinding) float64 {
+		b.Scale = 0
+		return vals[b]
+	}
+}
+
+func TestActionMapPressedStates(t *testing.T) {
+	m := NewActionMap()
+	m.Bind("jump", KeyBinding(ebiten.KeySpace), GamepadButtonBinding(ebiten.StandardGamepadButtonRightBottom))
+	vals := map[InputBinding]float64{}
+	read := fakeInput(vals)
+
+	m.update(read)
+	if m.Pressed("jump") || m.JustPressed("jump") || m.JustReleased("jump") {
+		t.Fatal("idle action reported active")
+	}
+
+	vals[GamepadButtonBinding(ebiten.StandardGamepadButtonRightBottom)] = 1
+	m.update(read)
+	if !m.Pressed("jump") || !m.JustPressed("jump") {
+		t.Error("button press not reported")
+	}
+	m.update(read)
+	if !m.Pressed("jump") || m.JustPressed("jump") {
+		t.Error("held action should be pressed but not just pressed")
+	}
+
+	delete(vals, GamepadButtonBinding(ebiten.StandardGamepadButtonRightBottom))
+	m.update(read)
+	if m.Pressed("jump") || !m.JustReleased("jump") {
+		t.Error("release not reported")
+	}
+	if m.Pressed("missing") || m.Value("missing") != 0 {
+		t.Error("unknown action should be inactive")
+	}
+}
+
+func TestActionMapValueAndDeadzone(t *testing.T) {
+	m := NewActionMap()
+	stick := GamepadAxisBinding(ebiten.StandardGamepadAxisLeftStickHorizontal, 0.2)
+	m.Bind("move_x",
+		KeyBinding(ebiten.KeyA).Scaled(-1),
+		KeyBinding(ebiten.KeyD),
+		stick,
+	)
+	vals := map[InputBinding]float64{KeyBinding(ebiten.KeyA): 1}
+	m.update(fakeInput(vals))
+	if m.Value("move_x") != -1 || !m.Pressed("move_x") {
+		t.Errorf("A: value = %f, want -1", m.Value("move_x"))
+	}
+
+	vals[KeyBinding(ebiten.KeyD)] = 1
+	m.update(fakeInput(vals))
+	if m.Value("move_x") != 0 {
+		t.Errorf("A+D: value = %f, want 0", m.Value("move_x"))
+	}
+
+	// read applies the deadzone for real devices; mirror it here.
+	clear(vals)
+	vals[stick] = applyDeadzone(0.8, 0.2)
+	m.update(fakeInput(vals))
+	if !approxEqual(m.Value("move_x"), 0.75, 1e-9) || !m.Pressed("move_x") {
+		t.Errorf("stick: value = %f, want 0.75", m.Value("move_x"))
+	}
+	if applyDeadzone(0.15, 0.2) != 0 || applyDeadzone(-1, 0.2) != -1 {
+		t.Error("applyDeadzone did not clip and rescale")
+	}
+}
+
+func TestActionMapRebind(t *testing.T) {
+	m := NewActionMap()
+	m.Bind("fire", KeyBinding(ebiten.KeyZ))
+	m.Rebind("fire", KeyBinding(ebiten.KeyX))
+	if got := m.Bindings("fire"); len(got) != 1 || got[0].Key != ebiten.KeyX {
+		t.Errorf("Bindings after Rebind = %v", got)
+	}
+	m.Unbind("fire")
+	if m.Bindings("fire") != nil || len(m.Actions()) != 0 {
+		t.Error("Unbind left the action")
+	}
+}
+
+func TestActionMapJSONRoundTrip(t *testing.T) {
+	m := NewActionMap()
+	m.Bind("jump", KeyBinding(ebiten.KeySpace), GamepadButtonBinding(ebiten.StandardGamepadButtonRightBottom).OnGamepad(1))
+	m.Bind("move_x", KeyBinding(ebiten.KeyArrowLeft).Scaled(-1),
+		GamepadAxisBinding(ebiten.StandardGamepadAxisLeftStickHorizontal, 0.25))
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"button":"RightBottom"`) || !strings.Contains(string(data), `"axis":"LeftStickHorizontal"`) {
+		t.Errorf("unexpected encoding %s", data)
+	}
+
+	got := NewActionMap()
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range m.Actions() {
+		if !reflect.DeepEqual(got.Bindings(name), m.Bindings(name)) {
+			t.Errorf("%s: got %+v, want %+v", name, got.Bindings(name), m.Bindings(name))
+		}
+	}
+
+	if err := json.Unmarshal([]byte(`{"jump":[{"button":"Nope"}]}`), got); err == nil {
+		t.Error("expected error for unknown button")
+	}
+}
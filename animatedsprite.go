@@ -0,0 +1,257 @@
+package willow
+
+import "log"
+
+// AnimLoopMode controls what an AnimatedSprite does when a clip reaches its
+// last frame.
+type AnimLoopMode uint8
+
+const (
+	// AnimLoop restarts the clip from the first frame.
+	AnimLoop AnimLoopMode = iota
+	// AnimPingPong reverses direction at each end of the clip.
+	AnimPingPong
+	// AnimOnce stops on the last frame and fires OnComplete.
+	AnimOnce
+)
+
+// SpriteFrame is a single frame in an AnimClip.
+type SpriteFrame struct {
+	Region   TextureRegion // atlas region displayed for this frame
+	Duration float64       // seconds the frame stays on screen
+}
+
+// AnimClip is a named sequence of sprite frames with a playback mode.
+type AnimClip struct {
+	Name   string
+	Frames []SpriteFrame
+	Mode   AnimLoopMode
+}
+
+// NewAnimClip builds a clip from named atlas regions, each shown for
+// frameDuration seconds. Missing names resolve to the magenta placeholder,
+// the same as Atlas.Region.
+func NewAnimClip(name string, atlas *Atlas, regionNames []string, frameDuration float64, mode AnimLoopMode) AnimClip {
+	frames := make([]SpriteFrame, len(regionNames))
+	for i, rn := range regionNames {
+		frames[i] = SpriteFrame{Region: atlas.Region(rn), Duration: frameDuration}
+	}
+	return AnimClip{Name: name, Frames: frames, Mode: mode}
+}
+
+// AnimatedSprite plays frame-by-frame clips on a sprite node. Playback is
+// advanced during Scene.Update, so the node must be part of the scene tree.
+// The node's OnUpdate stays free for other uses.
+type AnimatedSprite struct {
+	node  *Node
+	clips map[string]AnimClip
+
+	clip    AnimClip // currently selected clip (zero value if none)
+	frame   int      // index into clip.Frames
+	dir     int      // +1 forward, -1 backward (ping-pong only)
+	elapsed float64  // seconds spent on the current frame
+	playing bool
+
+	// Speed scales playback time. 1 is normal speed, 2 is double speed and
+	// 0 freezes the animation. Negative values are treated as 0.
+	Speed float64
+
+	// OnFrame fires each time playback advances to a new frame.
+	OnFrame func(clip string, frame int)
+	// OnLoop fires each time a looping or ping-pong clip completes a cycle.
+	OnLoop func(clip string)
+	// OnComplete fires when an AnimOnce clip finishes on its last frame.
+	OnComplete func(clip string)
+}
+
+// NewAnimatedSprite creates a sprite node driven by the given clips. Nothing
+// plays until Play is called; the node shows the first frame of the first
+// clip (if any) so it has a valid size from the start.
+func NewAnimatedSprite(name string, clips ...AnimClip) *AnimatedSprite {
+	a := &AnimatedSprite{
+		clips: make(map[string]AnimClip, len(clips)),
+		dir:   1,
+		Speed: 1,
+	}
+	var initial TextureRegion
+	for _, c := range clips {
+		a.clips[c.Name] = c
+	}
+	if len(clips) > 0 && len(clips[0].Frames) > 0 {
+		initial = clips[0].Frames[0].Region
+	}
+	a.node = NewSprite(name, initial)
+	a.node.update = a.update
+	return a
+}
+
+// Node returns the sprite node that displays the animation.
+func (a *AnimatedSprite) Node() *Node {
+	return a.node
+}
+
+// AddClip registers a clip, replacing any existing clip with the same name.
+func (a *AnimatedSprite) AddClip(c AnimClip) {
+	a.clips[c.Name] = c
+	if a.clip.Name != c.Name || a.clip.Frames == nil {
+		return
+	}
+	// Replacing the current clip: keep the playhead where it was if possible.
+	a.clip = c
+	if len(c.Frames) == 0 {
+		a.playing = false
+		a.frame = 0
+		return
+	}
+	a.setFrame(min(a.frame, len(c.Frames)-1))
+}
+
+// HasClip reports whether a clip with the given name is registered.
+func (a *AnimatedSprite) HasClip(name string) bool {
+	_, ok := a.clips[name]
+	return ok
+}
+
+// Play starts the named clip from its first frame. Calling Play with the clip
+// that is already playing is a no-op, so it is safe to call every frame from
+// game logic. Unknown names are ignored (with a warning in debug mode).
+func (a *AnimatedSprite) Play(name string) {
+	if a.playing && a.clip.Name == name {
+		return
+	}
+	a.PlayFrom(name, 0)
+}
+
+// PlayFrom starts the named clip at the given frame index, restarting it even
+// if it is already playing.
+func (a *AnimatedSprite) PlayFrom(name string, frame int) {
+	c, ok := a.clips[name]
+	if !ok || len(c.Frames) == 0 {
+		if globalDebug {
+			log.Printf("willow: animated sprite %q has no clip %q", a.node.Name, name)
+		}
+		return
+	}
+	a.clip = c
+	a.dir = 1
+	a.elapsed = 0
+	a.playing = true
+	a.setFrame(min(max(frame, 0), len(c.Frames)-1))
+}
+
+// Stop halts playback and rewinds to the first frame of the current clip.
+func (a *AnimatedSprite) Stop() {
+	a.playing = false
+	a.elapsed = 0
+	a.dir = 1
+	if len(a.clip.Frames) > 0 {
+		a.setFrame(0)
+	}
+}
+
+// Pause halts playback on the current frame. Resume continues from there.
+func (a *AnimatedSprite) Pause() {
+	a.playing = false
+}
+
+// Resume continues playback of the current clip after Pause.
+func (a *AnimatedSprite) Resume() {
+	if len(a.clip.Frames) > 0 {
+		a.playing = true
+	}
+}
+
+// IsPlaying reports whether a clip is currently advancing.
+func (a *AnimatedSprite) IsPlaying() bool {
+	return a.playing
+}
+
+// Clip returns the name of the current clip, or "" if none has been played.
+func (a *AnimatedSprite) Clip() string {
+	return a.clip.Name
+}
+
+// Frame returns the index of the displayed frame within the current clip.
+func (a *AnimatedSprite) Frame() int {
+	return a.frame
+}
+
+// SetFrame jumps to the given frame of the current clip without changing the
+// playing state. Out-of-range indices are clamped.
+func (a *AnimatedSprite) SetFrame(frame int) {
+	if len(a.clip.Frames) == 0 {
+		return
+	}
+	a.elapsed = 0
+	a.setFrame(min(max(frame, 0), len(a.clip.Frames)-1))
+}
+
+// setFrame displays frame i of the current clip.
+func (a *AnimatedSprite) setFrame(i int) {
+	a.frame = i
+	a.node.SetTextureRegion(a.clip.Frames[i].Region)
+}
+
+// update advances playback by dt seconds. Called from Scene.Update.
+func (a *AnimatedSprite) update(dt float64) {
+	if !a.playing || a.Speed <= 0 {
+		return
+	}
+	a.elapsed += dt * a.Speed
+
+	// Zero-duration frames hold until changed explicitly; otherwise elapsed
+	// shrinks every step, so the loop always terminates. Callbacks may switch
+	// clips, so the frame list is re-read on every step.
+	for a.playing {
+		dur := a.clip.Frames[a.frame].Duration
+		if dur <= 0 || a.elapsed < dur {
+			return
+		}
+		a.elapsed -= dur
+		a.advance()
+	}
+}
+
+// advance moves to the next frame according to the clip's loop mode and
+// fires the matching callbacks.
+func (a *AnimatedSprite) advance() {
+	n := len(a.clip.Frames)
+	name := a.clip.Name
+	next := a.frame + a.dir
+	switch a.clip.Mode {
+	case AnimOnce:
+		if next >= n {
+			a.playing = false
+			a.elapsed = 0
+			if a.OnComplete != nil {
+				a.OnComplete(name)
+			}
+			return
+		}
+	case AnimPingPong:
+		if n == 1 {
+			next = 0
+			break
+		}
+		if next >= n {
+			a.dir = -1
+			next = n - 2
+		} else if next < 0 {
+			a.dir = 1
+			next = 1
+		}
+	default:
+		if next >= n {
+			next = 0
+		}
+	}
+
+	a.setFrame(next)
+	if a.OnFrame != nil {
+		a.OnFrame(name, next)
+	}
+	// A ping-pong cycle ends when playback returns to the first frame.
+	if next == 0 && a.clip.Mode != AnimOnce && a.OnLoop != nil {
+		a.OnLoop(name)
+	}
+}
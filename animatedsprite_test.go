@@ -0,0 +1,223 @@
+package willow
+
+import "testing"
+
+// testClip builds a clip whose frames are distinguishable by region X.
+func testClip(name string, n int, dur float64, mode AnimLoopMode) AnimClip {
+	frames := make([]SpriteFrame, n)
+	for i := range frames {
+		frames[i] = SpriteFrame{
+			Region:   TextureRegion{X: uint16(i * 16), Width: 16, Height: 16, OriginalW: 16, OriginalH: 16},
+			Duration: dur,
+		}
+	}
+	return AnimClip{Name: name, Frames: frames, Mode: mode}
+}
+
+func TestNewAnimatedSpriteShowsFirstFrame(t *testing.T) {
+	a := NewAnimatedSprite("hero", testClip("idle", 3, 0.1, AnimLoop))
+	n := a.Node()
+	if n.Type != NodeTypeSprite {
+		t.Fatalf("Type = %d, want NodeTypeSprite", n.Type)
+	}
+	if n.TextureRegion.Width != 16 {
+		t.Errorf("initial region width = %d, want 16", n.TextureRegion.Width)
+	}
+	if n.update == nil || n.OnUpdate != nil {
+		t.Error("playback should be driven internally, leaving OnUpdate free")
+	}
+	if a.IsPlaying() {
+		t.Error("should not play until Play is called")
+	}
+}
+
+func TestAnimatedSpriteLoop(t *testing.T) {
+	a := NewAnimatedSprite("hero", testClip("walk", 3, 0.1, AnimLoop))
+	loops := 0
+	a.OnLoop = func(string) { loops++ }
+	a.Play("walk")
+
+	a.update(0.1)
+	if a.Frame() != 1 {
+		t.Fatalf("frame = %d, want 1", a.Frame())
+	}
+	if a.Node().TextureRegion.X != 16 {
+		t.Errorf("region X = %d, want 16", a.Node().TextureRegion.X)
+	}
+	a.update(0.1)
+	a.update(0.1)
+	if a.Frame() != 0 {
+		t.Errorf("frame = %d after wrap, want 0", a.Frame())
+	}
+	if loops != 1 {
+		t.Errorf("OnLoop fired %d times, want 1", loops)
+	}
+}
+
+func TestAnimatedSpritePingPong(t *testing.T) {
+	a := NewAnimatedSprite("hero", testClip("bob", 3, 0.1, AnimPingPong))
+	a.Play("bob")
+
+	var got []int
+	for i := 0; i < 6; i++ {
+		a.update(0.1)
+		got = append(got, a.Frame())
+	}
+	want := []int{1, 2, 1, 0, 1, 2}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("frames = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestAnimatedSpriteOnceCompletes(t *testing.T) {
+	a := NewAnimatedSprite("hero", testClip("attack", 2, 0.1, AnimOnce))
+	var completed string
+	a.OnComplete = func(name string) { completed = name }
+	a.Play("attack")
+
+	a.update(0.1)
+	a.update(0.1)
+	if a.IsPlaying() {
+		t.Error("one-shot clip should stop after the last frame")
+	}
+	if a.Frame() != 1 {
+		t.Errorf("frame = %d, want to hold last frame 1", a.Frame())
+	}
+	if completed != "attack" {
+		t.Errorf("OnComplete clip = %q, want attack", completed)
+	}
+
+	// Further updates are no-ops.
+	a.update(1)
+	if a.Frame() != 1 {
+		t.Errorf("frame changed after completion: %d", a.Frame())
+	}
+}
+
+func TestAnimatedSpriteOnFrameAndLargeDt(t *testing.T) {
+	a := NewAnimatedSprite("hero", testClip("run", 4, 0.1, AnimLoop))
+	var frames []int
+	a.OnFrame = func(_ string, f int) { frames = append(frames, f) }
+	a.Play("run")
+
+	// One tick spanning 2.5 frames advances twice and keeps the remainder.
+	a.update(0.25)
+	if len(frames) != 2 || frames[0] != 1 || frames[1] != 2 {
+		t.Fatalf("OnFrame = %v, want [1 2]", frames)
+	}
+	a.update(0.06)
+	if a.Frame() != 3 {
+		t.Errorf("frame = %d, want 3 (remainder carried over)", a.Frame())
+	}
+}
+
+func TestAnimatedSpriteSpeed(t *testing.T) {
+	a := NewAnimatedSprite("hero", testClip("run", 4, 0.1, AnimLoop))
+	a.Play("run")
+
+	a.Speed = 2
+	a.update(0.05)
+	if a.Frame() != 1 {
+		t.Errorf("frame = %d at 2x speed, want 1", a.Frame())
+	}
+
+	a.Speed = 0
+	a.update(10)
+	if a.Frame() != 1 {
+		t.Errorf("frame = %d at zero speed, want 1", a.Frame())
+	}
+}
+
+func TestAnimatedSpritePlaySameClipIsNoop(t *testing.T) {
+	a := NewAnimatedSprite("hero", testClip("walk", 3, 0.1, AnimLoop), testClip("idle", 2, 0.2, AnimLoop))
+	a.Play("walk")
+	a.update(0.1)
+	a.Play("walk")
+	if a.Frame() != 1 {
+		t.Errorf("Play(same) restarted the clip: frame = %d", a.Frame())
+	}
+
+	a.Play("idle")
+	if a.Clip() != "idle" || a.Frame() != 0 {
+		t.Errorf("Play(idle): clip=%q frame=%d", a.Clip(), a.Frame())
+	}
+
+	a.Play("missing")
+	if a.Clip() != "idle" {
+		t.Errorf("unknown clip should be ignored, clip = %q", a.Clip())
+	}
+}
+
+func TestAnimatedSpritePauseResumeStop(t *testing.T) {
+	a := NewAnimatedSprite("hero", testClip("walk", 3, 0.1, AnimLoop))
+	a.Play("walk")
+	a.update(0.1)
+
+	a.Pause()
+	a.update(0.5)
+	if a.Frame() != 1 {
+		t.Errorf("frame = %d while paused, want 1", a.Frame())
+	}
+
+	a.Resume()
+	a.update(0.1)
+	if a.Frame() != 2 {
+		t.Errorf("frame = %d after resume, want 2", a.Frame())
+	}
+
+	a.Stop()
+	if a.IsPlaying() || a.Frame() != 0 {
+		t.Errorf("Stop: playing=%v frame=%d", a.IsPlaying(), a.Frame())
+	}
+}
+
+func TestAnimatedSpriteZeroDurationDoesNotSpin(t *testing.T) {
+	a := NewAnimatedSprite("hero", testClip("bad", 3, 0, AnimLoop))
+	a.Play("bad")
+	a.update(1) // must return
+	if a.Frame() != 0 {
+		t.Errorf("frame = %d, want 0 (zero-duration frames hold)", a.Frame())
+	}
+}
+
+func TestAnimatedSpriteAdvancesInSceneUpdate(t *testing.T) {
+	s := NewScene()
+	a := NewAnimatedSprite("hero", testClip("walk", 2, 0.01, AnimLoop))
+	s.Root().AddChild(a.Node())
+	a.Play("walk")
+
+	s.Update()
+	if a.Frame() != 1 {
+		t.Errorf("frame = %d after Scene.Update, want 1", a.Frame())
+	}
+}
+
+func TestNewAnimClipFromAtlas(t *testing.T) {
+	atlas, err := LoadAtlas([]byte(`{"frames":{
+		"walk_0":{"frame":{"x":0,"y":0,"w":8,"h":8},"sourceSize":{"w":8,"h":8},"spriteSourceSize":{"x":0,"y":0,"w":8,"h":8}},
+		"walk_1":{"frame":{"x":8,"y":0,"w":8,"h":8},"sourceSize":{"w":8,"h":8},"spriteSourceSize":{"x":0,"y":0,"w":8,"h":8}}
+	}}`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewAnimClip("walk", atlas, []string{"walk_0", "walk_1"}, 0.1, AnimLoop)
+	if len(c.Frames) != 2 {
+		t.Fatalf("frames = %d, want 2", len(c.Frames))
+	}
+	if c.Frames[1].Region.X != 8 || c.Frames[1].Duration != 0.1 {
+		t.Errorf("frame 1 = %+v", c.Frames[1])
+	}
+}
+
+func TestAnimatedSpriteKeepsPlayingWithOnUpdate(t *testing.T) {
+	a := NewAnimatedSprite("hero", testClip("walk", 3, 0.1, AnimLoop))
+	var ticks int
+	a.Node().OnUpdate = func(float64) { ticks++ }
+	a.Play("walk")
+	updateNodesAndParticles(a.Node(), 0.15)
+	if ticks != 1 || a.Frame() != 1 {
+		t.Errorf("ticks = %d, frame = %d; want 1, 1", ticks, a.Frame())
+	}
+}
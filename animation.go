@@ -1,22 +1,56 @@
 package willow
 
 import (
-	"github.com/tanema/gween"
+	"log"
+
 	"github.com/tanema/gween/ease"
 )
 
-// TweenGroup animates up to 4 float64 fields on a Node simultaneously.
-// Create one via the convenience constructors (TweenPosition, TweenScale,
-// TweenColor) and call Update(dt) each frame. The group auto-applies values
-// and marks the node dirty. If the target node is disposed, the group stops
-// immediately.
+// tweenChannel is one value animated by a TweenGroup. Values are written
+// through field when it is set, otherwise through the get/set pair.
+type tweenChannel struct {
+	field    *float64
+	get      func() float64
+	set      func(float64)
+	from, to float64
+}
+
+func (c *tweenChannel) value() float64 {
+	if c.field != nil {
+		return *c.field
+	}
+	return c.get()
+}
+
+func (c *tweenChannel) write(v float64) {
+	if c.field != nil {
+		*c.field = v
+	} else {
+		c.set(v)
+	}
+}
+
+// TweenGroup animates any number of float64 values simultaneously. Create
+// one via the convenience constructors (TweenPosition, TweenScale,
+// TweenColor, TweenFloat, TweenAccessor, ...) and call Update(dt) each
+// frame. The group auto-applies values and marks the target node dirty. If
+// the target node is disposed, the group stops immediately.
+//
+// Either call Update yourself or register the group with the scene's
+// runner via Scene.Tweens, which ticks it during Scene.Update.
 //
-// There is no global animation manager — users call Update themselves.
+// Easing functions work in float32, but only to compute the eased
+// progress; values are interpolated in float64 so large world coordinates
+// keep their precision.
 type TweenGroup struct {
-	tweens [4]*gween.Tween
-	count  int
-	fields [4]*float64
-	target *Node
+	channels []tweenChannel
+	target   *Node
+	apply    func() // called after each write, e.g. to mark a camera dirty
+
+	elapsed  float32
+	duration float32
+	fn       ease.TweenFunc
+
 	// Done is true when all tweens in the group have finished or the target
 	// node has been disposed.
 	Done bool
@@ -35,72 +69,178 @@ func (g *TweenGroup) Update(dt float32) {
 		return
 	}
 
-	allDone := true
-	for i := 0; i < g.count; i++ {
-		val, finished := g.tweens[i].Update(dt)
-		*g.fields[i] = float64(val)
-		if !finished {
-			allDone = false
+	g.elapsed += dt
+	g.seek(g.elapsed)
+	g.Done = g.elapsed >= g.duration
+}
+
+// seek sets every tween to time t seconds and writes the values. No writes
+// occur once the target node is disposed.
+func (g *TweenGroup) seek(t float32) {
+	if g.target != nil && g.target.IsDisposed() {
+		return
+	}
+	var p float64
+	switch {
+	case t <= 0:
+		p = 0
+	case t >= g.duration:
+		p = 1
+	default:
+		p = float64(g.fn(t, 0, 1, g.duration))
+	}
+	for i := range g.channels {
+		c := &g.channels[i]
+		if p == 1 {
+			c.write(c.to)
+		} else {
+			c.write(c.from + (c.to-c.from)*p)
 		}
 	}
-	g.Done = allDone
-
 	if g.target != nil {
 		g.target.Invalidate()
 	}
+	if g.apply != nil {
+		g.apply()
+	}
+}
+
+// rebase restarts the group from the current values.
+func (g *TweenGroup) rebase() {
+	for i := range g.channels {
+		g.channels[i].from = g.channels[i].value()
+	}
+}
+
+// newTweenGroup creates a TweenGroup animating fields from their current
+// values to the matching to values.
+func newTweenGroup(node *Node, duration float32, fn ease.TweenFunc, fields []*float64, to []float64) *TweenGroup {
+	g := &TweenGroup{channels: make([]tweenChannel, len(fields)), target: node, duration: duration, fn: fn}
+	for i, f := range fields {
+		g.channels[i] = tweenChannel{field: f, from: *f, to: to[i]}
+	}
+	return g
 }
 
 // TweenPosition creates a TweenGroup that animates node.X and node.Y to the
 // given target coordinates over the specified duration using the easing function.
 func TweenPosition(node *Node, toX, toY float64, duration float32, fn ease.TweenFunc) *TweenGroup {
-	g := &TweenGroup{count: 2, target: node}
-	g.tweens[0] = gween.New(float32(node.X), float32(toX), duration, fn)
-	g.tweens[1] = gween.New(float32(node.Y), float32(toY), duration, fn)
-	g.fields[0] = &node.X
-	g.fields[1] = &node.Y
-	return g
+	return newTweenGroup(node, duration, fn, []*float64{&node.X, &node.Y}, []float64{toX, toY})
 }
 
 // TweenScale creates a TweenGroup that animates node.ScaleX and node.ScaleY to
 // the given target values over the specified duration using the easing function.
 func TweenScale(node *Node, toSX, toSY float64, duration float32, fn ease.TweenFunc) *TweenGroup {
-	g := &TweenGroup{count: 2, target: node}
-	g.tweens[0] = gween.New(float32(node.ScaleX), float32(toSX), duration, fn)
-	g.tweens[1] = gween.New(float32(node.ScaleY), float32(toSY), duration, fn)
-	g.fields[0] = &node.ScaleX
-	g.fields[1] = &node.ScaleY
-	return g
+	return newTweenGroup(node, duration, fn, []*float64{&node.ScaleX, &node.ScaleY}, []float64{toSX, toSY})
 }
 
 // TweenColor creates a TweenGroup that animates all four components of
 // node.Color (R, G, B, A) to the target color over the specified duration.
 func TweenColor(node *Node, to Color, duration float32, fn ease.TweenFunc) *TweenGroup {
-	g := &TweenGroup{count: 4, target: node}
-	g.tweens[0] = gween.New(float32(node.Color.R), float32(to.R), duration, fn)
-	g.tweens[1] = gween.New(float32(node.Color.G), float32(to.G), duration, fn)
-	g.tweens[2] = gween.New(float32(node.Color.B), float32(to.B), duration, fn)
-	g.tweens[3] = gween.New(float32(node.Color.A), float32(to.A), duration, fn)
-	g.fields[0] = &node.Color.R
-	g.fields[1] = &node.Color.G
-	g.fields[2] = &node.Color.B
-	g.fields[3] = &node.Color.A
-	return g
+	return newTweenGroup(node, duration, fn,
+		[]*float64{&node.Color.R, &node.Color.G, &node.Color.B, &node.Color.A},
+		[]float64{to.R, to.G, to.B, to.A})
 }
 
 // TweenAlpha creates a TweenGroup that animates node.Alpha to the target value
 // over the specified duration using the easing function.
 func TweenAlpha(node *Node, to float64, duration float32, fn ease.TweenFunc) *TweenGroup {
-	g := &TweenGroup{count: 1, target: node}
-	g.tweens[0] = gween.New(float32(node.Alpha), float32(to), duration, fn)
-	g.fields[0] = &node.Alpha
-	return g
+	return newTweenGroup(node, duration, fn, []*float64{&node.Alpha}, []float64{to})
 }
 
 // TweenRotation creates a TweenGroup that animates node.Rotation to the target
 // value over the specified duration using the easing function.
 func TweenRotation(node *Node, to float64, duration float32, fn ease.TweenFunc) *TweenGroup {
-	g := &TweenGroup{count: 1, target: node}
-	g.tweens[0] = gween.New(float32(node.Rotation), float32(to), duration, fn)
-	g.fields[0] = &node.Rotation
+	return newTweenGroup(node, duration, fn, []*float64{&node.Rotation}, []float64{to})
+}
+
+// TweenSkew creates a TweenGroup that animates node.SkewX and node.SkewY to
+// the target shear angles in radians.
+func TweenSkew(node *Node, toX, toY float64, duration float32, fn ease.TweenFunc) *TweenGroup {
+	return newTweenGroup(node, duration, fn, []*float64{&node.SkewX, &node.SkewY}, []float64{toX, toY})
+}
+
+// TweenPivot creates a TweenGroup that animates node.PivotX and node.PivotY
+// to the target local coordinates.
+func TweenPivot(node *Node, toX, toY float64, duration float32, fn ease.TweenFunc) *TweenGroup {
+	return newTweenGroup(node, duration, fn, []*float64{&node.PivotX, &node.PivotY}, []float64{toX, toY})
+}
+
+// TweenFloat creates a TweenGroup that animates any float64 field to the
+// target value. If node is non-nil, it is marked dirty after each write and
+// the tween stops when it is disposed.
+func TweenFloat(node *Node, field *float64, to float64, duration float32, fn ease.TweenFunc) *TweenGroup {
+	return newTweenGroup(node, duration, fn, []*float64{field}, []float64{to})
+}
+
+// TweenAccessor creates a TweenGroup that animates a value exposed through
+// a getter/setter pair, starting from get() and calling set with each new
+// value.
+func TweenAccessor(get func() float64, set func(float64), to float64, duration float32, fn ease.TweenFunc) *TweenGroup {
+	return &TweenGroup{
+		channels: []tweenChannel{{get: get, set: set, from: get(), to: to}},
+		duration: duration,
+		fn:       fn,
+	}
+}
+
+// TweenCameraZoom creates a TweenGroup that animates cam.Zoom.
+func TweenCameraZoom(cam *Camera, to float64, duration float32, fn ease.TweenFunc) *TweenGroup {
+	g := newTweenGroup(nil, duration, fn, []*float64{&cam.Zoom}, []float64{to})
+	g.apply = cam.Invalidate
+	return g
+}
+
+// TweenCameraRotation creates a TweenGroup that animates cam.Rotation in
+// radians.
+func TweenCameraRotation(cam *Camera, to float64, duration float32, fn ease.TweenFunc) *TweenGroup {
+	g := newTweenGroup(nil, duration, fn, []*float64{&cam.Rotation}, []float64{to})
+	g.apply = cam.Invalidate
+	return g
+}
+
+// TweenLightRadius creates a TweenGroup that animates light.Radius.
+func TweenLightRadius(light *Light, to float64, duration float32, fn ease.TweenFunc) *TweenGroup {
+	return newTweenGroup(nil, duration, fn, []*float64{&light.Radius}, []float64{to})
+}
+
+// TweenLightIntensity creates a TweenGroup that animates light.Intensity.
+func TweenLightIntensity(light *Light, to float64, duration float32, fn ease.TweenFunc) *TweenGroup {
+	return newTweenGroup(nil, duration, fn, []*float64{&light.Intensity}, []float64{to})
+}
+
+// TweenBrightness creates a TweenGroup that animates a ColorMatrixFilter's
+// brightness, starting from the value last passed to SetBrightness.
+func TweenBrightness(f *ColorMatrixFilter, to float64, duration float32, fn ease.TweenFunc) *TweenGroup {
+	return TweenAccessor(f.Brightness, f.SetBrightness, to, duration, fn)
+}
+
+// TweenContrast creates a TweenGroup that animates a ColorMatrixFilter's
+// contrast, starting from the value last passed to SetContrast.
+func TweenContrast(f *ColorMatrixFilter, to float64, duration float32, fn ease.TweenFunc) *TweenGroup {
+	return TweenAccessor(f.Contrast, f.SetContrast, to, duration, fn)
+}
+
+// TweenSaturation creates a TweenGroup that animates a ColorMatrixFilter's
+// saturation, starting from the value last passed to SetSaturation.
+func TweenSaturation(f *ColorMatrixFilter, to float64, duration float32, fn ease.TweenFunc) *TweenGroup {
+	return TweenAccessor(f.Saturation, f.SetSaturation, to, duration, fn)
+}
+
+// TweenTextColor creates a TweenGroup that animates the fill color of a
+// text node's TextBlock. If node has no TextBlock the returned group is
+// already Done and animates nothing.
+func TweenTextColor(node *Node, to Color, duration float32, fn ease.TweenFunc) *TweenGroup {
+	tb := node.TextBlock
+	if tb == nil {
+		if globalDebug {
+			log.Printf("willow: TweenTextColor on node %q without a TextBlock", node.Name)
+		}
+		return &TweenGroup{target: node, duration: duration, fn: fn, Done: true}
+	}
+	g := newTweenGroup(node, duration, fn,
+		[]*float64{&tb.Color.R, &tb.Color.G, &tb.Color.B, &tb.Color.A},
+		[]float64{to.R, to.G, to.B, to.A})
+	g.apply = func() { tb.ttfDirty = true }
 	return g
 }
@@ -240,3 +240,82 @@ func TestTweenGroupUpdateZeroAlloc(t *testing.T) {
 		t.Errorf("TweenGroup.Update allocated %f times per run, want 0", result)
 	}
 }
+
+func TestTweenKeepsFloat64Precision(t *testing.T) {
+	node := NewContainer("far")
+	node.X = 1e7 + 0.25
+	g := TweenPosition(node, 1e7+0.75, 0, 1.0, ease.Linear)
+
+	g.Update(0.5)
+	if math.Abs(node.X-(1e7+0.5)) > 1e-6 {
+		t.Errorf("X = %f, want 10000000.5", node.X)
+	}
+	g.Update(0.5)
+	if node.X != 1e7+0.75 {
+		t.Errorf("X = %f, want exactly 10000000.75", node.X)
+	}
+}
+
+func TestTweenFloatAndAccessor(t *testing.T) {
+	node := NewContainer("skew")
+	g := TweenSkew(node, 1, 2, 1.0, ease.Linear)
+	g.Update(1)
+	if node.SkewX != 1 || node.SkewY != 2 {
+		t.Errorf("skew = %f,%f, want 1,2", node.SkewX, node.SkewY)
+	}
+
+	var v float64 = 10
+	TweenFloat(nil, &v, 20, 1.0, ease.Linear).Update(0.5)
+	if v != 15 {
+		t.Errorf("TweenFloat = %f, want 15", v)
+	}
+
+	val := 4.0
+	var sets int
+	a := TweenAccessor(func() float64 { return val }, func(x float64) { val = x; sets++ }, 0, 1.0, ease.Linear)
+	a.Update(0.25)
+	if val != 3 || sets != 1 {
+		t.Errorf("accessor = %f after %d sets, want 3 after 1", val, sets)
+	}
+}
+
+func TestTweenCameraAndFilter(t *testing.T) {
+	cam := newCamera(Rect{Width: 100, Height: 100})
+	cam.computeViewMatrix()
+	TweenCameraZoom(cam, 2, 1.0, ease.Linear).Update(1)
+	if cam.Zoom != 2 || !cam.dirty {
+		t.Errorf("Zoom = %f dirty = %v, want 2 and dirty", cam.Zoom, cam.dirty)
+	}
+
+	f := NewColorMatrixFilter()
+	TweenSaturation(f, 0, 1.0, ease.Linear).Update(0.5)
+	if f.Saturation() != 0.5 {
+		t.Errorf("Saturation = %f, want 0.5", f.Saturation())
+	}
+	TweenBrightness(f, 0.2, 1.0, ease.Linear).Update(1)
+	if f.Brightness() != 0.2 || f.Matrix[4] != 0.2 {
+		t.Errorf("Brightness = %f, matrix offset = %f, want 0.2", f.Brightness(), f.Matrix[4])
+	}
+}
+
+func TestTweenTextColor(t *testing.T) {
+	node := NewText("label", "hi", nil)
+	node.TextBlock.Color = Color{R: 1, G: 1, B: 1, A: 1}
+	node.TextBlock.ttfDirty = false
+	TweenTextColor(node, Color{R: 0, G: 0, B: 0, A: 1}, 1.0, ease.Linear).Update(0.5)
+	if node.TextBlock.Color.R != 0.5 || !node.TextBlock.ttfDirty {
+		t.Errorf("R = %f ttfDirty = %v, want 0.5 and dirty", node.TextBlock.Color.R, node.TextBlock.ttfDirty)
+	}
+}
+
+func TestTweenTextColorWithoutTextBlock(t *testing.T) {
+	node := NewSprite("s", TextureRegion{})
+	g := TweenTextColor(node, Color{R: 1, A: 1}, 1.0, ease.Linear)
+	if !g.Done {
+		t.Error("tween on a node without a TextBlock should be Done")
+	}
+	g.Update(0.5)
+	if node.Color != (Color{R: 1, G: 1, B: 1, A: 1}) {
+		t.Errorf("node color changed to %+v", node.Color)
+	}
+}
@@ -0,0 +1,288 @@
+package willow
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// AsepriteSheet is the result of loading an Aseprite JSON export. Regions are
+// registered in Atlas under each frame's filename; Clips holds one AnimClip per
+// frame tag, ready to pass to NewAnimatedSprite.
+type AsepriteSheet struct {
+	Atlas *Atlas
+	// Frames lists every exported frame in Aseprite order, with durations
+	// converted from milliseconds to seconds.
+	Frames []SpriteFrame
+	// FrameNames holds the filename of each entry in Frames.
+	FrameNames []string
+	// Clips holds one clip per frame tag, in the order the tags are defined.
+	Clips []AnimClip
+	// Slices maps slice names to their per-frame keys.
+	Slices map[string]AsepriteSlice
+}
+
+// Clip returns the clip built from the named frame tag.
+func (s *AsepriteSheet) Clip(name string) (AnimClip, bool) {
+	for _, c := range s.Clips {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return AnimClip{}, false
+}
+
+// AsepriteSlice is a named slice from an Aseprite export. A slice can change
+// shape over time, so it holds one key per frame where it was edited.
+type AsepriteSlice struct {
+	Name string
+	Keys []AsepriteSliceKey
+}
+
+// AsepriteSliceKey is the state of a slice starting at Frame.
+type AsepriteSliceKey struct {
+	Frame  int  // first frame this key applies to
+	Bounds Rect // slice rectangle in sprite (canvas) coordinates
+	// Center is the inner 9-patch rectangle, relative to Bounds. Valid only
+	// when HasCenter is true.
+	Center    Rect
+	HasCenter bool
+	// Pivot is relative to Bounds, so the pivot in frame coordinates is
+	// Bounds.X+Pivot.X, Bounds.Y+Pivot.Y; see FramePivot. Valid only when
+	// HasPivot is true.
+	Pivot    Vec2
+	HasPivot bool
+}
+
+// FramePivot returns the key's pivot in frame (canvas) coordinates, ready to
+// assign to Node.PivotX/PivotY on a sprite showing the frame. ok is false
+// when the key has no pivot.
+func (k AsepriteSliceKey) FramePivot() (x, y float64, ok bool) {
+	if !k.HasPivot {
+		return 0, 0, false
+	}
+	return k.Bounds.X + k.Pivot.X, k.Bounds.Y + k.Pivot.Y, true
+}
+
+// KeyAt returns the key in effect at the given frame: the last key whose
+// Frame is not after it, or the first key for earlier frames. Returns the
+// zero key if the slice has no keys.
+func (s AsepriteSlice) KeyAt(frame int) AsepriteSliceKey {
+	if len(s.Keys) == 0 {
+		return AsepriteSliceKey{}
+	}
+	k := s.Keys[0]
+	for _, key := range s.Keys[1:] {
+		if key.Frame > frame {
+			break
+		}
+		k = key
+	}
+	return k
+}
+
+// LoadAseprite parses a JSON sheet exported by Aseprite (hash or array
+// layout) and associates the given page images. Frame tags become clips:
+// "forward" loops, "reverse" loops backwards, "pingpong" and
+// "pingpong_reverse" use AnimPingPong. A tag with a repeat count plays that
+// many times and stops: its frames are repeated in the clip, which uses
+// AnimOnce. Each ping-pong pass after the first runs the other way and
+// skips the end frame it turns on.
+func LoadAseprite(jsonData []byte, pages []*ebiten.Image) (*AsepriteSheet, error) {
+	return loadAseprite(jsonData, pages, 0)
+}
+
+// --- JSON structure types ---
+
+type asepriteFrame struct {
+	jsonFrame
+	Filename string `json:"filename"`
+	Duration int    `json:"duration"`
+}
+
+type asepriteTag struct {
+	Name      string          `json:"name"`
+	From      int             `json:"from"`
+	To        int             `json:"to"`
+	Direction string          `json:"direction"`
+	Repeat    json.RawMessage `json:"repeat"`
+}
+
+type asepriteSliceKeyJSON struct {
+	Frame  int       `json:"frame"`
+	Bounds jsonRect  `json:"bounds"`
+	Center *jsonRect `json:"center"`
+	Pivot  *struct {
+		X int `json:"x"`
+		Y int `json:"y"`
+	} `json:"pivot"`
+}
+
+type asepriteSliceJSON struct {
+	Name string                 `json:"name"`
+	Keys []asepriteSliceKeyJSON `json:"keys"`
+}
+
+// loadAseprite does the work of LoadAseprite, adding pageOffset to every
+// region's page index so Scene.LoadAseprite can append to existing pages.
+func loadAseprite(jsonData []byte, pages []*ebiten.Image, pageOffset uint16) (*AsepriteSheet, error) {
+	var doc struct {
+		Frames json.RawMessage `json:"frames"`
+		Meta   struct {
+			FrameTags []asepriteTag       `json:"frameTags"`
+			Slices    []asepriteSliceJSON `json:"slices"`
+		} `json:"meta"`
+	}
+	if err := json.Unmarshal(jsonData, &doc); err != nil {
+		return nil, fmt.Errorf("willow: failed to parse aseprite JSON: %w", err)
+	}
+	if doc.Frames == nil {
+		return nil, fmt.Errorf("willow: aseprite JSON has no \"frames\" key")
+	}
+
+	frames, err := parseAsepriteFrames(doc.Frames)
+	if err != nil {
+		return nil, err
+	}
+
+	sheet := &AsepriteSheet{
+		Atlas: &Atlas{
+			Pages:   pages,
+			regions: make(map[string]TextureRegion, len(frames)),
+		},
+		Frames:     make([]SpriteFrame, len(frames)),
+		FrameNames: make([]string, len(frames)),
+		Clips:      make([]AnimClip, 0, len(doc.Meta.FrameTags)),
+		Slices:     make(map[string]AsepriteSlice, len(doc.Meta.Slices)),
+	}
+	for i, f := range frames {
+		r := frameToRegion(f.jsonFrame, pageOffset)
+		sheet.Atlas.regions[f.Filename] = r
+		sheet.Frames[i] = SpriteFrame{Region: r, Duration: float64(f.Duration) / 1000}
+		sheet.FrameNames[i] = f.Filename
+	}
+
+	for _, tag := range doc.Meta.FrameTags {
+		clip, err := asepriteTagToClip(tag, sheet.Frames)
+		if err != nil {
+			return nil, err
+		}
+		sheet.Clips = append(sheet.Clips, clip)
+	}
+
+	for _, sl := range doc.Meta.Slices {
+		s := AsepriteSlice{Name: sl.Name, Keys: make([]AsepriteSliceKey, len(sl.Keys))}
+		for i, k := range sl.Keys {
+			key := AsepriteSliceKey{Frame: k.Frame, Bounds: jsonRectToRect(k.Bounds)}
+			if k.Center != nil {
+				key.Center = jsonRectToRect(*k.Center)
+				key.HasCenter = true
+			}
+			if k.Pivot != nil {
+				key.Pivot = Vec2{X: float64(k.Pivot.X), Y: float64(k.Pivot.Y)}
+				key.HasPivot = true
+			}
+			s.Keys[i] = key
+		}
+		sheet.Slices[sl.Name] = s
+	}
+
+	return sheet, nil
+}
+
+// parseAsepriteFrames decodes the "frames" value in export order. The hash
+// layout is an object keyed by filename, so it is read token by token to
+// keep the key order that frame tags index into.
+func parseAsepriteFrames(raw json.RawMessage) ([]asepriteFrame, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) > 0 && raw[0] == '[' {
+		var frames []asepriteFrame
+		if err := json.Unmarshal(raw, &frames); err != nil {
+			return nil, fmt.Errorf("willow: failed to parse aseprite frames: %w", err)
+		}
+		return frames, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if _, err := dec.Token(); err != nil {
+		return nil, fmt.Errorf("willow: failed to parse aseprite frames: %w", err)
+	}
+	var frames []asepriteFrame
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("willow: failed to parse aseprite frames: %w", err)
+		}
+		var f asepriteFrame
+		if err := dec.Decode(&f); err != nil {
+			return nil, fmt.Errorf("willow: failed to parse aseprite frame %v: %w", tok, err)
+		}
+		f.Filename, _ = tok.(string)
+		frames = append(frames, f)
+	}
+	return frames, nil
+}
+
+// asepriteTagToClip builds the clip for one frame tag.
+func asepriteTagToClip(tag asepriteTag, frames []SpriteFrame) (AnimClip, error) {
+	if tag.From < 0 || tag.To >= len(frames) || tag.From > tag.To {
+		return AnimClip{}, fmt.Errorf("willow: aseprite tag %q has invalid frame range %d..%d", tag.Name, tag.From, tag.To)
+	}
+	clipFrames := make([]SpriteFrame, 0, tag.To-tag.From+1)
+	clipFrames = append(clipFrames, frames[tag.From:tag.To+1]...)
+
+	mode := AnimLoop
+	switch tag.Direction {
+	case "reverse":
+		reverseFrames(clipFrames)
+	case "pingpong":
+		mode = AnimPingPong
+	case "pingpong_reverse":
+		reverseFrames(clipFrames)
+		mode = AnimPingPong
+	}
+
+	// Aseprite writes repeat as a quoted string; accept a bare number too.
+	if len(tag.Repeat) > 0 {
+		s := string(bytes.Trim(tag.Repeat, `"`))
+		if n, err := strconv.Atoi(s); err == nil && n > 0 {
+			clipFrames = repeatFrames(clipFrames, n, mode == AnimPingPong)
+			mode = AnimOnce
+		}
+	}
+	return AnimClip{Name: tag.Name, Frames: clipFrames, Mode: mode}, nil
+}
+
+// repeatFrames returns n passes over f. Ping-pong passes alternate
+// direction and share the frame they turn on.
+func repeatFrames(f []SpriteFrame, n int, pingPong bool) []SpriteFrame {
+	out := make([]SpriteFrame, 0, len(f)*n)
+	out = append(out, f...)
+	for pass := 1; pass < n; pass++ {
+		if !pingPong {
+			out = append(out, f...)
+			continue
+		}
+		if pass%2 == 1 {
+			for i := len(f) - 2; i >= 0; i-- {
+				out = append(out, f[i])
+			}
+		} else {
+			out = append(out, f[1:]...)
+		}
+	}
+	return out
+}
+
+func reverseFrames(f []SpriteFrame) {
+	for i, j := 0, len(f)-1; i < j; i, j = i+1, j-1 {
+		f[i], f[j] = f[j], f[i]
+	}
+}
+
+func jsonRectToRect(r jsonRect) Rect {
+	return Rect{X: float64(r.X), Y: float64(r.Y), Width: float64(r.W), Height: float64(r.H)}
+}
@@ -0,0 +1,222 @@
+package willow
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Hash layout with keys deliberately out of alphabetical order: frame tags
+// index into export order, not sorted order.
+const asepriteHashJSON = `{
+  "frames": {
+    "hero 2.aseprite": {
+      "frame": {"x": 0, "y": 0, "w": 16, "h": 16},
+      "rotated": false, "trimmed": false,
+      "spriteSourceSize": {"x": 0, "y": 0, "w": 16, "h": 16},
+      "sourceSize": {"w": 16, "h": 16},
+      "duration": 100
+    },
+    "hero 0.aseprite": {
+      "frame": {"x": 16, "y": 0, "w": 16, "h": 16},
+      "rotated": false, "trimmed": false,
+      "spriteSourceSize": {"x": 0, "y": 0, "w": 16, "h": 16},
+      "sourceSize": {"w": 16, "h": 16},
+      "duration": 150
+    },
+    "hero 1.aseprite": {
+      "frame": {"x": 32, "y": 0, "w": 14, "h": 15},
+      "rotated": false, "trimmed": true,
+      "spriteSourceSize": {"x": 1, "y": 1, "w": 14, "h": 15},
+      "sourceSize": {"w": 16, "h": 16},
+      "duration": 200
+    }
+  },
+  "meta": {
+    "app": "https://www.aseprite.org/",
+    "image": "hero.png",
+    "frameTags": [
+      {"name": "walk", "from": 0, "to": 2, "direction": "forward"},
+      {"name": "back", "from": 0, "to": 2, "direction": "reverse"},
+      {"name": "bob", "from": 1, "to": 2, "direction": "pingpong"},
+      {"name": "hit", "from": 0, "to": 1, "direction": "forward", "repeat": "1"},
+      {"name": "twice", "from": 0, "to": 1, "direction": "reverse", "repeat": "2"},
+      {"name": "bounce", "from": 0, "to": 2, "direction": "pingpong", "repeat": 3}
+    ],
+    "slices": [
+      {"name": "panel", "color": "#0000ffff", "keys": [
+        {"frame": 0, "bounds": {"x": 0, "y": 0, "w": 16, "h": 16}, "center": {"x": 4, "y": 4, "w": 8, "h": 8}},
+        {"frame": 2, "bounds": {"x": 1, "y": 1, "w": 14, "h": 14}, "center": {"x": 3, "y": 3, "w": 8, "h": 8}}
+      ]},
+      {"name": "feet", "color": "#ff0000ff", "keys": [
+        {"frame": 0, "bounds": {"x": 4, "y": 12, "w": 8, "h": 4}, "pivot": {"x": 4, "y": 4}}
+      ]}
+    ]
+  }
+}`
+
+const asepriteArrayJSON = `{
+  "frames": [
+    {"filename": "a", "frame": {"x": 0, "y": 0, "w": 8, "h": 8}, "sourceSize": {"w": 8, "h": 8}, "spriteSourceSize": {"x": 0, "y": 0, "w": 8, "h": 8}, "duration": 50},
+    {"filename": "b", "frame": {"x": 8, "y": 0, "w": 8, "h": 8}, "sourceSize": {"w": 8, "h": 8}, "spriteSourceSize": {"x": 0, "y": 0, "w": 8, "h": 8}, "duration": 60}
+  ],
+  "meta": {"frameTags": [{"name": "all", "from": 0, "to": 1, "direction": "pingpong_reverse"}]}
+}`
+
+func TestLoadAseprite_HashKeepsExportOrder(t *testing.T) {
+	sheet, err := LoadAseprite([]byte(asepriteHashJSON), []*ebiten.Image{ebiten.NewImage(64, 16)})
+	if err != nil {
+		t.Fatalf("LoadAseprite: %v", err)
+	}
+	want := []string{"hero 2.aseprite", "hero 0.aseprite", "hero 1.aseprite"}
+	for i, name := range want {
+		if sheet.FrameNames[i] != name {
+			t.Fatalf("FrameNames = %v, want %v", sheet.FrameNames, want)
+		}
+	}
+	if sheet.Frames[1].Duration != 0.15 {
+		t.Errorf("frame 1 duration = %v, want 0.15s", sheet.Frames[1].Duration)
+	}
+	r := sheet.Atlas.Region("hero 1.aseprite")
+	if r.X != 32 || r.OffsetX != 1 || r.OriginalW != 16 {
+		t.Errorf("trimmed region = %+v", r)
+	}
+}
+
+func TestLoadAseprite_TagDirections(t *testing.T) {
+	sheet, err := LoadAseprite([]byte(asepriteHashJSON), nil)
+	if err != nil {
+		t.Fatalf("LoadAseprite: %v", err)
+	}
+	if len(sheet.Clips) != 6 {
+		t.Fatalf("clips = %d, want 6", len(sheet.Clips))
+	}
+
+	walk, _ := sheet.Clip("walk")
+	if walk.Mode != AnimLoop || len(walk.Frames) != 3 || walk.Frames[0].Region.X != 0 {
+		t.Errorf("walk = %+v", walk)
+	}
+	back, _ := sheet.Clip("back")
+	if back.Mode != AnimLoop || back.Frames[0].Region.X != 32 || back.Frames[2].Region.X != 0 {
+		t.Errorf("reverse clip frames not reversed: %+v", back.Frames)
+	}
+	// Reversing a clip must not reorder the sheet's own frames.
+	if sheet.Frames[0].Region.X != 0 {
+		t.Error("reverse tag mutated sheet.Frames")
+	}
+	bob, _ := sheet.Clip("bob")
+	if bob.Mode != AnimPingPong || len(bob.Frames) != 2 {
+		t.Errorf("bob = %+v", bob)
+	}
+	hit, _ := sheet.Clip("hit")
+	if hit.Mode != AnimOnce {
+		t.Errorf("repeat 1 mode = %d, want AnimOnce", hit.Mode)
+	}
+	regionXs := func(c AnimClip) []float64 {
+		var xs []float64
+		for _, f := range c.Frames {
+			xs = append(xs, float64(f.Region.X))
+		}
+		return xs
+	}
+	twice, _ := sheet.Clip("twice")
+	if xs := regionXs(twice); twice.Mode != AnimOnce || fmt.Sprint(xs) != "[16 0 16 0]" {
+		t.Errorf("repeat 2 clip = mode %d frames %v, want AnimOnce [16 0 16 0]", twice.Mode, xs)
+	}
+	bounce, _ := sheet.Clip("bounce")
+	if xs := regionXs(bounce); bounce.Mode != AnimOnce || fmt.Sprint(xs) != "[0 16 32 16 0 16 32]" {
+		t.Errorf("ping-pong repeat 3 clip = mode %d frames %v", bounce.Mode, xs)
+	}
+	if _, ok := sheet.Clip("missing"); ok {
+		t.Error("Clip(missing) should report false")
+	}
+}
+
+func TestLoadAseprite_Slices(t *testing.T) {
+	sheet, err := LoadAseprite([]byte(asepriteHashJSON), nil)
+	if err != nil {
+		t.Fatalf("LoadAseprite: %v", err)
+	}
+	panel := sheet.Slices["panel"]
+	if len(panel.Keys) != 2 {
+		t.Fatalf("panel keys = %d, want 2", len(panel.Keys))
+	}
+	k := panel.KeyAt(1)
+	if !k.HasCenter || k.Center != (Rect{X: 4, Y: 4, Width: 8, Height: 8}) {
+		t.Errorf("KeyAt(1) = %+v", k)
+	}
+	if k := panel.KeyAt(5); k.Bounds.X != 1 {
+		t.Errorf("KeyAt(5).Bounds = %+v, want second key", k.Bounds)
+	}
+
+	feet := sheet.Slices["feet"].KeyAt(0)
+	if !feet.HasPivot || feet.Pivot != (Vec2{X: 4, Y: 4}) || feet.HasCenter {
+		t.Errorf("feet = %+v", feet)
+	}
+	if x, y, ok := feet.FramePivot(); !ok || x != 8 || y != 16 {
+		t.Errorf("feet FramePivot = %v, %v, %v; want 8, 16, true", x, y, ok)
+	}
+	if _, _, ok := k.FramePivot(); ok {
+		t.Error("FramePivot on a key without a pivot should report false")
+	}
+}
+
+func TestLoadAseprite_ArrayLayout(t *testing.T) {
+	sheet, err := LoadAseprite([]byte(asepriteArrayJSON), nil)
+	if err != nil {
+		t.Fatalf("LoadAseprite: %v", err)
+	}
+	if sheet.Atlas.Region("b").X != 8 {
+		t.Error("array frame b not registered")
+	}
+	all, ok := sheet.Clip("all")
+	if !ok || all.Mode != AnimPingPong || all.Frames[0].Duration != 0.06 {
+		t.Errorf("pingpong_reverse clip = %+v", all)
+	}
+}
+
+func TestLoadAseprite_Errors(t *testing.T) {
+	if _, err := LoadAseprite([]byte(`{`), nil); err == nil {
+		t.Error("expected error for malformed JSON")
+	}
+	if _, err := LoadAseprite([]byte(`{"meta":{}}`), nil); err == nil {
+		t.Error("expected error for missing frames")
+	}
+	bad := `{"frames":[{"filename":"a","duration":100}],"meta":{"frameTags":[{"name":"x","from":0,"to":3}]}}`
+	if _, err := LoadAseprite([]byte(bad), nil); err == nil {
+		t.Error("expected error for out-of-range tag")
+	}
+}
+
+func TestScene_LoadAseprite_OffsetsPages(t *testing.T) {
+	scene := NewScene()
+	if _, err := scene.LoadAtlas([]byte(singlePageJSON), []*ebiten.Image{ebiten.NewImage(8, 8)}); err != nil {
+		t.Fatalf("Scene.LoadAtlas: %v", err)
+	}
+	page := ebiten.NewImage(64, 16)
+	sheet, err := scene.LoadAseprite([]byte(asepriteHashJSON), []*ebiten.Image{page})
+	if err != nil {
+		t.Fatalf("Scene.LoadAseprite: %v", err)
+	}
+	if scene.pages[1] != page {
+		t.Error("aseprite page not registered at index 1")
+	}
+	walk, _ := sheet.Clip("walk")
+	if sheet.Atlas.Region("hero 0.aseprite").Page != 1 || walk.Frames[0].Region.Page != 1 {
+		t.Error("regions and clip frames should use the offset page index")
+	}
+}
+
+func TestLoadAseprite_PlaysInAnimatedSprite(t *testing.T) {
+	sheet, err := LoadAseprite([]byte(asepriteHashJSON), nil)
+	if err != nil {
+		t.Fatalf("LoadAseprite: %v", err)
+	}
+	a := NewAnimatedSprite("hero", sheet.Clips...)
+	a.Play("walk")
+	a.update(0.1) // first frame lasts 100ms
+	if a.Frame() != 1 {
+		t.Errorf("frame = %d, want 1", a.Frame())
+	}
+}
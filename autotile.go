@@ -0,0 +1,196 @@
+package willow
+
+import "math/bits"
+
+// AutotileMode selects which neighbours decide an autotiled cell's GID.
+type AutotileMode uint8
+
+const (
+	// AutotileEdge uses the four edge neighbours (16 combinations, Tiled
+	// edge Wang sets).
+	AutotileEdge AutotileMode = iota
+	// AutotileBlob uses all eight neighbours, counting a corner only when
+	// both edges beside it match (the 47-tile blob, Tiled mixed Wang sets).
+	AutotileBlob
+	// AutotileCorner uses the four corners, each matching when the three
+	// cells around it match (16 combinations, Tiled corner Wang sets).
+	AutotileCorner
+)
+
+// Neighbour bits of an autotile mask. The order matches the positions in
+// a Tiled wangid.
+const (
+	AutotileN uint8 = 1 << iota
+	AutotileNE
+	AutotileE
+	AutotileSE
+	AutotileS
+	AutotileSW
+	AutotileW
+	AutotileNW
+)
+
+// autotileOffsets are the (dcol, drow) of each mask bit's neighbour.
+var autotileOffsets = [8][2]int{{0, -1}, {1, -1}, {1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}}
+
+// AutotileRules maps painted terrains to tile GIDs.
+type AutotileRules struct {
+	Mode AutotileMode
+
+	// Terrains maps each terrain ID (> 0) to the GID drawn for each
+	// neighbour mask. Masks missing from a table fall back to the entry
+	// that differs in the fewest relevant bits.
+	Terrains map[int]map[uint8]uint32
+
+	// MatchOutside makes cells beyond the layer's edges count as matching
+	// neighbours, so terrain runs off the map without a border.
+	MatchOutside bool
+}
+
+// autotileState is a layer's terrain grid and resolved rule tables.
+type autotileState struct {
+	rules   *AutotileRules
+	terrain []int32                // per-cell terrain ID, row-major
+	tables  map[int32]*[256]uint32 // terrain -> GID for every raw mask
+}
+
+// SetAutotile enables terrain painting with the given rules, deriving each
+// cell's terrain from its current GID. Call it again after editing the
+// rules. Passing nil disables autotiling.
+func (l *TileMapLayer) SetAutotile(rules *AutotileRules) {
+	if rules == nil {
+		l.autotile = nil
+		return
+	}
+	a := &autotileState{rules: rules, tables: make(map[int32]*[256]uint32, len(rules.Terrains))}
+	for id, tiles := range rules.Terrains {
+		a.tables[int32(id)] = buildAutotileTable(rules.Mode, tiles)
+	}
+	l.autotile = a
+	l.deriveTerrain()
+}
+
+// deriveTerrain rebuilds the terrain grid from the layer's GIDs.
+func (l *TileMapLayer) deriveTerrain() {
+	a := l.autotile
+	owner := make(map[uint32]int32)
+	for id, tiles := range a.rules.Terrains {
+		for _, gid := range tiles {
+			if cur, ok := owner[gid]; !ok || int32(id) < cur {
+				owner[gid] = int32(id)
+			}
+		}
+	}
+	a.terrain = make([]int32, len(l.data))
+	for i, gid := range l.data {
+		a.terrain[i] = owner[gid&^tileFlagMask]
+	}
+}
+
+// PaintTerrain sets the terrain of (col, row) and re-picks the GIDs of the
+// cell and its eight neighbours. Terrain 0 clears the cell. Only cells
+// whose GID changes are written, so buffer rebuilds happen only when a
+// changed cell is buffered. No-op without SetAutotile or outside the layer.
+func (l *TileMapLayer) PaintTerrain(col, row, terrain int) {
+	a := l.autotile
+	if a == nil || col < 0 || col >= l.width || row < 0 || row >= l.height {
+		return
+	}
+	a.terrain[row*l.width+col] = int32(terrain)
+	if terrain == 0 {
+		l.SetTile(col, row, 0)
+	}
+	for dr := -1; dr <= 1; dr++ {
+		for dc := -1; dc <= 1; dc++ {
+			l.refreshAutotile(col+dc, row+dr)
+		}
+	}
+}
+
+// TerrainAt returns the terrain painted at (col, row), or 0.
+func (l *TileMapLayer) TerrainAt(col, row int) int {
+	if l.autotile == nil || col < 0 || col >= l.width || row < 0 || row >= l.height {
+		return 0
+	}
+	return int(l.autotile.terrain[row*l.width+col])
+}
+
+// refreshAutotile re-picks the GID of a painted cell from its neighbours.
+func (l *TileMapLayer) refreshAutotile(col, row int) {
+	if col < 0 || col >= l.width || row < 0 || row >= l.height {
+		return
+	}
+	a := l.autotile
+	t := a.terrain[row*l.width+col]
+	table := a.tables[t]
+	if table == nil {
+		return
+	}
+	var mask uint8
+	for i, off := range autotileOffsets {
+		c, r := col+off[0], row+off[1]
+		match := a.rules.MatchOutside
+		if c >= 0 && c < l.width && r >= 0 && r < l.height {
+			match = a.terrain[r*l.width+c] == t
+		}
+		if match {
+			mask |= 1 << i
+		}
+	}
+	if gid := table[mask]; gid != l.data[row*l.width+col] {
+		l.SetTile(col, row, gid)
+	}
+}
+
+// normalizeAutotileMask keeps the bits of a rule mask that matter for
+// mode.
+func normalizeAutotileMask(mode AutotileMode, m uint8) uint8 {
+	const edges = AutotileN | AutotileE | AutotileS | AutotileW
+	switch mode {
+	case AutotileEdge:
+		return m & edges
+	case AutotileBlob:
+		out := m & edges
+		for _, c := range [4][3]uint8{
+			{AutotileNE, AutotileN, AutotileE},
+			{AutotileSE, AutotileS, AutotileE},
+			{AutotileSW, AutotileS, AutotileW},
+			{AutotileNW, AutotileN, AutotileW},
+		} {
+			if m&c[0] != 0 && m&c[1] != 0 && m&c[2] != 0 {
+				out |= c[0]
+			}
+		}
+		return out
+	default:
+		return m &^ edges
+	}
+}
+
+// cornerMask sets each corner bit of a raw neighbour mask only when the
+// corner cell and both edge cells beside it match.
+func cornerMask(m uint8) uint8 {
+	return normalizeAutotileMask(AutotileBlob, m) &^ (AutotileN | AutotileE | AutotileS | AutotileW)
+}
+
+// buildAutotileTable resolves the GID for every raw neighbour mask.
+func buildAutotileTable(mode AutotileMode, tiles map[uint8]uint32) *[256]uint32 {
+	var table [256]uint32
+	for m := 0; m < 256; m++ {
+		want := uint8(m)
+		if mode == AutotileCorner {
+			want = cornerMask(want)
+		} else {
+			want = normalizeAutotileMask(mode, want)
+		}
+		best, bestKey := 9, 0
+		for key, gid := range tiles {
+			d := bits.OnesCount8(normalizeAutotileMask(mode, key) ^ want)
+			if d < best || (d == best && int(key) < bestKey) {
+				best, bestKey = d, int(key)
+				table[m] = gid
+			}
+		}
+	}
+	return &table
+}
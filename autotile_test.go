@@ -0,0 +1,130 @@
+package willow
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// edgeRules maps every 4-bit edge mask m of terrain 1 to GID 100+m.
+func edgeRules() *AutotileRules {
+	tiles := make(map[uint8]uint32)
+	for m := 0; m < 256; m++ {
+		if uint8(m)&^(AutotileN|AutotileE|AutotileS|AutotileW) == 0 {
+			tiles[uint8(m)] = 100 + uint32(m)
+		}
+	}
+	return &AutotileRules{Mode: AutotileEdge, Terrains: map[int]map[uint8]uint32{1: tiles}}
+}
+
+func newAutotileLayer(w, h int, rules *AutotileRules) *TileMapLayer {
+	_, v := newTestViewport(64, 64)
+	layer := v.AddTileLayer("terrain", w, h, make([]uint32, w*h), testRegions(2), ebiten.NewImage(32, 16))
+	layer.SetAutotile(rules)
+	return layer
+}
+
+func TestAutotileEdgePaint(t *testing.T) {
+	layer := newAutotileLayer(3, 3, edgeRules())
+	layer.PaintTerrain(1, 1, 1)
+	if gid := layer.GetTile(1, 1); gid != 100 {
+		t.Errorf("lone cell = %d, want 100 (no neighbours)", gid)
+	}
+
+	// Painting the right neighbour updates both cells.
+	layer.PaintTerrain(2, 1, 1)
+	if gid := layer.GetTile(1, 1); gid != 100+uint32(AutotileE) {
+		t.Errorf("center = %d, want east mask", gid)
+	}
+	if gid := layer.GetTile(2, 1); gid != 100+uint32(AutotileW) {
+		t.Errorf("right = %d, want west mask", gid)
+	}
+
+	// Clearing a cell clears it and re-picks its neighbours.
+	layer.PaintTerrain(2, 1, 0)
+	if layer.GetTile(2, 1) != 0 || layer.TerrainAt(2, 1) != 0 || layer.GetTile(1, 1) != 100 {
+		t.Errorf("after erase: right=%d center=%d", layer.GetTile(2, 1), layer.GetTile(1, 1))
+	}
+	// Cells never painted are left alone.
+	if layer.GetTile(0, 0) != 0 {
+		t.Error("unpainted cell was written")
+	}
+}
+
+func TestAutotileBlobCorners(t *testing.T) {
+	full := AutotileN | AutotileNE | AutotileE | AutotileSE | AutotileS | AutotileSW | AutotileW | AutotileNW
+	rules := &AutotileRules{Mode: AutotileBlob, MatchOutside: true, Terrains: map[int]map[uint8]uint32{
+		1: {full: 1, full &^ AutotileNE: 2, 0: 3},
+	}}
+	layer := newAutotileLayer(3, 3, rules)
+	for r := 0; r < 3; r++ {
+		for c := 0; c < 3; c++ {
+			layer.PaintTerrain(c, r, 1)
+		}
+	}
+	if gid := layer.GetTile(1, 1); gid != 1 {
+		t.Errorf("surrounded cell = %d, want 1", gid)
+	}
+	layer.PaintTerrain(2, 0, 0)
+	if gid := layer.GetTile(1, 1); gid != 2 {
+		t.Errorf("missing NE corner = %d, want 2", gid)
+	}
+	// (2,1) lost its north edge, so its NE/NW corners no longer count; the
+	// nearest rule is the one missing only NE.
+	if gid := layer.GetTile(2, 1); gid != 2 {
+		t.Errorf("cell below the hole = %d, want fallback 2", gid)
+	}
+}
+
+func TestAutotileCornerMode(t *testing.T) {
+	rules := &AutotileRules{Mode: AutotileCorner, Terrains: map[int]map[uint8]uint32{
+		1: {0: 10, AutotileSE: 11, AutotileNE | AutotileSE | AutotileSW | AutotileNW: 12},
+	}}
+	layer := newAutotileLayer(3, 3, rules)
+	layer.PaintTerrain(0, 0, 1)
+	layer.PaintTerrain(1, 0, 1)
+	layer.PaintTerrain(0, 1, 1)
+	if gid := layer.GetTile(0, 0); gid != 10 {
+		t.Errorf("SE corner incomplete = %d, want 10", gid)
+	}
+	layer.PaintTerrain(1, 1, 1)
+	if gid := layer.GetTile(0, 0); gid != 11 {
+		t.Errorf("SE corner complete = %d, want 11", gid)
+	}
+}
+
+func TestAutotileDerivesTerrain(t *testing.T) {
+	_, v := newTestViewport(64, 64)
+	layer := v.AddTileLayer("terrain", 2, 1, []uint32{100 + uint32(AutotileE), 0}, testRegions(2), ebiten.NewImage(32, 16))
+	layer.SetAutotile(edgeRules())
+	if layer.TerrainAt(0, 0) != 1 || layer.TerrainAt(1, 0) != 0 {
+		t.Errorf("terrain = %d,%d, want 1,0", layer.TerrainAt(0, 0), layer.TerrainAt(1, 0))
+	}
+}
+
+func TestTiledWangSetRules(t *testing.T) {
+	ts := &TiledTileset{FirstGID: 5, WangSets: []TiledWangSet{{
+		Name: "ground", Type: "corner", Colors: []string{"grass", "sand"},
+		Tiles: []TiledWangTile{
+			{ID: 0, WangID: [8]uint8{0, 1, 0, 1, 0, 1, 0, 1}},
+			{ID: 1, WangID: [8]uint8{0, 1, 0, 2, 0, 2, 0, 1}},
+		},
+	}}}
+	if ts.AutotileRules("missing") != nil {
+		t.Error("unknown set should return nil")
+	}
+	r := ts.AutotileRules("ground")
+	if r.Mode != AutotileCorner {
+		t.Errorf("mode = %d, want AutotileCorner", r.Mode)
+	}
+	corners := AutotileNE | AutotileSE | AutotileSW | AutotileNW
+	if gid := r.Terrains[1][corners]; gid != 5 {
+		t.Errorf("grass full = %d, want 5", gid)
+	}
+	if gid := r.Terrains[1][AutotileNE|AutotileNW]; gid != 6 {
+		t.Errorf("grass top = %d, want 6", gid)
+	}
+	if gid := r.Terrains[2][AutotileSE|AutotileSW]; gid != 6 {
+		t.Errorf("sand bottom = %d, want 6", gid)
+	}
+}
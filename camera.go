@@ -3,18 +3,10 @@ package willow
 import (
 	"math"
 
-	"github.com/tanema/gween"
+	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/tanema/gween/ease"
 )
 
-// scrollAnim holds active scroll-to tweens for camera X and Y.
-type scrollAnim struct {
-	tweenX *gween.Tween
-	tweenY *gween.Tween
-	doneX  bool
-	doneY  bool
-}
-
 // Camera controls the view into the scene: position, zoom, rotation, and viewport.
 type Camera struct {
 	// X and Y are the world-space position the camera centers on.
@@ -23,18 +15,71 @@ type Camera struct {
 	Zoom float64
 	// Rotation is the camera rotation in radians (clockwise).
 	Rotation float64
-	// Viewport is the screen-space rectangle this camera renders into.
+	// Viewport is the screen-space rectangle this camera renders into. When
+	// Target is set it is in the target texture's pixel space instead.
 	Viewport Rect
 
+	// Target, when set, makes the camera render into a RenderTexture instead
+	// of the screen. Its Viewport area of the texture is cleared each frame.
+	// Show the texture with RenderTexture.NewSpriteNode to build minimaps or
+	// picture-in-picture views, or add filters to that sprite.
+	Target *RenderTexture
+	// PixelPerfect renders the world at one texel per world pixel into a
+	// low-resolution target, with the camera snapped to whole pixels, then
+	// upscales it by a whole-number factor with nearest filtering. The
+	// fractional part of X/Y is applied as an offset during the upscale, so
+	// scrolling stays smooth without shimmering. The factor is Zoom rounded
+	// to a whole number (at least 1). Zoom effects are ignored.
+	PixelPerfect bool
+	// PixelWidth and PixelHeight fix the low-resolution size in pixel-perfect
+	// mode. The factor is then the largest whole number that fits the
+	// Viewport, and the image is centered with LetterboxColor around it.
+	// Zero sizes the target from the Viewport and Zoom.
+	PixelWidth, PixelHeight int
+	// LetterboxColor fills the Viewport around the upscaled image in
+	// pixel-perfect mode. The zero value leaves it transparent.
+	LetterboxColor Color
+
+	// MapInput forwards pointer input that hits an interactable sprite
+	// showing Target into this camera's world, so nodes seen through the
+	// texture can be clicked and dragged.
+	MapInput bool
+
 	// CullEnabled skips nodes whose world AABB doesn't intersect the
 	// camera's visible bounds.
 	CullEnabled bool
 
+	// RenderMask selects the RenderLayers this camera draws and routes
+	// pointer input to. The zero LayerMask includes every layer.
+	RenderMask LayerMask
+
 	followTarget  *Node
 	followOffsetX float64
 	followOffsetY float64
 	followLerp    float64
 
+	// Deadzone is a screen-space rectangle, relative to the viewport center,
+	// inside which a followed node can move without the camera moving. The
+	// zero Rect disables it.
+	Deadzone Rect
+	// LookAhead leads a followed node by its velocity times LookAhead
+	// seconds, so the camera shows more of where it is heading.
+	LookAhead float64
+	// LookAheadMax caps the look-ahead distance in world units. Zero means
+	// no cap.
+	LookAheadMax float64
+
+	// MinZoom and MaxZoom limit the zoom chosen by FollowGroup, ZoomAt,
+	// ZoomTo and pan/zoom controllers. Zero means no limit.
+	MinZoom, MaxZoom float64
+
+	followGroup   []*Node
+	followPadding float64
+	followPrevX   float64
+	followPrevY   float64
+	followHasPrev bool
+	lookX, lookY  float64
+
 	// BoundsEnabled clamps the camera position so the visible area stays
 	// within Bounds.
 	BoundsEnabled bool
@@ -46,7 +91,33 @@ type Camera struct {
 	invViewMatrix [6]float64
 	dirty         bool
 
-	scrollTween *scrollAnim
+	scrollTween *TweenGroup
+	zoomTween   *TweenGroup
+
+	pixelTarget *ebiten.Image
+
+	effects      []CameraEffect
+	effectOffset CameraOffset
+	shake        *ShakeEffect
+}
+
+// LayerMask is a set of RenderLayer values used to filter what a camera
+// draws. The zero LayerMask includes every layer.
+type LayerMask [4]uint64
+
+// LayerMaskOf returns a mask containing the given render layers.
+func LayerMaskOf(layers ...uint8) LayerMask {
+	var m LayerMask
+	for _, l := range layers {
+		m[l>>6] |= 1 << (l & 63)
+	}
+	return m
+}
+
+// Has reports whether the mask includes layer. The zero mask includes
+// every layer.
+func (m LayerMask) Has(layer uint8) bool {
+	return m == LayerMask{} || m[layer>>6]&(1<<(layer&63)) != 0
 }
 
 // newCamera creates a Camera with default values and the given viewport.
@@ -61,24 +132,74 @@ func newCamera(viewport Rect) *Camera {
 
 // Follow makes the camera track a target node with the given offset and lerp factor.
 // A lerp of 1.0 snaps immediately; lower values give smoother following.
+// The lerp is the fraction of the remaining distance covered per 1/60s and
+// is scaled by the frame's dt, so following feels the same at any tick rate.
 func (c *Camera) Follow(node *Node, offsetX, offsetY, lerp float64) {
 	c.followTarget = node
+	c.followGroup = nil
 	c.followOffsetX = offsetX
 	c.followOffsetY = offsetY
 	c.followLerp = lerp
+	c.followHasPrev = false
+	c.lookX, c.lookY = 0, 0
+}
+
+// FollowGroup keeps several nodes in view, centering on their bounds and
+// zooming so they fit with padding screen pixels to spare on each side.
+// The zoom is clamped to MinZoom and MaxZoom. Lerp smooths position and
+// zoom as in Follow. Nodes that are disposed are ignored.
+func (c *Camera) FollowGroup(nodes []*Node, padding, lerp float64) {
+	c.followTarget = nil
+	c.followGroup = append(c.followGroup[:0], nodes...)
+	c.followPadding = padding
+	c.followLerp = lerp
 }
 
-// Unfollow stops tracking the current target node.
+// Unfollow stops tracking the current target node or group.
 func (c *Camera) Unfollow() {
 	c.followTarget = nil
+	c.followGroup = nil
 }
 
 // ScrollTo animates the camera to the given world position over duration seconds.
 func (c *Camera) ScrollTo(x, y float64, duration float32, easeFn ease.TweenFunc) {
-	c.scrollTween = &scrollAnim{
-		tweenX: gween.New(float32(c.X), float32(x), duration, easeFn),
-		tweenY: gween.New(float32(c.Y), float32(y), duration, easeFn),
+	c.scrollTween = newTweenGroup(nil, duration, easeFn, []*float64{&c.X, &c.Y}, []float64{x, y})
+}
+
+// ZoomTo animates the zoom to the given value, clamped to MinZoom and
+// MaxZoom, over duration seconds.
+func (c *Camera) ZoomTo(zoom float64, duration float32, easeFn ease.TweenFunc) {
+	c.zoomTween = newTweenGroup(nil, duration, easeFn, []*float64{&c.Zoom}, []float64{c.clampZoom(zoom)})
+}
+
+// ZoomAt multiplies the zoom by factor, clamped to MinZoom and MaxZoom,
+// while keeping the world point under screen position (sx, sy) fixed. Use
+// it to zoom around the cursor or a pinch center.
+func (c *Camera) ZoomAt(sx, sy, factor float64) {
+	if factor <= 0 {
+		return
+	}
+	bx, by := c.ScreenToWorld(sx, sy)
+	c.Zoom = c.clampZoom(c.Zoom * factor)
+	c.dirty = true
+	ax, ay := c.ScreenToWorld(sx, sy)
+	c.X += bx - ax
+	c.Y += by - ay
+	c.dirty = true
+	if c.BoundsEnabled {
+		c.clampToBounds()
+	}
+}
+
+// clampZoom limits zoom to MinZoom and MaxZoom.
+func (c *Camera) clampZoom(zoom float64) float64 {
+	if c.MaxZoom > 0 {
+		zoom = math.Min(zoom, c.MaxZoom)
+	}
+	if c.MinZoom > 0 {
+		zoom = math.Max(zoom, c.MinZoom)
 	}
+	return zoom
 }
 
 // ScrollToTile scrolls to the center of the given tile in a tile-based layout.
@@ -116,43 +237,135 @@ func (c *Camera) update(dt float32) {
 
 	// Follow target
 	if c.followTarget != nil && !c.followTarget.IsDisposed() {
-		targetX := c.followTarget.worldTransform[4] + c.followOffsetX
-		targetY := c.followTarget.worldTransform[5] + c.followOffsetY
-		c.X += (targetX - c.X) * c.followLerp
-		c.Y += (targetY - c.Y) * c.followLerp
+		c.updateFollow(dt)
+	} else if len(c.followGroup) > 0 {
+		c.updateFollowGroup(dt)
 	}
 
 	// Scroll animation
 	if c.scrollTween != nil {
-		if !c.scrollTween.doneX {
-			val, done := c.scrollTween.tweenX.Update(dt)
-			c.X = float64(val)
-			c.scrollTween.doneX = done
-		}
-		if !c.scrollTween.doneY {
-			val, done := c.scrollTween.tweenY.Update(dt)
-			c.Y = float64(val)
-			c.scrollTween.doneY = done
-		}
-		if c.scrollTween.doneX && c.scrollTween.doneY {
+		c.scrollTween.Update(dt)
+		if c.scrollTween.Done {
 			c.scrollTween = nil
 		}
 	}
+	if c.zoomTween != nil {
+		c.zoomTween.Update(dt)
+		if c.zoomTween.Done {
+			c.zoomTween = nil
+		}
+	}
 
 	// Bounds clamping
 	if c.BoundsEnabled {
 		c.clampToBounds()
 	}
 
+	// Effects offset the view only; X/Y stay logical.
+	c.updateEffects(dt)
+
 	if c.X != prevX || c.Y != prevY || c.Zoom != prevZoom || c.Rotation != prevRot {
 		c.dirty = true
 	}
 }
 
+// followFactor converts the per-1/60s follow lerp to the fraction covered
+// over dt seconds.
+func (c *Camera) followFactor(dt float32) float64 {
+	if c.followLerp >= 1 {
+		return 1
+	}
+	if c.followLerp <= 0 {
+		return 0
+	}
+	return 1 - math.Pow(1-c.followLerp, float64(dt)*60)
+}
+
+// updateFollow moves toward the followed node, honoring the deadzone and
+// look-ahead.
+func (c *Camera) updateFollow(dt float32) {
+	wx := c.followTarget.worldTransform[4]
+	wy := c.followTarget.worldTransform[5]
+	k := c.followFactor(dt)
+
+	if c.LookAhead > 0 && c.followHasPrev && dt > 0 {
+		lx := (wx - c.followPrevX) / float64(dt) * c.LookAhead
+		ly := (wy - c.followPrevY) / float64(dt) * c.LookAhead
+		if m := c.LookAheadMax; m > 0 {
+			if d := math.Hypot(lx, ly); d > m {
+				lx, ly = lx*m/d, ly*m/d
+			}
+		}
+		// Smooth the lead so jittery movement doesn't shake the view.
+		c.lookX += (lx - c.lookX) * k
+		c.lookY += (ly - c.lookY) * k
+	}
+	c.followPrevX, c.followPrevY = wx, wy
+	c.followHasPrev = true
+
+	targetX := wx + c.followOffsetX + c.lookX
+	targetY := wy + c.followOffsetY + c.lookY
+
+	// Only chase the part of the target that lies outside the deadzone.
+	if dz := c.Deadzone; dz.Width > 0 || dz.Height > 0 {
+		minX, maxX := c.X+dz.X/c.Zoom, c.X+(dz.X+dz.Width)/c.Zoom
+		minY, maxY := c.Y+dz.Y/c.Zoom, c.Y+(dz.Y+dz.Height)/c.Zoom
+		targetX = c.X + math.Min(targetX-minX, 0) + math.Max(targetX-maxX, 0)
+		targetY = c.Y + math.Min(targetY-minY, 0) + math.Max(targetY-maxY, 0)
+	}
+
+	c.X += (targetX - c.X) * k
+	c.Y += (targetY - c.Y) * k
+}
+
+// updateFollowGroup frames every live node of the follow group.
+func (c *Camera) updateFollowGroup(dt float32) {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, n := range c.followGroup {
+		if n.IsDisposed() {
+			continue
+		}
+		x, y := n.worldTransform[4], n.worldTransform[5]
+		minX, maxX = math.Min(minX, x), math.Max(maxX, x)
+		minY, maxY = math.Min(minY, y), math.Max(maxY, y)
+	}
+	if minX > maxX {
+		return
+	}
+
+	zoom := c.Zoom
+	availW := c.Viewport.Width - 2*c.followPadding
+	availH := c.Viewport.Height - 2*c.followPadding
+	if availW > 0 && availH > 0 {
+		zoom = math.Inf(1)
+		if w := maxX - minX; w > 0 {
+			zoom = availW / w
+		}
+		if h := maxY - minY; h > 0 {
+			zoom = math.Min(zoom, availH/h)
+		}
+		zoom = c.clampZoom(zoom)
+		if math.IsInf(zoom, 1) {
+			zoom = c.Zoom
+		}
+	}
+
+	k := c.followFactor(dt)
+	c.X += ((minX+maxX)/2 - c.X) * k
+	c.Y += ((minY+maxY)/2 - c.Y) * k
+	c.Zoom += (zoom - c.Zoom) * k
+}
+
 // clampToBounds restricts camera position so the visible area stays within Bounds.
 func (c *Camera) clampToBounds() {
-	halfW := c.Viewport.Width / (2 * c.Zoom)
-	halfH := c.Viewport.Height / (2 * c.Zoom)
+	w, h, z := c.Viewport.Width, c.Viewport.Height, c.Zoom
+	if c.PixelPerfect {
+		s, _, _, area := c.pixelLayout()
+		w, h, z = area.Width, area.Height, float64(s)
+	}
+	halfW := w / (2 * z)
+	halfH := h / (2 * z)
 
 	minX := c.Bounds.X + halfW
 	maxX := c.Bounds.X + c.Bounds.Width - halfW
@@ -175,19 +388,26 @@ func (c *Camera) clampToBounds() {
 // computeViewMatrix recomputes the cached view matrix if dirty.
 //
 // viewMatrix = Translate(cx, cy) * Scale(zoom) * Rotate(-rotation) * Translate(-X, -Y)
-// where cx, cy = viewport center.
+// where cx, cy = viewport center. The effect offset shifts cx, cy and
+// adjusts zoom and rotation without touching X, Y.
 func (c *Camera) computeViewMatrix() [6]float64 {
 	if !c.dirty {
 		return c.viewMatrix
 	}
 	c.dirty = false
 
-	cx := c.Viewport.X + c.Viewport.Width/2
-	cy := c.Viewport.Y + c.Viewport.Height/2
+	fx := &c.effectOffset
+	cx := c.Viewport.X + c.Viewport.Width/2 + fx.X
+	cy := c.Viewport.Y + c.Viewport.Height/2 + fx.Y
+	z := c.Zoom * (1 + fx.Zoom)
+	if c.PixelPerfect {
+		// Matches the upscaled low-resolution image, so input maps exactly.
+		px, py, scale := c.pixelCenter()
+		cx, cy, z = px+fx.X, py+fx.Y, scale
+	}
 
-	cos := math.Cos(-c.Rotation)
-	sin := math.Sin(-c.Rotation)
-	z := c.Zoom
+	cos := math.Cos(-(c.Rotation + fx.Rotation))
+	sin := math.Sin(-(c.Rotation + fx.Rotation))
 
 	// Combined: Translate(cx,cy) * Scale(z) * Rotate(-rot) * Translate(-X,-Y)
 	// [a b tx]   [z*cos  -z*sin  cx + z*(- cos*X + sin*Y)]
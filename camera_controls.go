@@ -0,0 +1,217 @@
+package willow
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// PanZoomController connects mouse wheel, pinch and drag input to a
+// camera's position and zoom, as used by map viewers and editors. Zoom is
+// limited by the camera's MinZoom and MaxZoom, and panning respects its
+// Bounds. Create one with Scene.NewPanZoomController.
+type PanZoomController struct {
+	// Enabled turns the controller on or off without removing it.
+	Enabled bool
+	// WheelFactor is the zoom multiplier per wheel notch, applied around the
+	// cursor. Zero disables wheel zoom.
+	WheelFactor float64
+	// Pinch zooms around the center of a two-finger pinch.
+	Pinch bool
+	// DragPan pans when a drag with DragButton starts on empty space (no
+	// interactable node under the pointer).
+	DragPan    bool
+	DragButton MouseButton
+	// Friction controls how quickly the pan keeps gliding after a drag is
+	// released; higher values stop sooner. Zero disables inertia.
+	Friction float64
+
+	cam     *Camera
+	scene   *Scene
+	handles []CallbackHandle
+
+	dragging   bool
+	moved      bool
+	velX, velY float64
+}
+
+// NewPanZoomController creates a pan/zoom controller for cam with wheel,
+// pinch and drag enabled, and registers it with the scene.
+func (s *Scene) NewPanZoomController(cam *Camera) *PanZoomController {
+	p := &PanZoomController{
+		Enabled:     true,
+		WheelFactor: 1.1,
+		Pinch:       true,
+		DragPan:     true,
+		DragButton:  MouseButtonLeft,
+		Friction:    6,
+		cam:         cam,
+		scene:       s,
+	}
+	p.handles = append(p.handles,
+		s.OnDragStart(p.onDragStart),
+		s.OnDrag(p.onDrag),
+		s.OnDragEnd(p.onDragEnd),
+		s.OnPinch(p.onPinch),
+	)
+	s.panZoom = append(s.panZoom, p)
+	return p
+}
+
+// Camera returns the camera the controller drives.
+func (p *PanZoomController) Camera() *Camera {
+	return p.cam
+}
+
+// Stop cancels any inertia glide.
+func (p *PanZoomController) Stop() {
+	p.velX, p.velY = 0, 0
+}
+
+// Remove unregisters the controller from its scene.
+func (p *PanZoomController) Remove() {
+	for _, h := range p.handles {
+		h.Remove()
+	}
+	p.handles = nil
+	s := p.scene
+	for i, c := range s.panZoom {
+		if c == p {
+			s.panZoom = append(s.panZoom[:i], s.panZoom[i+1:]...)
+			break
+		}
+	}
+}
+
+// owns reports whether a drag belongs to this controller: an empty-space
+// drag with the pan button, routed to the controller's camera.
+func (p *PanZoomController) owns(ctx DragContext) bool {
+	return p.Enabled && p.DragPan && ctx.Node == nil && ctx.Button == p.DragButton &&
+		!p.scene.pinch.active && p.scene.pointers[ctx.PointerID].camera == p.cam
+}
+
+func (p *PanZoomController) onDragStart(ctx DragContext) {
+	// The drag event fired in the same frame applies the movement.
+	if p.owns(ctx) {
+		p.dragging = true
+		p.Stop()
+	}
+}
+
+func (p *PanZoomController) onDrag(ctx DragContext) {
+	if p.dragging && p.owns(ctx) {
+		p.pan(ctx.ScreenDeltaX, ctx.ScreenDeltaY)
+	}
+}
+
+func (p *PanZoomController) onDragEnd(DragContext) {
+	p.dragging = false
+}
+
+func (p *PanZoomController) onPinch(ctx PinchContext) {
+	if !p.Enabled || !p.Pinch {
+		return
+	}
+	sx, sy, ok := p.pinchCenter(ctx)
+	if !ok {
+		return
+	}
+	p.dragging = false
+	p.Stop()
+	p.cam.ZoomAt(sx, sy, 1+ctx.ScaleDelta)
+}
+
+// pinchCenter returns the pinch midpoint in the controller camera's screen
+// space. The context's world center comes from the camera the touches were
+// routed to, so it is mapped back only through that camera; otherwise the
+// touches' own screen midpoint is used. ok is false when that midpoint is
+// outside the viewport of a camera drawing to the screen.
+func (p *PanZoomController) pinchCenter(ctx PinchContext) (sx, sy float64, ok bool) {
+	s := p.scene
+	ps0, ps1 := &s.pointers[s.pinch.pointer0], &s.pointers[s.pinch.pointer1]
+	routed := ps0.camera
+	if ps0.throughCam != nil && !ps0.throughNode.disposed {
+		routed = ps0.throughCam
+	}
+	if routed == p.cam {
+		sx, sy = p.cam.WorldToScreen(ctx.CenterX, ctx.CenterY)
+		return sx, sy, true
+	}
+	sx = (ps0.lastScreenX + ps1.lastScreenX) / 2
+	sy = (ps0.lastScreenY + ps1.lastScreenY) / 2
+	return sx, sy, p.cam.Target != nil || p.cam.Viewport.Contains(sx, sy)
+}
+
+// pan moves the camera so the world follows a screen-space drag of
+// (dx, dy) pixels, and records the velocity for inertia.
+func (p *PanZoomController) pan(dx, dy float64) {
+	c := p.cam
+	x0, y0 := c.ScreenToWorld(0, 0)
+	x1, y1 := c.ScreenToWorld(dx, dy)
+	wdx, wdy := x1-x0, y1-y0
+	c.X -= wdx
+	c.Y -= wdy
+	c.dirty = true
+	if c.BoundsEnabled {
+		c.clampToBounds()
+	}
+	tps := float64(ebiten.TPS())
+	p.velX, p.velY = -wdx*tps, -wdy*tps
+	p.moved = true
+}
+
+// wheel zooms around screen (sx, sy) by notches wheel steps.
+func (p *PanZoomController) wheel(sx, sy, notches float64) {
+	if p.WheelFactor <= 0 || notches == 0 {
+		return
+	}
+	p.Stop()
+	p.cam.ZoomAt(sx, sy, math.Pow(p.WheelFactor, notches))
+}
+
+// wheelCursor zooms around the raw cursor position (mx, my), converted to
+// the scene's screen coordinates, if it lies in the camera's viewport.
+func (p *PanZoomController) wheelCursor(mx, my int, notches float64) {
+	sx, sy := p.scene.pointerToCanvas(mx, my)
+	if p.cam.Viewport.Contains(sx, sy) {
+		p.wheel(sx, sy, notches)
+	}
+}
+
+// update applies wheel zoom and inertia. Called from Scene.Update().
+func (p *PanZoomController) update(dt float32) {
+	if !p.Enabled {
+		return
+	}
+	// A wheel handler calling PreventDefault (say, on a scrolling list)
+	// keeps the scroll from zooming.
+	if _, wy := ebiten.Wheel(); wy != 0 && p.cam.Target == nil && !p.scene.wheelBlocked {
+		mx, my := ebiten.CursorPosition()
+		p.wheelCursor(mx, my, wy)
+	}
+
+	if p.dragging {
+		// Holding still during a drag leaves nothing to glide with.
+		if !p.moved {
+			p.Stop()
+		}
+		p.moved = false
+		return
+	}
+	if p.Friction <= 0 || (p.velX == 0 && p.velY == 0) {
+		return
+	}
+	c := p.cam
+	c.X += p.velX * float64(dt)
+	c.Y += p.velY * float64(dt)
+	c.dirty = true
+	if c.BoundsEnabled {
+		c.clampToBounds()
+	}
+	k := math.Exp(-p.Friction * float64(dt))
+	p.velX *= k
+	p.velY *= k
+	if math.Hypot(p.velX, p.velY) < 1 {
+		p.Stop()
+	}
+}
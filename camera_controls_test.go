@@ -0,0 +1,172 @@
+package willow
+
+import (
+	"math"
+	"testing"
+
+	"github.com/tanema/gween/ease"
+)
+
+func TestCameraZoomAtKeepsPointFixed(t *testing.T) {
+	cam := newCamera(Rect{Width: 800, Height: 600})
+	cam.X, cam.Y = 100, 100
+	cam.Rotation = 0.3
+	wx, wy := cam.ScreenToWorld(600, 150)
+
+	cam.ZoomAt(600, 150, 2)
+	if cam.Zoom != 2 {
+		t.Errorf("Zoom = %f, want 2", cam.Zoom)
+	}
+	ax, ay := cam.ScreenToWorld(600, 150)
+	if !approxEqual(ax, wx, 1e-9) || !approxEqual(ay, wy, 1e-9) {
+		t.Errorf("point under cursor moved from %f,%f to %f,%f", wx, wy, ax, ay)
+	}
+
+	cam.MaxZoom = 3
+	cam.ZoomAt(0, 0, 10)
+	if cam.Zoom != 3 {
+		t.Errorf("Zoom = %f, want MaxZoom 3", cam.Zoom)
+	}
+}
+
+func TestCameraZoomTo(t *testing.T) {
+	cam := newCamera(Rect{Width: 800, Height: 600})
+	cam.MinZoom = 0.5
+	cam.ZoomTo(0.1, 1, ease.Linear)
+	cam.update(0.5)
+	if !approxEqual(cam.Zoom, 0.75, 1e-6) {
+		t.Errorf("mid ZoomTo = %f, want 0.75", cam.Zoom)
+	}
+	cam.update(0.5)
+	if cam.Zoom != 0.5 || cam.zoomTween != nil {
+		t.Errorf("final Zoom = %f, want clamped 0.5", cam.Zoom)
+	}
+}
+
+func TestPanZoomControllerDragAndInertia(t *testing.T) {
+	s := NewScene()
+	cam := s.NewCamera(Rect{Width: 800, Height: 600})
+	cam.Zoom = 2
+	p := s.NewPanZoomController(cam)
+
+	move := func(x, y float64, pressed bool) {
+		wx, wy := s.pointerToWorld(0, cam, x, y)
+		s.processPointer(0, wx, wy, x, y, pressed, MouseButtonLeft, 0)
+	}
+	move(400, 300, true)
+	move(420, 300, true) // starts the drag: 20px = 10 world units
+	move(440, 300, true)
+	if !approxEqual(cam.X, -20, 1e-9) || cam.Y != 0 {
+		t.Fatalf("after drag cam = %f,%f, want -20,0", cam.X, cam.Y)
+	}
+	move(440, 300, false)
+
+	p.update(0.1)
+	if cam.X >= -20 {
+		t.Errorf("no inertia after release: X = %f", cam.X)
+	}
+	for range 200 {
+		p.update(1.0 / 60)
+	}
+	x := cam.X
+	p.update(1.0 / 60)
+	if cam.X != x {
+		t.Error("inertia never came to rest")
+	}
+
+	// Dragging an interactable node does not pan.
+	n := NewSprite("n", TextureRegion{OriginalW: 800, OriginalH: 600})
+	n.Interactable = true
+	n.X, n.Y = -1000, -1000
+	n.ScaleX, n.ScaleY = 10, 10
+	s.Root().AddChild(n)
+	updateWorldTransform(s.root, identityTransform, 1.0, false, false)
+	move(400, 300, true)
+	move(450, 300, true)
+	if cam.X != x {
+		t.Errorf("dragging a node panned the camera: %f", cam.X)
+	}
+}
+
+func TestPanZoomControllerWheelAndRemove(t *testing.T) {
+	s := NewScene()
+	cam := s.NewCamera(Rect{Width: 800, Height: 600})
+	p := s.NewPanZoomController(cam)
+	cam.MaxZoom = 1.5
+
+	p.wheel(400, 300, 2)
+	if !approxEqual(cam.Zoom, 1.21, 1e-9) {
+		t.Errorf("Zoom = %f, want 1.21", cam.Zoom)
+	}
+	p.wheel(400, 300, 10)
+	if cam.Zoom != 1.5 {
+		t.Errorf("Zoom = %f, want MaxZoom 1.5", cam.Zoom)
+	}
+
+	p.onPinch(PinchContext{ScaleDelta: -0.5})
+	if !approxEqual(cam.Zoom, 0.75, 1e-9) || math.IsNaN(cam.X) {
+		t.Errorf("pinch Zoom = %f", cam.Zoom)
+	}
+
+	p.Remove()
+	if len(s.panZoom) != 0 || len(s.handlers.drag) != 0 || len(s.handlers.pinch) != 0 {
+		t.Error("Remove left the controller registered")
+	}
+}
+
+func TestPanZoomControllerWheelVirtualResolution(t *testing.T) {
+	s := NewScene()
+	cam := s.NewCamera(Rect{Width: 320, Height: 180})
+	p := s.NewPanZoomController(cam)
+	// A 320x180 canvas shown at 4x with a 40px letterbox on the left.
+	l := computeScreenLayout(ScaleModeFit, 320, 180, 1360, 720)
+	s.screenLayout = &l
+
+	wx, wy := cam.ScreenToWorld(300, 170)
+	p.wheelCursor(1240, 680, 1) // canvas (300, 170)
+	if cam.Zoom == 1 {
+		t.Fatal("cursor inside the canvas did not zoom")
+	}
+	ax, ay := cam.ScreenToWorld(300, 170)
+	if !approxEqual(ax, wx, 1e-9) || !approxEqual(ay, wy, 1e-9) {
+		t.Errorf("zoom anchor moved from %f,%f to %f,%f", wx, wy, ax, ay)
+	}
+
+	// Raw (20, 20) is in the letterbox, left of the canvas.
+	z := cam.Zoom
+	p.wheelCursor(20, 20, 1)
+	if cam.Zoom != z {
+		t.Error("cursor in the letterbox zoomed the camera")
+	}
+}
+
+func TestPanZoomControllerPinchOnOtherCamera(t *testing.T) {
+	s := NewScene()
+	screen := s.NewCamera(Rect{Width: 800, Height: 600})
+	screen.X, screen.Y = 1000, 1000
+	cam := s.NewCamera(Rect{Width: 800, Height: 600})
+	cam.Target = NewRenderTexture(800, 600)
+	p := s.NewPanZoomController(cam)
+
+	// Two touches routed to the screen camera, centered on screen (200, 100).
+	for i, x := range []float64{100, 300} {
+		ps := &s.pointers[i+1]
+		ps.camera, ps.lastScreenX, ps.lastScreenY = screen, x, 100
+		ps.lastX, ps.lastY = screen.ScreenToWorld(x, 100)
+	}
+	s.pinch.pointer0, s.pinch.pointer1 = 1, 2
+	cx, cy := screen.ScreenToWorld(200, 100)
+
+	wx, wy := cam.ScreenToWorld(200, 100)
+	p.onPinch(PinchContext{CenterX: cx, CenterY: cy, ScaleDelta: 0.25})
+	if !approxEqual(cam.Zoom, 1.25, 1e-9) {
+		t.Fatalf("Zoom = %f, want 1.25", cam.Zoom)
+	}
+	ax, ay := cam.ScreenToWorld(200, 100)
+	if !approxEqual(ax, wx, 1e-9) || !approxEqual(ay, wy, 1e-9) {
+		t.Errorf("zoom anchor moved from %f,%f to %f,%f", wx, wy, ax, ay)
+	}
+	if screen.Zoom != 1 {
+		t.Error("pinch zoomed the routing camera")
+	}
+}
@@ -0,0 +1,257 @@
+package willow
+
+import (
+	"math"
+
+	"github.com/tanema/gween/ease"
+)
+
+// CameraOffset is an effect's contribution to a camera's view. Offsets are
+// applied on top of the camera's X, Y, Zoom and Rotation when the view
+// matrix is built, so following, scrolling and bounds clamping still see
+// the camera's logical position.
+type CameraOffset struct {
+	// X and Y shift the view in screen pixels.
+	X, Y float64
+	// Rotation is added to the camera rotation, in radians.
+	Rotation float64
+	// Zoom scales the camera zoom by 1+Zoom, so 0.1 zooms in by 10%.
+	Zoom float64
+}
+
+// add accumulates o into c.
+func (c *CameraOffset) add(o CameraOffset) {
+	c.X += o.X
+	c.Y += o.Y
+	c.Rotation += o.Rotation
+	c.Zoom += o.Zoom
+}
+
+// CameraEffect is a temporary view offset such as a shake or zoom punch.
+// Add effects with Camera.AddEffect; their offsets are summed each frame.
+type CameraEffect interface {
+	// Update advances the effect by dt seconds. It returns false once the
+	// effect has finished, and the camera then removes it.
+	Update(dt float32) bool
+	// Offset returns the effect's current contribution to the view.
+	Offset() CameraOffset
+}
+
+// AddEffect pushes an effect onto the camera's effect stack.
+func (c *Camera) AddEffect(e CameraEffect) {
+	c.effects = append(c.effects, e)
+	c.dirty = true
+}
+
+// RemoveEffect removes an effect from the camera's effect stack.
+func (c *Camera) RemoveEffect(e CameraEffect) {
+	for i, ce := range c.effects {
+		if ce == e {
+			c.effects = append(c.effects[:i], c.effects[i+1:]...)
+			c.dirty = true
+			return
+		}
+	}
+}
+
+// ClearEffects removes every effect, including any shake, and resets
+// the shake's trauma.
+func (c *Camera) ClearEffects() {
+	clear(c.effects)
+	c.effects = c.effects[:0]
+	if c.shake != nil {
+		c.shake.Trauma = 0
+	}
+	c.effectOffset = CameraOffset{}
+	c.dirty = true
+}
+
+// Effects returns the camera's active effects. The returned slice MUST NOT
+// be mutated.
+func (c *Camera) Effects() []CameraEffect {
+	return c.effects
+}
+
+// EffectOffset returns the summed offset of the camera's effects for the
+// current frame.
+func (c *Camera) EffectOffset() CameraOffset {
+	return c.effectOffset
+}
+
+// Shake returns the camera's built-in trauma shake, creating it with
+// default settings on first use. Adjust its fields to tune the shake.
+func (c *Camera) Shake() *ShakeEffect {
+	if c.shake == nil {
+		c.shake = NewShakeEffect()
+	}
+	return c.shake
+}
+
+// AddTrauma adds to the built-in shake's trauma, clamped to 1. Shake
+// strength grows with the square of trauma, and trauma decays over time,
+// so small hits barely move the view while repeated hits build up.
+func (c *Camera) AddTrauma(amount float64) {
+	s := c.Shake()
+	s.AddTrauma(amount)
+	for _, e := range c.effects {
+		if e == CameraEffect(s) {
+			return
+		}
+	}
+	c.AddEffect(s)
+}
+
+// ZoomPunch briefly zooms the view by 1+amount and eases back over
+// duration seconds. Negative amounts punch outwards.
+func (c *Camera) ZoomPunch(amount float64, duration float32) *ZoomPunchEffect {
+	p := &ZoomPunchEffect{Amount: amount, Duration: duration, Ease: ease.OutQuad}
+	c.AddEffect(p)
+	return p
+}
+
+// updateEffects advances the effect stack and sums the surviving offsets.
+func (c *Camera) updateEffects(dt float32) {
+	if len(c.effects) == 0 {
+		if c.effectOffset != (CameraOffset{}) {
+			c.effectOffset = CameraOffset{}
+			c.dirty = true
+		}
+		return
+	}
+	var sum CameraOffset
+	live := c.effects[:0]
+	for _, e := range c.effects {
+		if e.Update(dt) {
+			live = append(live, e)
+			sum.add(e.Offset())
+		}
+	}
+	clear(c.effects[len(live):])
+	c.effects = live
+	if sum != c.effectOffset {
+		c.effectOffset = sum
+		c.dirty = true
+	}
+}
+
+// --- ShakeEffect ---
+
+// ShakeEffect shakes the view by an amount driven by trauma. Translation
+// and rotation follow smooth noise rather than random jitter, so the shake
+// reads as a rumble instead of flicker.
+type ShakeEffect struct {
+	// Trauma is the current shake intensity in [0, 1].
+	Trauma float64
+	// Decay is the trauma lost per second.
+	Decay float64
+	// MaxOffset is the largest translation, in screen pixels, at full trauma.
+	MaxOffset float64
+	// MaxRotation is the largest rotation, in radians, at full trauma.
+	MaxRotation float64
+	// Frequency is the noise speed. Higher values shake faster.
+	Frequency float64
+
+	time   float64
+	offset CameraOffset
+}
+
+// NewShakeEffect creates a shake with default settings and no trauma.
+func NewShakeEffect() *ShakeEffect {
+	return &ShakeEffect{
+		Decay:       1,
+		MaxOffset:   16,
+		MaxRotation: 0.05,
+		Frequency:   15,
+	}
+}
+
+// AddTrauma adds to the shake's trauma, clamped to 1.
+func (s *ShakeEffect) AddTrauma(amount float64) {
+	s.Trauma = math.Max(0, math.Min(s.Trauma+amount, 1))
+}
+
+// Update decays trauma and samples the noise. It returns false once
+// trauma reaches zero.
+func (s *ShakeEffect) Update(dt float32) bool {
+	s.time += float64(dt)
+	s.Trauma = math.Max(0, s.Trauma-s.Decay*float64(dt))
+	if s.Trauma <= 0 {
+		s.offset = CameraOffset{}
+		return false
+	}
+	k := s.Trauma * s.Trauma
+	t := s.time * s.Frequency
+	s.offset = CameraOffset{
+		X:        s.MaxOffset * k * noise1D(t, 0),
+		Y:        s.MaxOffset * k * noise1D(t, 1),
+		Rotation: s.MaxRotation * k * noise1D(t, 2),
+	}
+	return true
+}
+
+// Offset returns the shake's current view offset.
+func (s *ShakeEffect) Offset() CameraOffset {
+	return s.offset
+}
+
+// --- ZoomPunchEffect ---
+
+// ZoomPunchEffect zooms the view by 1+Amount and eases back to no offset
+// over Duration seconds.
+type ZoomPunchEffect struct {
+	// Amount is the initial zoom offset; 0.1 starts 10% zoomed in.
+	Amount float64
+	// Duration is the time in seconds to return to normal.
+	Duration float32
+	// Ease shapes the return. Nil is linear.
+	Ease ease.TweenFunc
+
+	elapsed float32
+}
+
+// Update advances the punch. It returns false once Duration has elapsed.
+func (p *ZoomPunchEffect) Update(dt float32) bool {
+	p.elapsed += dt
+	return p.elapsed < p.Duration
+}
+
+// Offset returns the punch's current zoom offset.
+func (p *ZoomPunchEffect) Offset() CameraOffset {
+	if p.elapsed >= p.Duration {
+		return CameraOffset{}
+	}
+	var k float64
+	switch {
+	case p.elapsed <= 0:
+		k = 0
+	case p.Ease == nil:
+		k = float64(p.elapsed / p.Duration)
+	default:
+		k = float64(p.Ease(p.elapsed, 0, 1, p.Duration))
+	}
+	return CameraOffset{Zoom: p.Amount * (1 - k)}
+}
+
+// noise1D returns smooth 1D gradient noise in [-1, 1] at x. Different
+// seeds give independent channels.
+func noise1D(x float64, seed uint32) float64 {
+	i := math.Floor(x)
+	f := x - i
+	g0 := noiseGradient(int64(i), seed)
+	g1 := noiseGradient(int64(i)+1, seed)
+	// Quintic fade keeps the curve smooth across lattice points.
+	u := f * f * f * (f*(f*6-15) + 10)
+	// Gradient noise peaks at 0.5 between lattice points; scale to [-1, 1].
+	return 2 * (g0*f + (g1*(f-1)-g0*f)*u)
+}
+
+// noiseGradient hashes a lattice point to a gradient in [-1, 1].
+func noiseGradient(i int64, seed uint32) float64 {
+	h := uint32(i)*0x9E3779B1 ^ seed*0x85EBCA77
+	h ^= h >> 15
+	h *= 0x2C1B3C6D
+	h ^= h >> 12
+	h *= 0x297A2D39
+	h ^= h >> 15
+	return float64(h)/float64(math.MaxUint32)*2 - 1
+}
@@ -0,0 +1,97 @@
+package willow
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCameraShakeLeavesLogicalPosition(t *testing.T) {
+	cam := newCamera(Rect{Width: 800, Height: 600})
+	cam.X, cam.Y = 100, 50
+	cam.AddTrauma(1)
+	cam.AddTrauma(0.5) // clamped, and not added twice
+	if len(cam.Effects()) != 1 || cam.Shake().Trauma != 1 {
+		t.Fatalf("effects=%d trauma=%f", len(cam.Effects()), cam.Shake().Trauma)
+	}
+
+	cam.update(0.1)
+	if cam.X != 100 || cam.Y != 50 {
+		t.Errorf("logical position moved to %f,%f", cam.X, cam.Y)
+	}
+	off := cam.EffectOffset()
+	if off.X == 0 && off.Y == 0 {
+		t.Error("shake produced no offset")
+	}
+	if math.Abs(off.X) > 16 || math.Abs(off.Y) > 16 || math.Abs(off.Rotation) > 0.05 {
+		t.Errorf("offset %+v exceeds max", off)
+	}
+	sx, sy := cam.WorldToScreen(100, 50)
+	if !approxEqual(sx, 400+off.X, 1e-6) || !approxEqual(sy, 300+off.Y, 1e-6) {
+		t.Errorf("WorldToScreen = %f,%f, want shaken center", sx, sy)
+	}
+
+	// Trauma decays at 1/s; after it runs out the shake is removed.
+	cam.update(1)
+	if len(cam.Effects()) != 0 || cam.EffectOffset() != (CameraOffset{}) {
+		t.Errorf("shake not removed: %d effects, offset %+v", len(cam.Effects()), cam.EffectOffset())
+	}
+	if sx, sy := cam.WorldToScreen(100, 50); !approxEqual(sx, 400, 1e-9) || !approxEqual(sy, 300, 1e-9) {
+		t.Errorf("view not restored: %f,%f", sx, sy)
+	}
+}
+
+func TestCameraShakeIsSmooth(t *testing.T) {
+	s := NewShakeEffect()
+	s.Decay = 0
+	s.Trauma = 1
+	s.Update(1.0 / 60)
+	prev := s.Offset().X
+	for range 120 {
+		s.Update(1.0 / 60)
+		x := s.Offset().X
+		if math.Abs(x-prev) > 8 {
+			t.Fatalf("shake jumped %f px in one frame", x-prev)
+		}
+		prev = x
+	}
+}
+
+func TestCameraZoomPunch(t *testing.T) {
+	cam := newCamera(Rect{Width: 800, Height: 600})
+	p := cam.ZoomPunch(0.5, 1)
+	p.Ease = nil
+	cam.update(0.5)
+	if !approxEqual(cam.EffectOffset().Zoom, 0.25, 1e-6) {
+		t.Errorf("zoom offset = %f, want 0.25", cam.EffectOffset().Zoom)
+	}
+	// Effective zoom is 1.25: one world unit spans 1.25 pixels.
+	x0, _ := cam.WorldToScreen(0, 0)
+	x1, _ := cam.WorldToScreen(1, 0)
+	if !approxEqual(x1-x0, 1.25, 1e-6) || cam.Zoom != 1 {
+		t.Errorf("scale=%f Zoom=%f", x1-x0, cam.Zoom)
+	}
+	cam.update(0.5)
+	if len(cam.Effects()) != 0 || cam.EffectOffset().Zoom != 0 {
+		t.Error("punch not removed after its duration")
+	}
+}
+
+func TestCameraEffectsStackWithBounds(t *testing.T) {
+	cam := newCamera(Rect{Width: 100, Height: 100})
+	cam.SetBounds(Rect{Width: 200, Height: 200})
+	cam.X, cam.Y = 50, 50
+	cam.ZoomPunch(-0.5, 1)
+	cam.AddTrauma(1)
+	cam.update(0.1)
+	// Clamping uses the logical zoom, not the punched one.
+	if cam.X != 50 || cam.Y != 50 {
+		t.Errorf("clamped to %f,%f, want 50,50", cam.X, cam.Y)
+	}
+	if len(cam.Effects()) != 2 {
+		t.Fatalf("effects = %d, want 2", len(cam.Effects()))
+	}
+	cam.ClearEffects()
+	if len(cam.Effects()) != 0 || cam.Shake().Trauma != 0 || cam.EffectOffset() != (CameraOffset{}) {
+		t.Error("ClearEffects left state behind")
+	}
+}
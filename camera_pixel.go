@@ -0,0 +1,99 @@
+package willow
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// pixelLayout returns the pixel-perfect upscale factor, the low-resolution
+// target size (including a one-pixel margin on each side for the sub-pixel
+// offset), and the screen rectangle the upscaled image covers.
+func (c *Camera) pixelLayout() (scale, lw, lh int, area Rect) {
+	vp := c.Viewport
+	if c.PixelWidth > 0 && c.PixelHeight > 0 {
+		scale = max(1, min(int(vp.Width)/c.PixelWidth, int(vp.Height)/c.PixelHeight))
+		w, h := float64(c.PixelWidth*scale), float64(c.PixelHeight*scale)
+		area = Rect{
+			X:      vp.X + math.Floor((vp.Width-w)/2),
+			Y:      vp.Y + math.Floor((vp.Height-h)/2),
+			Width:  w,
+			Height: h,
+		}
+		return scale, c.PixelWidth + 2, c.PixelHeight + 2, area
+	}
+	scale = max(1, int(math.Round(c.Zoom)))
+	lw = int(math.Ceil(vp.Width/float64(scale))) + 2
+	lh = int(math.Ceil(vp.Height/float64(scale))) + 2
+	return scale, lw, lh, vp
+}
+
+// pixelCenter returns the screen position of the camera's X/Y in
+// pixel-perfect mode: the center pixel of the low-resolution target after
+// upscaling.
+func (c *Camera) pixelCenter() (cx, cy, scale float64) {
+	s, lw, lh, area := c.pixelLayout()
+	scale = float64(s)
+	return area.X + float64(lw/2-1)*scale, area.Y + float64(lh/2-1)*scale, scale
+}
+
+// pixelViewMatrix returns the view used to render into the low-resolution
+// target: one texel per world pixel, centered on the camera position
+// snapped down to whole pixels.
+func (c *Camera) pixelViewMatrix() [6]float64 {
+	_, lw, lh, _ := c.pixelLayout()
+	rot := c.Rotation + c.effectOffset.Rotation
+	cos, sin := math.Cos(-rot), math.Sin(-rot)
+	x, y := math.Floor(c.X), math.Floor(c.Y)
+	cx, cy := float64(lw/2), float64(lh/2)
+	return [6]float64{cos, sin, -sin, cos, cx - cos*x + sin*y, cy - sin*x - cos*y}
+}
+
+// ensurePixelTarget returns the camera's low-resolution target, allocating
+// it when the size changes.
+func (c *Camera) ensurePixelTarget(w, h int) *ebiten.Image {
+	if c.pixelTarget != nil {
+		b := c.pixelTarget.Bounds()
+		if b.Dx() == w && b.Dy() == h {
+			return c.pixelTarget
+		}
+		c.pixelTarget.Deallocate()
+	}
+	c.pixelTarget = ebiten.NewImage(w, h)
+	return c.pixelTarget
+}
+
+// releasePixelTarget frees the low-resolution target, if any.
+func (c *Camera) releasePixelTarget() {
+	if c.pixelTarget != nil {
+		c.pixelTarget.Deallocate()
+		c.pixelTarget = nil
+	}
+}
+
+// drawCamera renders cam into its viewport of dst. In pixel-perfect mode
+// the world is drawn into the camera's low-resolution target, which is then
+// upscaled by a whole-number factor with the sub-pixel offset applied.
+func (s *Scene) drawCamera(dst *ebiten.Image, cam *Camera) {
+	if !cam.PixelPerfect {
+		cam.releasePixelTarget()
+		s.drawWithCamera(cameraSubImage(dst, cam.Viewport), cam)
+		return
+	}
+
+	scale, lw, lh, area := cam.pixelLayout()
+	if cam.LetterboxColor.A > 0 {
+		cameraSubImage(dst, cam.Viewport).Fill(cam.LetterboxColor.toRGBA())
+	}
+	low := cam.ensurePixelTarget(lw, lh)
+	low.Clear()
+	s.drawWithCamera(low, cam)
+
+	fx, fy := cam.X-math.Floor(cam.X), cam.Y-math.Floor(cam.Y)
+	var op ebiten.DrawImageOptions
+	op.Filter = ebiten.FilterNearest
+	op.GeoM.Translate(-1-fx, -1-fy)
+	op.GeoM.Scale(float64(scale), float64(scale))
+	op.GeoM.Translate(area.X+cam.effectOffset.X, area.Y+cam.effectOffset.Y)
+	cameraSubImage(dst, area).DrawImage(low, &op)
+}
@@ -0,0 +1,84 @@
+package willow
+
+import (
+	"math"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+func TestCameraPixelLayout(t *testing.T) {
+	cam := newCamera(Rect{X: 10, Width: 640, Height: 360})
+	cam.PixelPerfect = true
+	cam.Zoom = 2.6
+	scale, lw, lh, area := cam.pixelLayout()
+	if scale != 3 || lw != 216 || lh != 122 || area != cam.Viewport {
+		t.Errorf("free layout = %d %dx%d %v", scale, lw, lh, area)
+	}
+
+	// Fixed 320x180 in a 700x400 viewport: 2x, letterboxed.
+	cam.Viewport = Rect{Width: 700, Height: 400}
+	cam.PixelWidth, cam.PixelHeight = 320, 180
+	scale, lw, lh, area = cam.pixelLayout()
+	if scale != 2 || lw != 322 || lh != 182 || area != (Rect{X: 30, Y: 20, Width: 640, Height: 360}) {
+		t.Errorf("fixed layout = %d %dx%d %v", scale, lw, lh, area)
+	}
+}
+
+func TestCameraPixelSnapAndSubPixel(t *testing.T) {
+	cam := newCamera(Rect{Width: 640, Height: 360})
+	cam.PixelPerfect = true
+	cam.PixelWidth, cam.PixelHeight = 320, 180
+	cam.X, cam.Y = 100.25, 50.75
+
+	// The low-res render uses the snapped position: world (100, 50) lands on
+	// the target's center texel.
+	lx, ly := transformPoint(cam.pixelViewMatrix(), 100, 50)
+	if lx != 161 || ly != 91 {
+		t.Errorf("snapped center texel = %f,%f, want 161,91", lx, ly)
+	}
+
+	// Upscaling texel (lx, ly) shifted by the sub-pixel offset must agree
+	// with the screen mapping used for input.
+	sx := (lx - 1 - 0.25) * 2
+	sy := (ly - 1 - 0.75) * 2
+	wx, wy := cam.ScreenToWorld(sx, sy)
+	if !approxEqual(wx, 100, 1e-9) || !approxEqual(wy, 50, 1e-9) {
+		t.Errorf("ScreenToWorld(%f,%f) = %f,%f, want 100,50", sx, sy, wx, wy)
+	}
+	if px, py := cam.WorldToScreen(cam.X, cam.Y); px != 320 || py != 180 {
+		t.Errorf("camera center on screen = %f,%f, want 320,180", px, py)
+	}
+}
+
+func TestCameraPixelPerfectDraw(t *testing.T) {
+	s := NewScene()
+	box := NewSprite("box", TextureRegion{})
+	box.ScaleX, box.ScaleY = 4, 4
+	box.X, box.Y = 10.5, 10
+	s.Root().AddChild(box)
+	cam := s.NewCamera(Rect{Width: 200, Height: 100})
+	cam.PixelPerfect = true
+	cam.Zoom = 4
+	cam.X, cam.Y = 10.5, 10.5
+	cam.LetterboxColor = Color{A: 1}
+
+	s.Draw(ebiten.NewImage(200, 100))
+	if cam.pixelTarget == nil || cam.pixelTarget.Bounds().Dx() != 52 {
+		t.Fatalf("pixel target = %v", cam.pixelTarget)
+	}
+	// The box is drawn at 1:1 in the low-res target, relative to the
+	// snapped camera at (10, 10).
+	if len(s.commands) != 1 {
+		t.Fatalf("commands = %d, want 1", len(s.commands))
+	}
+	if tx := s.commands[0].Transform[4]; math.Abs(float64(tx)-26.5) > 1e-4 {
+		t.Errorf("low-res X = %f, want 26.5", tx)
+	}
+
+	cam.PixelPerfect = false
+	s.Draw(ebiten.NewImage(200, 100))
+	if cam.pixelTarget != nil {
+		t.Error("pixel target kept after leaving pixel-perfect mode")
+	}
+}
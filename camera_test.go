@@ -148,8 +148,9 @@ func TestCameraFollowLerp(t *testing.T) {
 
 	cam.Follow(target, 0, 0, 0.5)
 	cam.update(1.0 / 60.0)
-	// Should move halfway from 0 to 100
-	if !approxEqual(cam.X, 50, epsilon) {
+	// Should move halfway from 0 to 100 (the lerp is per 1/60s, so float32
+	// rounding of dt leaves a tiny error).
+	if !approxEqual(cam.X, 50, 1e-4) {
 		t.Errorf("after lerp 0.5: cam.X = %f, want 50", cam.X)
 	}
 }
@@ -517,3 +518,163 @@ func BenchmarkCulling_10000Nodes(b *testing.B) {
 		scene.Draw(screen)
 	}
 }
+
+func TestCameraFollowFrameRateIndependent(t *testing.T) {
+	target := NewSprite("target", TextureRegion{})
+	target.worldTransform = [6]float64{1, 0, 0, 1, 100, 0}
+
+	at60 := newCamera(Rect{Width: 800, Height: 600})
+	at60.Follow(target, 0, 0, 0.1)
+	for range 60 {
+		at60.update(1.0 / 60.0)
+	}
+	at30 := newCamera(Rect{Width: 800, Height: 600})
+	at30.Follow(target, 0, 0, 0.1)
+	for range 30 {
+		at30.update(1.0 / 30.0)
+	}
+	if !approxEqual(at60.X, at30.X, 1e-3) {
+		t.Errorf("after 1s: 60fps X=%f, 30fps X=%f", at60.X, at30.X)
+	}
+}
+
+func TestCameraFollowDeadzone(t *testing.T) {
+	cam := newCamera(Rect{Width: 800, Height: 600})
+	cam.Deadzone = Rect{X: -50, Y: -50, Width: 100, Height: 100}
+	target := NewSprite("target", TextureRegion{})
+	target.worldTransform = [6]float64{1, 0, 0, 1, 40, -30}
+	cam.Follow(target, 0, 0, 1)
+
+	cam.update(1.0 / 60.0)
+	if cam.X != 0 || cam.Y != 0 {
+		t.Errorf("moved inside deadzone: %f,%f", cam.X, cam.Y)
+	}
+	target.worldTransform[4] = 80
+	cam.update(1.0 / 60.0)
+	if !approxEqual(cam.X, 30, epsilon) || cam.Y != 0 {
+		t.Errorf("cam = %f,%f, want 30,0 (target on the deadzone edge)", cam.X, cam.Y)
+	}
+}
+
+func TestCameraFollowLookAhead(t *testing.T) {
+	cam := newCamera(Rect{Width: 800, Height: 600})
+	cam.LookAhead = 0.5
+	cam.LookAheadMax = 40
+	target := NewSprite("target", TextureRegion{})
+	cam.Follow(target, 0, 0, 1)
+
+	// Moving right at 60px/s: lead 30px.
+	for i := range 3 {
+		target.worldTransform[4] = float64(i)
+		cam.update(1.0 / 60.0)
+	}
+	if !approxEqual(cam.X, 2+30, 1e-3) {
+		t.Errorf("cam.X = %f, want 32", cam.X)
+	}
+	// 600px/s would lead 300px; capped at 40.
+	target.worldTransform[4] = 12
+	cam.update(1.0 / 60.0)
+	if !approxEqual(cam.X, 12+40, 1e-3) {
+		t.Errorf("capped cam.X = %f, want 52", cam.X)
+	}
+}
+
+func TestCameraFollowGroup(t *testing.T) {
+	cam := newCamera(Rect{Width: 800, Height: 600})
+	a := NewSprite("a", TextureRegion{})
+	b := NewSprite("b", TextureRegion{})
+	a.worldTransform = [6]float64{1, 0, 0, 1, 0, 0}
+	b.worldTransform = [6]float64{1, 0, 0, 1, 1400, 100}
+	cam.MinZoom, cam.MaxZoom = 0.25, 2
+	cam.FollowGroup([]*Node{a, b}, 50, 1)
+
+	cam.update(1.0 / 60.0)
+	// 700px of room across 1400 world units.
+	if cam.X != 700 || cam.Y != 50 || !approxEqual(cam.Zoom, 0.5, epsilon) {
+		t.Errorf("cam = %f,%f zoom %f, want 700,50 zoom 0.5", cam.X, cam.Y, cam.Zoom)
+	}
+	b.worldTransform[4] = 10
+	cam.update(1.0 / 60.0)
+	if cam.Zoom != 2 {
+		t.Errorf("zoom = %f, want MaxZoom 2", cam.Zoom)
+	}
+	b.Dispose()
+	cam.update(1.0 / 60.0)
+	if cam.X != 0 || cam.Y != 0 {
+		t.Errorf("disposed node still framed: %f,%f", cam.X, cam.Y)
+	}
+}
+
+func TestLayerMask(t *testing.T) {
+	var all LayerMask
+	if !all.Has(0) || !all.Has(255) {
+		t.Error("zero mask should include every layer")
+	}
+	m := LayerMaskOf(1, 200)
+	if !m.Has(1) || !m.Has(200) || m.Has(0) || m.Has(255) {
+		t.Errorf("LayerMaskOf(1, 200) = %v", m)
+	}
+}
+
+func TestCameraRenderMask(t *testing.T) {
+	s := NewScene()
+	world := NewSprite("world", TextureRegion{Width: 10, Height: 10, OriginalW: 10, OriginalH: 10})
+	hud := NewSprite("hud", TextureRegion{Width: 10, Height: 10, OriginalW: 10, OriginalH: 10})
+	hud.RenderLayer = 5
+	s.Root().AddChild(world)
+	s.Root().AddChild(hud)
+
+	cam := s.NewCamera(Rect{Width: 100, Height: 100})
+	cam.CullEnabled = false
+	cam.X, cam.Y = 50, 50
+	traverseSceneWithCamera(s, cam)
+	if len(s.commands) != 2 {
+		t.Fatalf("unmasked commands = %d, want 2", len(s.commands))
+	}
+	cam.RenderMask = LayerMaskOf(5)
+	s.filterCommandsByLayer(cam.RenderMask)
+	if len(s.commands) != 1 || s.commands[0].RenderLayer != 5 {
+		t.Errorf("masked commands = %+v", s.commands)
+	}
+}
+
+func TestCameraRenderMaskRoutesInput(t *testing.T) {
+	s := NewScene()
+	world := NewSprite("world", TextureRegion{OriginalW: 100, OriginalH: 100})
+	world.Interactable = true
+	hud := NewSprite("hud", TextureRegion{OriginalW: 20, OriginalH: 20})
+	hud.Interactable = true
+	hud.RenderLayer = 1
+	s.Root().AddChild(world)
+	s.Root().AddChild(hud)
+	updateWorldTransform(s.root, identityTransform, 1.0, false, false)
+
+	worldCam := s.NewCamera(Rect{Width: 100, Height: 100})
+	worldCam.X, worldCam.Y = 50, 50
+	worldCam.RenderMask = LayerMaskOf(0)
+	hudCam := s.NewCamera(Rect{Width: 100, Height: 100})
+	hudCam.X, hudCam.Y = 50, 50
+	hudCam.RenderMask = LayerMaskOf(1)
+
+	var downs []string
+	s.OnPointerDown(func(ctx PointerContext) {
+		if ctx.Node != nil {
+			downs = append(downs, ctx.Node.Name)
+		}
+	})
+	press := func(x, y float64, pressed bool) {
+		wx, wy := s.pointerToWorld(0, worldCam, x, y)
+		s.processPointer(0, wx, wy, x, y, pressed, MouseButtonLeft, 0)
+	}
+	// Over the HUD sprite the top camera wins; elsewhere the HUD camera has
+	// nothing under the pointer, so the world camera receives it.
+	press(10, 10, true)
+	press(10, 10, false)
+	press(60, 60, true)
+	if len(downs) != 2 || downs[0] != "hud" || downs[1] != "world" {
+		t.Errorf("downs = %v, want [hud world]", downs)
+	}
+	if s.pointers[0].camera != worldCam {
+		t.Error("pressed pointer should be routed to the world camera")
+	}
+}
@@ -320,7 +320,7 @@ func (s *Scene) InjectDrag(fromX, fromY, toX, toY float64, frames int) {
 }
 
 // processInjectedInput pops one event from the inject queue, converts
-// screen→world via the primary camera, and feeds it through processPointer.
+// screen→world via the routed camera, and feeds it through processPointer.
 // Returns true if an event was consumed (real mouse input should be skipped).
 func (s *Scene) processInjectedInput(cam *Camera, mods KeyModifiers) bool {
 	if len(s.injectQueue) == 0 {
@@ -330,7 +330,7 @@ func (s *Scene) processInjectedInput(cam *Camera, mods KeyModifiers) bool {
 	copy(s.injectQueue, s.injectQueue[1:])
 	s.injectQueue = s.injectQueue[:len(s.injectQueue)-1]
 
-	wx, wy := screenToWorld(cam, evt.screenX, evt.screenY)
+	wx, wy := s.pointerToWorld(0, cam, evt.screenX, evt.screenY)
 	s.processPointer(0, wx, wy, evt.screenX, evt.screenY, evt.pressed, evt.button, mods)
 	return true
 }
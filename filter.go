@@ -183,12 +183,17 @@ type ColorMatrixFilter struct {
 	matrixF32   [20]float32 // persistent buffer to avoid per-frame slice escape
 	matrixSlice []float32   // persistent slice header pointing into matrixF32
 	shaderOp    ebiten.DrawRectShaderOptions
+
+	// Values last passed to SetBrightness, SetContrast and SetSaturation.
+	brightness, contrast, saturation float64
 }
 
 // NewColorMatrixFilter creates a color matrix filter initialized to the identity.
 func NewColorMatrixFilter() *ColorMatrixFilter {
 	f := &ColorMatrixFilter{
-		uniforms: make(map[string]any, 1),
+		uniforms:   make(map[string]any, 1),
+		contrast:   1,
+		saturation: 1,
 	}
 	f.matrixSlice = f.matrixF32[:]
 	f.uniforms["Matrix"] = f.matrixSlice
@@ -202,6 +207,7 @@ func NewColorMatrixFilter() *ColorMatrixFilter {
 
 // SetBrightness sets the matrix to adjust brightness by the given offset [-1, 1].
 func (f *ColorMatrixFilter) SetBrightness(b float64) {
+	f.brightness = b
 	f.Matrix = [20]float64{
 		1, 0, 0, 0, b,
 		0, 1, 0, 0, b,
@@ -212,6 +218,7 @@ func (f *ColorMatrixFilter) SetBrightness(b float64) {
 
 // SetContrast sets the matrix to adjust contrast. c=1 is normal, 0=gray, >1 is higher.
 func (f *ColorMatrixFilter) SetContrast(c float64) {
+	f.contrast = c
 	t := (1.0 - c) / 2.0
 	f.Matrix = [20]float64{
 		c, 0, 0, 0, t,
@@ -223,6 +230,7 @@ func (f *ColorMatrixFilter) SetContrast(c float64) {
 
 // SetSaturation sets the matrix to adjust saturation. s=1 is normal, 0=grayscale.
 func (f *ColorMatrixFilter) SetSaturation(s float64) {
+	f.saturation = s
 	sr := (1 - s) * 0.299
 	sg := (1 - s) * 0.587
 	sb := (1 - s) * 0.114
@@ -234,6 +242,15 @@ func (f *ColorMatrixFilter) SetSaturation(s float64) {
 	}
 }
 
+// Brightness returns the value last passed to SetBrightness (0 by default).
+func (f *ColorMatrixFilter) Brightness() float64 { return f.brightness }
+
+// Contrast returns the value last passed to SetContrast (1 by default).
+func (f *ColorMatrixFilter) Contrast() float64 { return f.contrast }
+
+// Saturation returns the value last passed to SetSaturation (1 by default).
+func (f *ColorMatrixFilter) Saturation() float64 { return f.saturation }
+
 // Apply renders the color matrix transformation from src into dst.
 func (f *ColorMatrixFilter) Apply(src, dst *ebiten.Image) {
 	shader := ensureColorMatrixShader()
@@ -0,0 +1,356 @@
+package willow
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// FocusPointerID is the PointerID reported in the ClickContext when a
+// focused node is activated with the keyboard or a gamepad.
+const FocusPointerID = -1
+
+// FocusContext carries focus change data passed to OnFocus and OnBlur
+// callbacks.
+type FocusContext struct {
+	Node     *Node  // the node gaining (OnFocus) or losing (OnBlur) focus
+	EntityID uint32 // the node's EntityID (for ECS bridging)
+	UserData any    // the node's UserData
+	Related  *Node  // the node losing (OnFocus) or gaining (OnBlur) focus, or nil
+}
+
+// FocusDirection is a direction for spatial focus navigation.
+type FocusDirection uint8
+
+const (
+	// FocusUp moves toward smaller world Y.
+	FocusUp FocusDirection = iota
+	// FocusDown moves toward larger world Y.
+	FocusDown
+	// FocusLeft moves toward smaller world X.
+	FocusLeft
+	// FocusRight moves toward larger world X.
+	FocusRight
+)
+
+type focusHandler struct {
+	id uint32
+	fn func(FocusContext)
+}
+
+func removeFocusHandler(s []focusHandler, id uint32) []focusHandler {
+	for i := range s {
+		if s[i].id == id {
+			copy(s[i:], s[i+1:])
+			s[len(s)-1] = focusHandler{}
+			return s[:len(s)-1]
+		}
+	}
+	return s
+}
+
+// OnFocus registers a scene-level callback for nodes gaining focus.
+// Multiple registrations are additive. Use CallbackHandle.Remove to unregister.
+func (s *Scene) OnFocus(fn func(FocusContext)) CallbackHandle {
+	s.handlers.nextID++
+	id := s.handlers.nextID
+	s.handlers.focus = append(s.handlers.focus, focusHandler{id: id, fn: fn})
+	return CallbackHandle{id: id, reg: &s.handlers, event: EventFocus}
+}
+
+// OnBlur registers a scene-level callback for nodes losing focus.
+// Multiple registrations are additive. Use CallbackHandle.Remove to unregister.
+func (s *Scene) OnBlur(fn func(FocusContext)) CallbackHandle {
+	s.handlers.nextID++
+	id := s.handlers.nextID
+	s.handlers.blur = append(s.handlers.blur, focusHandler{id: id, fn: fn})
+	return CallbackHandle{id: id, reg: &s.handlers, event: EventBlur}
+}
+
+// --- Focus ---
+
+// Focused returns the node that has keyboard focus, or nil.
+func (s *Scene) Focused() *Node {
+	if s.focused != nil && s.focused.disposed {
+		s.focused = nil
+	}
+	return s.focused
+}
+
+// SetFocus moves keyboard focus to n, firing OnBlur on the previously
+// focused node and then OnFocus on n. Pass nil to clear focus. Any node can
+// be focused directly; navigation only visits Focusable ones.
+func (s *Scene) SetFocus(n *Node) {
+	prev := s.Focused()
+	if n == prev || (n != nil && n.disposed) {
+		return
+	}
+	s.focused = n
+	if prev != nil {
+		s.fireFocus(EventBlur, prev, n)
+	}
+	if n != nil {
+		s.fireFocus(EventFocus, n, prev)
+	}
+}
+
+// SetFocusNavigation turns built-in keyboard and gamepad focus navigation
+// on or off (default off). When on, Scene.Update reads Tab / Shift+Tab, the
+// arrow keys, Enter, the d-pad and the A button to move and activate focus.
+// Leave it off if the game binds those inputs itself; SetFocus, FocusNext,
+// MoveFocus and ActivateFocus work either way.
+func (s *Scene) SetFocusNavigation(enabled bool) {
+	s.focusNav = enabled
+}
+
+// checkFocus clears focus, firing OnBlur, when the focused node has been
+// disposed, hidden (directly or through an ancestor) or removed from the
+// scene.
+// Called from Scene.Update().
+func (s *Scene) checkFocus() {
+	n := s.focused
+	if n == nil {
+		return
+	}
+	if n.disposed {
+		s.focused = nil
+		return
+	}
+	for p := n; p != nil; p = p.Parent {
+		if !p.Visible {
+			s.SetFocus(nil)
+			return
+		}
+		if p == s.root {
+			return
+		}
+	}
+	s.SetFocus(nil)
+}
+
+// FocusNext moves focus to the next focusable node in tab order, wrapping
+// around at the end. Tab order is painter order, so nodes drawn later come
+// later. Reports whether a node is focused afterwards.
+func (s *Scene) FocusNext() bool {
+	return s.focusStep(1)
+}
+
+// FocusPrevious moves focus to the previous focusable node in tab order,
+// wrapping around at the start. Reports whether a node is focused
+// afterwards.
+func (s *Scene) FocusPrevious() bool {
+	return s.focusStep(-1)
+}
+
+// MoveFocus moves focus to the nearest focusable node in direction dir from
+// the focused node, comparing node centers in world space. With nothing
+// focused it focuses the first node in tab order. Reports whether focus
+// moved.
+func (s *Scene) MoveFocus(dir FocusDirection) bool {
+	s.checkFocus()
+	nodes := s.focusables()
+	cur := s.Focused()
+	if cur == nil {
+		if len(nodes) == 0 {
+			return false
+		}
+		s.SetFocus(nodes[0])
+		return true
+	}
+
+	cx, cy := focusCenter(cur)
+	var best *Node
+	bestScore := math.Inf(1)
+	for _, n := range nodes {
+		if n == cur {
+			continue
+		}
+		x, y := focusCenter(n)
+		dx, dy := x-cx, y-cy
+		// Distance along the direction of travel and off to the side.
+		var along, side float64
+		switch dir {
+		case FocusUp:
+			along, side = -dy, dx
+		case FocusDown:
+			along, side = dy, dx
+		case FocusLeft:
+			along, side = -dx, dy
+		case FocusRight:
+			along, side = dx, dy
+		}
+		if along <= 0 {
+			continue
+		}
+		// Favor nodes in line with the focused one over closer diagonal ones.
+		if score := along + 2*math.Abs(side); score < bestScore {
+			best, bestScore = n, score
+		}
+	}
+	if best == nil {
+		return false
+	}
+	s.SetFocus(best)
+	return true
+}
+
+// ActivateFocus fires OnClick on the focused node, as if it were clicked at
+// its center with the left button. The ClickContext's PointerID is
+// FocusPointerID. Reports whether a node was focused.
+func (s *Scene) ActivateFocus() bool {
+	s.checkFocus()
+	n := s.focused
+	if n == nil {
+		return false
+	}
+	wx, wy := focusCenter(n)
+	s.fireClick(n, FocusPointerID, wx, wy, MouseButtonLeft, readModifiers())
+	return true
+}
+
+// focusables returns the Focusable nodes in tab order, reusing the
+// painter-order walk used for hit testing.
+func (s *Scene) focusables() []*Node {
+	s.focusBuf = s.collectInteractable(s.root, s.focusBuf[:0])
+	nodes := s.focusBuf[:0]
+	for _, n := range s.focusBuf {
+		if n.Focusable {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+func (s *Scene) focusStep(step int) bool {
+	nodes := s.focusables()
+	if len(nodes) == 0 {
+		return s.Focused() != nil
+	}
+	i := -1
+	cur := s.Focused()
+	for j, n := range nodes {
+		if n == cur {
+			i = j
+			break
+		}
+	}
+	switch {
+	case i >= 0:
+		i = (i + step + len(nodes)) % len(nodes)
+	case step > 0:
+		i = 0
+	default:
+		i = len(nodes) - 1
+	}
+	s.SetFocus(nodes[i])
+	return true
+}
+
+// focusCenter returns the world-space center of a node's hit region.
+func focusCenter(n *Node) (float64, float64) {
+	var lx, ly float64
+	switch sh := n.HitShape.(type) {
+	case HitRect:
+		lx, ly = sh.X+sh.Width/2, sh.Y+sh.Height/2
+	case HitCircle:
+		lx, ly = sh.CenterX, sh.CenterY
+	default:
+		w, h := nodeDimensions(n)
+		lx, ly = w/2, h/2
+	}
+	return n.LocalToWorld(lx, ly)
+}
+
+func (s *Scene) fireFocus(eventType EventType, node, related *Node) {
+	ctx := FocusContext{
+		Node: node, EntityID: node.EntityID, UserData: node.UserData,
+		Related: related,
+	}
+	handlers, cb := s.handlers.focus, node.OnFocus
+	if eventType == EventBlur {
+		handlers, cb = s.handlers.blur, node.OnBlur
+	}
+	for _, h := range handlers {
+		h.fn(ctx)
+	}
+	if cb != nil {
+		cb(ctx)
+	}
+	wx, wy := focusCenter(node)
+	s.emitInteractionEvent(eventType, node, wx, wy, 0, 0, MouseButtonLeft, 0, DragContext{}, PinchContext{})
+}
+
+// --- Keyboard and gamepad navigation ---
+
+// focusInput is one tick of focus navigation input.
+type focusInput struct {
+	next, prev bool
+	dir        FocusDirection
+	move       bool
+	activate   bool
+}
+
+// readFocusInput reads Tab / Shift+Tab, the arrow keys and Enter from the
+// keyboard, and the d-pad and A button (StandardGamepadButtonRightBottom)
+// from any gamepad.
+func readFocusInput() focusInput {
+	var in focusInput
+	if inpututil.IsKeyJustPressed(ebiten.KeyTab) {
+		if readModifiers()&ModShift != 0 {
+			in.prev = true
+		} else {
+			in.next = true
+		}
+	}
+	in.activate = inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyNumpadEnter)
+
+	keys := [...]ebiten.Key{ebiten.KeyArrowUp, ebiten.KeyArrowDown, ebiten.KeyArrowLeft, ebiten.KeyArrowRight}
+	pad := [...]ebiten.StandardGamepadButton{
+		ebiten.StandardGamepadButtonLeftTop, ebiten.StandardGamepadButtonLeftBottom,
+		ebiten.StandardGamepadButtonLeftLeft, ebiten.StandardGamepadButtonLeftRight,
+	}
+	for d, k := range keys {
+		if inpututil.IsKeyJustPressed(k) {
+			in.dir, in.move = FocusDirection(d), true
+		}
+	}
+	var ids [4]ebiten.GamepadID
+	for _, id := range ebiten.AppendGamepadIDs(ids[:0]) {
+		if !ebiten.IsStandardGamepadLayoutAvailable(id) {
+			continue
+		}
+		for d, b := range pad {
+			if inpututil.IsStandardGamepadButtonJustPressed(id, b) {
+				in.dir, in.move = FocusDirection(d), true
+			}
+		}
+		if inpututil.IsStandardGamepadButtonJustPressed(id, ebiten.StandardGamepadButtonRightBottom) {
+			in.activate = true
+		}
+	}
+	return in
+}
+
+// processFocus applies focus navigation input. Called from Scene.Update()
+// when focus navigation is enabled.
+// Directional input and activation only apply while a node is focused, so
+// games using the arrow keys for movement are unaffected until a menu takes
+// focus.
+func (s *Scene) processFocus(in focusInput) {
+	switch {
+	case in.next:
+		s.FocusNext()
+	case in.prev:
+		s.FocusPrevious()
+	}
+	if s.Focused() == nil {
+		return
+	}
+	if in.move {
+		s.MoveFocus(in.dir)
+	}
+	if in.activate {
+		s.ActivateFocus()
+	}
+}
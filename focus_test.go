@@ -0,0 +1,187 @@
+package willow
+
+import "testing"
+
+// focusGrid builds three 50x50 focusable buttons: a at (0,0), b at (100,0)
+// and c at (0,100), in that painter order.
+func focusGrid() (s *Scene, a, b, c *Node) {
+	s = NewScene()
+	mk := func(name string, x, y float64) *Node {
+		n := NewSprite(name, TextureRegion{OriginalW: 50, OriginalH: 50})
+		n.X, n.Y = x, y
+		n.Interactable = true
+		n.Focusable = true
+		s.Root().AddChild(n)
+		return n
+	}
+	a, b, c = mk("a", 0, 0), mk("b", 100, 0), mk("c", 0, 100)
+	updateWorldTransform(s.root, identityTransform, 1.0, false, false)
+	return s, a, b, c
+}
+
+func TestSetFocusCallbacks(t *testing.T) {
+	s, a, b, _ := focusGrid()
+	var log []string
+	a.OnFocus = func(ctx FocusContext) { log = append(log, "focus a") }
+	a.OnBlur = func(ctx FocusContext) {
+		if ctx.Related != b {
+			t.Error("blur Related should be the new focus")
+		}
+		log = append(log, "blur a")
+	}
+	h := s.OnFocus(func(ctx FocusContext) { log = append(log, "scene focus "+ctx.Node.Name) })
+
+	s.SetFocus(a)
+	s.SetFocus(a) // no-op
+	s.SetFocus(b)
+	want := []string{"scene focus a", "focus a", "blur a", "scene focus b"}
+	if len(log) != len(want) {
+		t.Fatalf("log = %v, want %v", log, want)
+	}
+	for i := range want {
+		if log[i] != want[i] {
+			t.Fatalf("log = %v, want %v", log, want)
+		}
+	}
+
+	h.Remove()
+	if len(s.handlers.focus) != 0 {
+		t.Error("Remove left the focus handler")
+	}
+	b.Dispose()
+	if s.Focused() != nil {
+		t.Error("disposed node still focused")
+	}
+}
+
+func TestFocusTabOrder(t *testing.T) {
+	s, a, b, c := focusGrid()
+	b.Focusable = false
+
+	s.FocusNext()
+	if s.Focused() != a {
+		t.Fatalf("first Tab focused %v, want a", s.Focused())
+	}
+	s.FocusNext()
+	if s.Focused() != c {
+		t.Errorf("Tab skipped to %v, want c (b is not focusable)", s.Focused())
+	}
+	s.FocusNext()
+	if s.Focused() != a {
+		t.Errorf("Tab did not wrap to a")
+	}
+	s.FocusPrevious()
+	if s.Focused() != c {
+		t.Errorf("Shift+Tab did not wrap to c")
+	}
+
+	c.Visible = false
+	s.FocusNext()
+	if s.Focused() != a {
+		t.Errorf("Tab from hidden node focused %v, want a", s.Focused())
+	}
+}
+
+func TestMoveFocusDirectional(t *testing.T) {
+	s, a, b, c := focusGrid()
+	s.SetFocus(a)
+
+	if !s.MoveFocus(FocusRight) || s.Focused() != b {
+		t.Fatalf("right from a focused %v, want b", s.Focused())
+	}
+	// c is below a, so down from b picks it as the only candidate.
+	if !s.MoveFocus(FocusDown) || s.Focused() != c {
+		t.Fatalf("down from b focused %v, want c", s.Focused())
+	}
+	if !s.MoveFocus(FocusUp) || s.Focused() != a {
+		t.Errorf("up from c focused %v, want a (in line beats diagonal)", s.Focused())
+	}
+	if s.MoveFocus(FocusLeft) || s.Focused() != a {
+		t.Error("left from a should not move")
+	}
+}
+
+func TestFocusActivationAndInput(t *testing.T) {
+	s, a, b, _ := focusGrid()
+	var clicked *Node
+	var ctx ClickContext
+	click := func(c ClickContext) { clicked, ctx = c.Node, c }
+	a.OnClick, b.OnClick = click, click
+
+	// Arrows and Enter are ignored until something has focus.
+	s.processFocus(focusInput{move: true, dir: FocusRight, activate: true})
+	if s.Focused() != nil || clicked != nil {
+		t.Fatal("navigation acted without focus")
+	}
+
+	s.processFocus(focusInput{next: true})
+	s.processFocus(focusInput{move: true, dir: FocusRight, activate: true})
+	if clicked != b {
+		t.Fatalf("activation clicked %v, want b", clicked)
+	}
+	if ctx.PointerID != FocusPointerID || ctx.GlobalX != 125 || ctx.GlobalY != 25 {
+		t.Errorf("ctx = %+v, want FocusPointerID at b's center", ctx)
+	}
+
+	// Pressing the pointer on a focusable node focuses it.
+	s.processPointer(0, 10, 10, 10, 10, true, MouseButtonLeft, 0)
+	if s.Focused() != a {
+		t.Errorf("pointer press focused %v, want a", s.Focused())
+	}
+}
+
+func TestFocusNavigationOptIn(t *testing.T) {
+	s := NewScene()
+	if s.focusNav {
+		t.Fatal("focus navigation enabled by default")
+	}
+	s.SetFocusNavigation(true)
+	if !s.focusNav {
+		t.Error("SetFocusNavigation(true) not applied")
+	}
+}
+
+func TestFocusClearedWhenHidden(t *testing.T) {
+	s, a, b, _ := focusGrid()
+	var blurs int
+	a.OnBlur = func(FocusContext) { blurs++ }
+	var clicked bool
+	a.OnClick = func(ClickContext) { clicked = true }
+
+	s.SetFocus(a)
+	a.Visible = false
+	if s.ActivateFocus() || clicked {
+		t.Fatal("hidden node was activated")
+	}
+	if s.Focused() != nil || blurs != 1 {
+		t.Errorf("hidden node kept focus (blurs = %d)", blurs)
+	}
+
+	// Hiding an ancestor or removing the node drops focus too.
+	a.Visible = true
+	parent := NewContainer("parent")
+	s.Root().AddChild(parent)
+	parent.AddChild(b)
+	s.SetFocus(b)
+	parent.Visible = false
+	s.checkFocus()
+	if s.Focused() != nil {
+		t.Error("node under a hidden parent kept focus")
+	}
+	parent.Visible = true
+	s.SetFocus(b)
+	parent.RemoveChild(b)
+	s.checkFocus()
+	if s.Focused() != nil {
+		t.Error("detached node kept focus")
+	}
+}
+
+func TestFocusClearedByEmptyPress(t *testing.T) {
+	s, a, _, _ := focusGrid()
+	s.SetFocus(a)
+	clickAt(s, 300, 300)
+	if s.Focused() != nil {
+		t.Errorf("press on empty space left %v focused", s.Focused())
+	}
+}
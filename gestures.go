@@ -0,0 +1,190 @@
+package willow
+
+import "math"
+
+type wheelHandler struct {
+	id uint32
+	fn func(WheelContext)
+}
+
+func removeWheelHandler(s []wheelHandler, id uint32) []wheelHandler {
+	for i := range s {
+		if s[i].id == id {
+			copy(s[i:], s[i+1:])
+			s[len(s)-1] = wheelHandler{}
+			return s[:len(s)-1]
+		}
+	}
+	return s
+}
+
+// OnDoubleClick registers a scene-level callback for double-click events.
+// Multiple registrations are additive. Use CallbackHandle.Remove to unregister.
+func (s *Scene) OnDoubleClick(fn func(ClickContext)) CallbackHandle {
+	s.handlers.nextID++
+	id := s.handlers.nextID
+	s.handlers.doubleClick = append(s.handlers.doubleClick, clickHandler{id: id, fn: fn})
+	return CallbackHandle{id: id, reg: &s.handlers, event: EventDoubleClick}
+}
+
+// OnLongPress registers a scene-level callback for long-press events.
+// Multiple registrations are additive. Use CallbackHandle.Remove to unregister.
+func (s *Scene) OnLongPress(fn func(PointerContext)) CallbackHandle {
+	s.handlers.nextID++
+	id := s.handlers.nextID
+	s.handlers.longPress = append(s.handlers.longPress, pointerHandler{id: id, fn: fn})
+	return CallbackHandle{id: id, reg: &s.handlers, event: EventLongPress}
+}
+
+// OnWheel registers a scene-level callback for mouse wheel events.
+// Multiple registrations are additive. Use CallbackHandle.Remove to unregister.
+func (s *Scene) OnWheel(fn func(WheelContext)) CallbackHandle {
+	s.handlers.nextID++
+	id := s.handlers.nextID
+	s.handlers.wheel = append(s.handlers.wheel, wheelHandler{id: id, fn: fn})
+	return CallbackHandle{id: id, reg: &s.handlers, event: EventWheel}
+}
+
+// SetDoubleClickTime sets the longest gap in seconds between two clicks on
+// the same node, with the same button, for them to count as a double-click
+// (default 0.3). The clicks must also land within the drag dead zone of
+// each other. Zero disables double-clicks.
+func (s *Scene) SetDoubleClickTime(seconds float32) {
+	s.doubleClickTime = float64(seconds)
+}
+
+// SetLongPressTime sets how long in seconds a pointer must be held without
+// moving past the drag dead zone to fire a long press (default 0.5). Zero
+// disables long presses.
+func (s *Scene) SetLongPressTime(seconds float32) {
+	s.longPressTime = float64(seconds)
+}
+
+// --- Detection ---
+
+// detectDoubleClick fires a double-click if the click just fired on node
+// pairs with the pointer's previous one, and otherwise remembers it.
+func (s *Scene) detectDoubleClick(ps *pointerState, node *Node, pointerID int, wx, wy float64, mods KeyModifiers) {
+	dx, dy := wx-ps.clickX, wy-ps.clickY
+	if ps.clickNode == node && ps.clickButton == ps.button &&
+		s.inputTime-ps.clickTime <= s.doubleClickTime &&
+		math.Sqrt(dx*dx+dy*dy) <= s.dragDeadZone {
+		// A third click starts a new pair.
+		ps.clickNode = nil
+		s.fireDoubleClick(node, pointerID, wx, wy, ps.button, mods)
+		return
+	}
+	ps.clickNode, ps.clickButton = node, ps.button
+	ps.clickTime, ps.clickX, ps.clickY = s.inputTime, wx, wy
+}
+
+// detectLongPress fires a long press once a held pointer has stayed within
+// the drag dead zone for the long-press time.
+func (s *Scene) detectLongPress(ps *pointerState, pointerID int, wx, wy float64, mods KeyModifiers) {
+	if ps.longPressed || ps.dragging || s.longPressTime <= 0 || s.pinch.active ||
+		s.inputTime-ps.pressTime < s.longPressTime {
+		return
+	}
+	dx, dy := wx-ps.startX, wy-ps.startY
+	if math.Sqrt(dx*dx+dy*dy) > s.dragDeadZone {
+		return
+	}
+	ps.longPressed = true
+	s.fireLongPress(ps.hitNode, pointerID, wx, wy, ps.button, mods)
+}
+
+// processWheel delivers this tick's wheel movement to the node under the
+// mouse. Called from processInput.
+func (s *Scene) processWheel(dx, dy float64) {
+	s.wheelBlocked = false
+	if dx == 0 && dy == 0 {
+		return
+	}
+	// Hit-test instead of using hoverNode, which stays on a captured node
+	// while it is dragged. Filter by the camera the mouse was routed to.
+	ps := &s.pointers[0]
+	s.hitMask = LayerMask{}
+	switch {
+	case ps.throughCam != nil && !ps.throughNode.disposed:
+		s.hitMask = ps.throughCam.RenderMask
+	case ps.camera != nil:
+		s.hitMask = ps.camera.RenderMask
+	}
+	node := s.hitTest(ps.lastX, ps.lastY)
+	s.wheelBlocked = s.fireWheel(node, ps.lastX, ps.lastY, dx, dy, readModifiers())
+}
+
+// --- Event firing ---
+
+func (s *Scene) fireDoubleClick(node *Node, pointerID int, wx, wy float64, button MouseButton, mods KeyModifiers) {
+	var lx, ly float64
+	var entityID uint32
+	var userData any
+	if node != nil {
+		lx, ly = node.WorldToLocal(wx, wy)
+		entityID = node.EntityID
+		userData = node.UserData
+	}
+	prop := &propagation{}
+	ctx := ClickContext{
+		Node: node, EntityID: entityID, UserData: userData,
+		GlobalX: wx, GlobalY: wy, LocalX: lx, LocalY: ly,
+		Button: button, PointerID: pointerID, Modifiers: mods,
+		Phase: PhaseCapture, prop: prop,
+	}
+	for _, h := range s.handlers.doubleClick {
+		h.fn(ctx)
+	}
+	dispatchEvent(node, prop, ctx, func(n *Node) func(ClickContext) { return n.OnDoubleClick })
+	s.emitInteractionEvent(EventDoubleClick, node, wx, wy, lx, ly, button, mods, DragContext{}, PinchContext{})
+}
+
+func (s *Scene) fireLongPress(node *Node, pointerID int, wx, wy float64, button MouseButton, mods KeyModifiers) {
+	var lx, ly float64
+	var entityID uint32
+	var userData any
+	if node != nil {
+		lx, ly = node.WorldToLocal(wx, wy)
+		entityID = node.EntityID
+		userData = node.UserData
+	}
+	prop := &propagation{}
+	ctx := PointerContext{
+		Node: node, EntityID: entityID, UserData: userData,
+		GlobalX: wx, GlobalY: wy, LocalX: lx, LocalY: ly,
+		Button: button, PointerID: pointerID, Modifiers: mods,
+		Phase: PhaseCapture, prop: prop,
+	}
+	for _, h := range s.handlers.longPress {
+		h.fn(ctx)
+	}
+	dispatchEvent(node, prop, ctx, func(n *Node) func(PointerContext) { return n.OnLongPress })
+	s.emitInteractionEvent(EventLongPress, node, wx, wy, lx, ly, button, mods, DragContext{}, PinchContext{})
+}
+
+// fireWheel reports whether a handler called PreventDefault.
+func (s *Scene) fireWheel(node *Node, wx, wy, dx, dy float64, mods KeyModifiers) bool {
+	var lx, ly float64
+	var entityID uint32
+	var userData any
+	if node != nil {
+		lx, ly = node.WorldToLocal(wx, wy)
+		entityID = node.EntityID
+		userData = node.UserData
+	}
+	prop := &propagation{}
+	ctx := WheelContext{
+		Node: node, EntityID: entityID, UserData: userData,
+		GlobalX: wx, GlobalY: wy, LocalX: lx, LocalY: ly,
+		DeltaX: dx, DeltaY: dy, Modifiers: mods,
+		Phase: PhaseCapture, prop: prop,
+	}
+	for _, h := range s.handlers.wheel {
+		h.fn(ctx)
+	}
+	dispatchEvent(node, prop, ctx, func(n *Node) func(WheelContext) { return n.OnWheel })
+	// The ECS event carries the scroll amount in the drag delta fields.
+	s.emitInteractionEvent(EventWheel, node, wx, wy, lx, ly, MouseButtonLeft, mods,
+		DragContext{DeltaX: dx, DeltaY: dy}, PinchContext{})
+	return prop.prevented
+}
@@ -0,0 +1,195 @@
+package willow
+
+import "testing"
+
+// gestureScene returns a scene with a 100x100 interactable sprite, with
+// EntityID 7, at the world origin, bridged to a mock store.
+func gestureScene() (*Scene, *Node, *mockStore) {
+	s := NewScene()
+	store := &mockStore{}
+	s.SetEntityStore(store)
+	n := NewSprite("n", TextureRegion{OriginalW: 100, OriginalH: 100})
+	n.Interactable = true
+	n.EntityID = 7
+	s.Root().AddChild(n)
+	updateWorldTransform(s.root, identityTransform, 1.0, false, false)
+	return s, n, store
+}
+
+func countEvents(store *mockStore, typ EventType) int {
+	count := 0
+	for _, e := range store.events {
+		if e.Type == typ {
+			count++
+		}
+	}
+	return count
+}
+
+func TestDoubleClick(t *testing.T) {
+	s, n, store := gestureScene()
+	var doubles, sceneDoubles int
+	n.OnDoubleClick = func(ClickContext) { doubles++ }
+	s.OnDoubleClick(func(ctx ClickContext) {
+		if ctx.Node == n {
+			sceneDoubles++
+		}
+	})
+
+	clickAt(s, 10, 10)
+	s.inputTime += 0.2
+	clickAt(s, 12, 10)
+	if doubles != 1 || sceneDoubles != 1 || countEvents(store, EventDoubleClick) != 1 {
+		t.Fatalf("doubles = %d/%d, want 1/1", doubles, sceneDoubles)
+	}
+
+	// A third quick click starts a new pair instead of firing again.
+	s.inputTime += 0.1
+	clickAt(s, 12, 10)
+	if doubles != 1 {
+		t.Errorf("third click fired a double-click")
+	}
+
+	// Clicks too far apart in time do not pair up.
+	s.inputTime += 1
+	clickAt(s, 12, 10)
+	if doubles != 1 {
+		t.Errorf("slow clicks fired a double-click")
+	}
+
+	s.SetDoubleClickTime(0.5)
+	s.inputTime += 0.4
+	clickAt(s, 12, 10)
+	if doubles != 2 {
+		t.Errorf("SetDoubleClickTime not applied: doubles = %d", doubles)
+	}
+}
+
+func TestLongPress(t *testing.T) {
+	s, n, store := gestureScene()
+	var presses, clicks int
+	n.OnLongPress = func(ctx PointerContext) { presses++ }
+	n.OnClick = func(ClickContext) { clicks++ }
+	s.SetLongPressTime(0.5)
+
+	hold := func(x, y float64, seconds float64) {
+		s.processPointer(0, x, y, x, y, true, MouseButtonLeft, 0)
+		for range int(seconds * 60) {
+			s.inputTime += 1.0 / 60
+			s.processPointer(0, x, y, x, y, true, MouseButtonLeft, 0)
+		}
+	}
+	hold(10, 10, 0.6)
+	s.processPointer(0, 10, 10, 10, 10, false, MouseButtonLeft, 0)
+	if presses != 1 || countEvents(store, EventLongPress) != 1 {
+		t.Fatalf("presses = %d, want 1", presses)
+	}
+	if clicks != 0 {
+		t.Error("release after a long press fired a click")
+	}
+
+	// A short hold is a click.
+	hold(10, 10, 0.2)
+	s.processPointer(0, 10, 10, 10, 10, false, MouseButtonLeft, 0)
+	if presses != 1 || clicks != 1 {
+		t.Errorf("short hold: presses %d, clicks %d; want 1, 1", presses, clicks)
+	}
+
+	// Moving past the dead zone turns the hold into a drag.
+	s.processPointer(0, 10, 10, 10, 10, true, MouseButtonLeft, 0)
+	hold(40, 10, 0.6)
+	if presses != 1 {
+		t.Error("long press fired during a drag")
+	}
+}
+
+func TestWheelEvent(t *testing.T) {
+	s, n, store := gestureScene()
+	parent := NewContainer("parent")
+	parent.Interactable = true
+	s.Root().AddChild(parent)
+	parent.AddChild(n)
+	updateWorldTransform(s.root, identityTransform, 1.0, false, false)
+
+	// Hover the node, then scroll.
+	s.processPointer(0, 30, 40, 30, 40, false, MouseButtonLeft, 0)
+	var got WheelContext
+	var bubbled bool
+	n.OnWheel = func(ctx WheelContext) { got = ctx }
+	parent.OnWheel = func(ctx WheelContext) {
+		bubbled = true
+		ctx.PreventDefault()
+	}
+	s.processWheel(0, -2)
+	if got.Node != n || got.DeltaY != -2 || got.LocalX != 30 || got.LocalY != 40 {
+		t.Errorf("wheel ctx = %+v", got)
+	}
+	if !bubbled || !s.wheelBlocked {
+		t.Error("wheel did not bubble to the parent or PreventDefault was lost")
+	}
+	if countEvents(store, EventWheel) != 1 || store.events[len(store.events)-1].DeltaY != -2 {
+		t.Error("wheel not forwarded to the entity store")
+	}
+
+	s.processWheel(0, 0)
+	if s.wheelBlocked {
+		t.Error("wheelBlocked not reset when the wheel is idle")
+	}
+
+	// Scrolling over empty space reaches scene-level handlers only.
+	s.processPointer(0, 500, 500, 500, 500, false, MouseButtonLeft, 0)
+	sceneNode := n
+	s.OnWheel(func(ctx WheelContext) { sceneNode = ctx.Node })
+	s.processWheel(1, 0)
+	if sceneNode != nil {
+		t.Errorf("wheel over empty space hit %v", sceneNode)
+	}
+}
+
+func TestWheelDuringDrag(t *testing.T) {
+	s, n, _ := gestureScene()
+	other := NewSprite("other", TextureRegion{OriginalW: 100, OriginalH: 100})
+	other.X = 200
+	other.Interactable = true
+	other.RenderLayer = 3
+	s.Root().AddChild(other)
+	updateWorldTransform(s.root, identityTransform, 1.0, false, false)
+	var got *Node
+	s.OnWheel(func(ctx WheelContext) { got = ctx.Node })
+
+	// Drag n over the other node; n stays captured and hovered.
+	n.OnDragStart = func(DragContext) { s.CapturePointer(0, n) }
+	s.processPointer(0, 10, 10, 10, 10, true, MouseButtonLeft, 0)
+	s.processPointer(0, 40, 10, 40, 10, true, MouseButtonLeft, 0)
+	s.processPointer(0, 250, 50, 250, 50, true, MouseButtonLeft, 0)
+	if s.pointers[0].hoverNode != n {
+		t.Fatalf("hover during drag = %v, want the dragged node", s.pointers[0].hoverNode)
+	}
+	s.processWheel(0, 1)
+	if got != other {
+		t.Errorf("wheel during drag hit %v, want the node under the cursor", got)
+	}
+
+	// The routed camera's mask filters the hit test.
+	s.pointers[0].camera = &Camera{RenderMask: LayerMaskOf(0)}
+	s.processWheel(0, 1)
+	if got != nil {
+		t.Errorf("wheel hit %v outside the camera's mask", got)
+	}
+}
+
+func TestRightButtonDrag(t *testing.T) {
+	s, n, store := gestureScene()
+	var buttons []MouseButton
+	n.OnDragStart = func(ctx DragContext) { buttons = append(buttons, ctx.Button) }
+
+	s.processPointer(0, 10, 10, 10, 10, true, MouseButtonRight, 0)
+	s.processPointer(0, 40, 10, 40, 10, true, MouseButtonRight, 0)
+	s.processPointer(0, 40, 10, 40, 10, false, MouseButtonRight, 0)
+	if len(buttons) != 1 || buttons[0] != MouseButtonRight {
+		t.Errorf("drag buttons = %v, want [right]", buttons)
+	}
+	if countEvents(store, EventDragEnd) != 1 || store.events[len(store.events)-2].Button != MouseButtonRight {
+		t.Error("right-button drag end not forwarded with its button")
+	}
+}
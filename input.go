@@ -11,6 +11,9 @@ import (
 const (
 	maxPointers         = 10  // pointer 0 = mouse, 1-9 = touch
 	defaultDragDeadZone = 4.0 // pixels
+
+	defaultDoubleClickTime = 0.3 // seconds
+	defaultLongPressTime   = 0.5 // seconds
 )
 
 // --- Built-in HitShape types ---
@@ -89,7 +92,18 @@ type pointerState struct {
 	hitNode     *Node
 	hoverNode   *Node // last node the pointer was hovering over (for enter/leave)
 	dragging    bool
+	noDrag      bool    // PreventDefault on pointer down or drag start blocks dragging
+	pressTime   float64 // scene input time of the press
+	longPressed bool    // a long press fired for the current press
+	clickNode   *Node   // node of the previous click, for double-click detection
+	clickButton MouseButton
+	clickTime   float64
+	clickX      float64
+	clickY      float64
 	button      MouseButton // button captured at press time
+	camera      *Camera     // camera the pointer was last routed to
+	throughNode *Node       // sprite showing a camera target the pointer is forwarded through
+	throughCam  *Camera     // camera whose world the pointer is forwarded into
 }
 
 // --- Pinch state ---
@@ -137,6 +151,11 @@ type handlerRegistry struct {
 	drag         []dragHandler
 	dragEnd      []dragHandler
 	pinch        []pinchHandler
+	doubleClick  []clickHandler
+	longPress    []pointerHandler
+	wheel        []wheelHandler
+	focus        []focusHandler
+	blur         []focusHandler
 	nextID       uint32
 }
 
@@ -174,6 +193,16 @@ func (h CallbackHandle) Remove() {
 		h.reg.dragEnd = removeDragHandler(h.reg.dragEnd, h.id)
 	case EventPinch:
 		h.reg.pinch = removePinchHandler(h.reg.pinch, h.id)
+	case EventDoubleClick:
+		h.reg.doubleClick = removeClickHandler(h.reg.doubleClick, h.id)
+	case EventLongPress:
+		h.reg.longPress = removePointerHandler(h.reg.longPress, h.id)
+	case EventWheel:
+		h.reg.wheel = removeWheelHandler(h.reg.wheel, h.id)
+	case EventFocus:
+		h.reg.focus = removeFocusHandler(h.reg.focus, h.id)
+	case EventBlur:
+		h.reg.blur = removeFocusHandler(h.reg.blur, h.id)
 	}
 }
 
@@ -379,14 +408,17 @@ func (s *Scene) collectInteractable(n *Node, buf []*Node) []*Node {
 	return buf
 }
 
-// hitTest finds the topmost interactable node at (worldX, worldY).
-// Returns nil if nothing is hit.
+// hitTest finds the topmost interactable node at (worldX, worldY) whose
+// RenderLayer is in the routed camera's mask. Returns nil if nothing is hit.
 func (s *Scene) hitTest(worldX, worldY float64) *Node {
 	s.hitBuf = s.collectInteractable(s.root, s.hitBuf[:0])
 
 	// Iterate backward (reverse painter order): topmost visual node first.
 	for i := len(s.hitBuf) - 1; i >= 0; i-- {
 		n := s.hitBuf[i]
+		if !s.hitMask.Has(n.RenderLayer) {
+			continue
+		}
 		lx, ly := n.WorldToLocal(worldX, worldY)
 		if nodeContainsLocal(n, lx, ly) {
 			return n
@@ -420,16 +452,21 @@ func readModifiers() KeyModifiers {
 func (s *Scene) processInput() {
 	mods := readModifiers()
 
-	// Primary camera for screen-to-world conversion.
+	// Primary camera for screen-to-world conversion: the first one drawing
+	// to the screen.
 	var cam *Camera
-	if len(s.cameras) > 0 {
-		cam = s.cameras[0]
-		cam.computeViewMatrix()
+	for _, c := range s.cameras {
+		if c.Target == nil {
+			cam = c
+			cam.computeViewMatrix()
+			break
+		}
 	}
 
 	if !s.processInjectedInput(cam, mods) {
 		s.processMousePointer(cam, mods)
 	}
+	s.processWheel(ebiten.Wheel())
 	s.processTouchPointers(cam, mods)
 	s.detectPinch(mods)
 }
@@ -442,11 +479,106 @@ func screenToWorld(cam *Camera, sx, sy float64) (float64, float64) {
 	return sx, sy
 }
 
+// pointerToWorld routes a pointer at screen (sx, sy) to a camera and
+// converts it to world coordinates. With several cameras, the topmost one
+// whose viewport contains the point and has an interactable node under it
+// wins. A pressed pointer stays with the camera it was pressed on. The
+// chosen camera's RenderMask filters the following hit test.
+func (s *Scene) pointerToWorld(pointerID int, primary *Camera, sx, sy float64) (float64, float64) {
+	ps := &s.pointers[pointerID]
+	cam := primary
+	if ps.down && ps.camera != nil {
+		cam = ps.camera
+	} else if len(s.cameras) > 1 {
+		cam = s.routeCamera(primary, sx, sy)
+	}
+	ps.camera = cam
+	s.hitMask = LayerMask{}
+	if cam != nil {
+		s.hitMask = cam.RenderMask
+	}
+	wx, wy := screenToWorld(cam, sx, sy)
+	return s.mapThroughTarget(ps, wx, wy)
+}
+
+// mapThroughTarget forwards a pointer that lands on a sprite showing the
+// Target of a MapInput camera into that camera's world. As with camera
+// routing, a pressed pointer keeps its mapping until release.
+func (s *Scene) mapThroughTarget(ps *pointerState, wx, wy float64) (float64, float64) {
+	if !ps.down {
+		ps.throughNode, ps.throughCam = nil, nil
+		if n := s.mappedTargetNode(wx, wy); n != nil {
+			ps.throughNode, ps.throughCam = n, s.inputCameraFor(n)
+		}
+	}
+	if ps.throughCam == nil || ps.throughNode.disposed {
+		return wx, wy
+	}
+	// The sprite's local space is the texture's pixel space.
+	lx, ly := ps.throughNode.WorldToLocal(wx, wy)
+	s.hitMask = ps.throughCam.RenderMask
+	return ps.throughCam.ScreenToWorld(lx, ly)
+}
+
+// mappedTargetNode returns the node hit at (wx, wy) if it shows the target
+// of a MapInput camera.
+func (s *Scene) mappedTargetNode(wx, wy float64) *Node {
+	mapped := false
+	for _, c := range s.cameras {
+		mapped = mapped || (c.MapInput && c.Target != nil)
+	}
+	if !mapped {
+		return nil
+	}
+	n := s.hitTest(wx, wy)
+	if n == nil || s.inputCameraFor(n) == nil {
+		return nil
+	}
+	return n
+}
+
+// inputCameraFor returns the MapInput camera whose Target n displays.
+func (s *Scene) inputCameraFor(n *Node) *Camera {
+	if n.customImage == nil {
+		return nil
+	}
+	for _, c := range s.cameras {
+		if c.MapInput && c.Target != nil && c.Target.image == n.customImage {
+			return c
+		}
+	}
+	return nil
+}
+
+// routeCamera picks the camera that receives a pointer at screen (sx, sy).
+// Falls back to the topmost camera containing the point, then to primary.
+func (s *Scene) routeCamera(primary *Camera, sx, sy float64) *Camera {
+	var fallback *Camera
+	for i := len(s.cameras) - 1; i >= 0; i-- {
+		c := s.cameras[i]
+		if c.Target != nil || !c.Viewport.Contains(sx, sy) {
+			continue
+		}
+		if fallback == nil {
+			fallback = c
+		}
+		s.hitMask = c.RenderMask
+		wx, wy := c.ScreenToWorld(sx, sy)
+		if s.hitTest(wx, wy) != nil {
+			return c
+		}
+	}
+	if fallback != nil {
+		return fallback
+	}
+	return primary
+}
+
 // processMousePointer handles mouse input (pointer 0).
 func (s *Scene) processMousePointer(cam *Camera, mods KeyModifiers) {
 	mx, my := ebiten.CursorPosition()
-	sx, sy := float64(mx), float64(my)
-	wx, wy := screenToWorld(cam, sx, sy)
+	sx, sy := s.pointerToCanvas(mx, my)
+	wx, wy := s.pointerToWorld(0, cam, sx, sy)
 
 	// Detect which button is pressed. If pointer is already down, use the
 	// stored button to avoid changing mid-interaction.
@@ -456,7 +588,20 @@ func (s *Scene) processMousePointer(cam *Camera, mods KeyModifiers) {
 	right := ebiten.IsMouseButtonPressed(ebiten.MouseButtonRight)
 	middle := ebiten.IsMouseButtonPressed(ebiten.MouseButtonMiddle)
 
-	if left || right || middle {
+	if ps := &s.pointers[0]; ps.down {
+		// Only the button that started the interaction can end it, so a
+		// right or middle drag ends when that button is released even if
+		// another one is held.
+		button = ps.button
+		switch button {
+		case MouseButtonRight:
+			pressed = right
+		case MouseButtonMiddle:
+			pressed = middle
+		default:
+			pressed = left
+		}
+	} else if left || right || middle {
 		pressed = true
 		if left {
 			button = MouseButtonLeft
@@ -485,8 +630,8 @@ func (s *Scene) processTouchPointers(cam *Camera, mods KeyModifiers) {
 		activeSlots[slot] = true
 
 		tx, ty := ebiten.TouchPosition(tid)
-		stx, sty := float64(tx), float64(ty)
-		wx, wy := screenToWorld(cam, stx, sty)
+		stx, sty := s.pointerToCanvas(tx, ty)
+		wx, wy := s.pointerToWorld(slot, cam, stx, sty)
 		s.processPointer(slot, wx, wy, stx, sty, true, MouseButtonLeft, mods)
 	}
 
@@ -566,8 +711,24 @@ func (s *Scene) processPointer(pointerID int, wx, wy, sx, sy float64, pressed bo
 		ps.lastScreenY = sy
 		ps.hitNode = target
 		ps.dragging = false
-
-		s.firePointerDown(target, pointerID, wx, wy, ps.button, mods)
+		ps.pressTime = s.inputTime
+		ps.longPressed = false
+
+		ps.noDrag = s.firePointerDown(target, pointerID, wx, wy, ps.button, mods)
+		if !ps.noDrag {
+			// Focus the nearest focusable node, so pressing a child of a
+			// composite widget focuses the widget.
+			for n := target; n != nil; n = n.Parent {
+				if n.Focusable {
+					s.SetFocus(n)
+					break
+				}
+			}
+			// Pressing empty space clears focus.
+			if target == nil {
+				s.SetFocus(nil)
+			}
+		}
 	} else if !pressed && ps.down {
 		// Just released — use button from press start.
 		sdx := sx - ps.lastScreenX
@@ -575,8 +736,9 @@ func (s *Scene) processPointer(pointerID int, wx, wy, sx, sy float64, pressed bo
 		if ps.dragging {
 			s.fireDragEnd(ps.hitNode, pointerID, wx, wy, ps.startX, ps.startY,
 				wx-ps.lastX, wy-ps.lastY, sdx, sdy, ps.button, mods)
-		} else if ps.hitNode != nil && ps.hitNode == target {
+		} else if ps.hitNode != nil && ps.hitNode == target && !ps.longPressed {
 			s.fireClick(target, pointerID, wx, wy, ps.button, mods)
+			s.detectDoubleClick(ps, target, pointerID, wx, wy, mods)
 		}
 
 		s.firePointerUp(target, pointerID, wx, wy, ps.button, mods)
@@ -591,13 +753,17 @@ func (s *Scene) processPointer(pointerID int, wx, wy, sx, sy float64, pressed bo
 		if wx != ps.lastX || wy != ps.lastY || sx != ps.lastScreenX || sy != ps.lastScreenY {
 			sdx := sx - ps.lastScreenX
 			sdy := sy - ps.lastScreenY
-			if !ps.dragging {
+			if !ps.dragging && !ps.noDrag {
 				dx := wx - ps.startX
 				dy := wy - ps.startY
 				if math.Sqrt(dx*dx+dy*dy) > s.dragDeadZone {
 					ps.dragging = true
-					s.fireDragStart(ps.hitNode, pointerID, wx, wy, ps.startX, ps.startY,
-						wx-ps.startX, wy-ps.startY, sx-ps.screenX, sy-ps.screenY, ps.button, mods)
+					if s.fireDragStart(ps.hitNode, pointerID, wx, wy, ps.startX, ps.startY,
+						wx-ps.startX, wy-ps.startY, sx-ps.screenX, sy-ps.screenY, ps.button, mods) {
+						// PreventDefault cancels the drag for this press.
+						ps.dragging = false
+						ps.noDrag = true
+					}
 				}
 			}
 			if ps.dragging {
@@ -609,6 +775,7 @@ func (s *Scene) processPointer(pointerID int, wx, wy, sx, sy float64, pressed bo
 		ps.lastY = wy
 		ps.lastScreenX = sx
 		ps.lastScreenY = sy
+		s.detectLongPress(ps, pointerID, wx, wy, mods)
 	} else if !pressed && !ps.down {
 		// Hover move.
 		if wx != ps.lastX || wy != ps.lastY {
@@ -710,7 +877,7 @@ func (s *Scene) detectPinch(mods KeyModifiers) {
 
 // --- Event dispatch ---
 
-func (s *Scene) firePointerDown(node *Node, pointerID int, wx, wy float64, button MouseButton, mods KeyModifiers) {
+func (s *Scene) firePointerDown(node *Node, pointerID int, wx, wy float64, button MouseButton, mods KeyModifiers) bool {
 	var lx, ly float64
 	var entityID uint32
 	var userData any
@@ -719,21 +886,25 @@ func (s *Scene) firePointerDown(node *Node, pointerID int, wx, wy float64, butto
 		entityID = node.EntityID
 		userData = node.UserData
 	}
+	prop := &propagation{}
 	ctx := PointerContext{
 		Node: node, EntityID: entityID, UserData: userData,
 		GlobalX: wx, GlobalY: wy, LocalX: lx, LocalY: ly,
 		Button: button, PointerID: pointerID, Modifiers: mods,
+		Phase: PhaseCapture, prop: prop,
 	}
 	// Scene-level handlers first.
 	for _, h := range s.handlers.pointerDown {
 		h.fn(ctx)
 	}
-	// Per-node callback.
-	if node != nil && node.OnPointerDown != nil {
-		node.OnPointerDown(ctx)
+	// Per-node callbacks: capture, target, then bubble.
+	dispatchEvent(node, prop, ctx, func(n *Node) func(PointerContext) { return n.OnPointerDown })
+	if node != nil && node.tileLayer != nil {
+		node.tileLayer.tilePointerDown(ctx)
 	}
 	// ECS bridge.
 	s.emitInteractionEvent(EventPointerDown, node, wx, wy, lx, ly, button, mods, DragContext{}, PinchContext{})
+	return prop.prevented
 }
 
 func (s *Scene) firePointerUp(node *Node, pointerID int, wx, wy float64, button MouseButton, mods KeyModifiers) {
@@ -745,17 +916,17 @@ func (s *Scene) firePointerUp(node *Node, pointerID int, wx, wy float64, button
 		entityID = node.EntityID
 		userData = node.UserData
 	}
+	prop := &propagation{}
 	ctx := PointerContext{
 		Node: node, EntityID: entityID, UserData: userData,
 		GlobalX: wx, GlobalY: wy, LocalX: lx, LocalY: ly,
 		Button: button, PointerID: pointerID, Modifiers: mods,
+		Phase: PhaseCapture, prop: prop,
 	}
 	for _, h := range s.handlers.pointerUp {
 		h.fn(ctx)
 	}
-	if node != nil && node.OnPointerUp != nil {
-		node.OnPointerUp(ctx)
-	}
+	dispatchEvent(node, prop, ctx, func(n *Node) func(PointerContext) { return n.OnPointerUp })
 	s.emitInteractionEvent(EventPointerUp, node, wx, wy, lx, ly, button, mods, DragContext{}, PinchContext{})
 }
 
@@ -768,16 +939,19 @@ func (s *Scene) firePointerMove(node *Node, pointerID int, wx, wy float64, butto
 		entityID = node.EntityID
 		userData = node.UserData
 	}
+	prop := &propagation{}
 	ctx := PointerContext{
 		Node: node, EntityID: entityID, UserData: userData,
 		GlobalX: wx, GlobalY: wy, LocalX: lx, LocalY: ly,
 		Button: button, PointerID: pointerID, Modifiers: mods,
+		Phase: PhaseCapture, prop: prop,
 	}
 	for _, h := range s.handlers.pointerMove {
 		h.fn(ctx)
 	}
-	if node != nil && node.OnPointerMove != nil {
-		node.OnPointerMove(ctx)
+	dispatchEvent(node, prop, ctx, func(n *Node) func(PointerContext) { return n.OnPointerMove })
+	if node != nil && node.tileLayer != nil {
+		node.tileLayer.tileHover(ctx)
 	}
 	s.emitInteractionEvent(EventPointerMove, node, wx, wy, lx, ly, button, mods, DragContext{}, PinchContext{})
 }
@@ -800,7 +974,10 @@ func (s *Scene) firePointerEnter(node *Node, pointerID int, wx, wy float64, butt
 		h.fn(ctx)
 	}
 	if node != nil && node.OnPointerEnter != nil {
-		node.OnPointerEnter(ctx)
+		node.OnPointerEnter(ctx.at(node, PhaseTarget))
+	}
+	if node != nil && node.tileLayer != nil {
+		node.tileLayer.tileHover(ctx)
 	}
 	s.emitInteractionEvent(EventPointerEnter, node, wx, wy, lx, ly, button, mods, DragContext{}, PinchContext{})
 }
@@ -823,7 +1000,10 @@ func (s *Scene) firePointerLeave(node *Node, pointerID int, wx, wy float64, butt
 		h.fn(ctx)
 	}
 	if node != nil && node.OnPointerLeave != nil {
-		node.OnPointerLeave(ctx)
+		node.OnPointerLeave(ctx.at(node, PhaseTarget))
+	}
+	if node != nil && node.tileLayer != nil {
+		node.tileLayer.tileLeave(ctx)
 	}
 	s.emitInteractionEvent(EventPointerLeave, node, wx, wy, lx, ly, button, mods, DragContext{}, PinchContext{})
 }
@@ -837,21 +1017,24 @@ func (s *Scene) fireClick(node *Node, pointerID int, wx, wy float64, button Mous
 		entityID = node.EntityID
 		userData = node.UserData
 	}
+	prop := &propagation{}
 	ctx := ClickContext{
 		Node: node, EntityID: entityID, UserData: userData,
 		GlobalX: wx, GlobalY: wy, LocalX: lx, LocalY: ly,
 		Button: button, PointerID: pointerID, Modifiers: mods,
+		Phase: PhaseCapture, prop: prop,
 	}
 	for _, h := range s.handlers.click {
 		h.fn(ctx)
 	}
-	if node != nil && node.OnClick != nil {
-		node.OnClick(ctx)
+	dispatchEvent(node, prop, ctx, func(n *Node) func(ClickContext) { return n.OnClick })
+	if node != nil && node.tileLayer != nil {
+		node.tileLayer.tileClick(ctx)
 	}
 	s.emitInteractionEvent(EventClick, node, wx, wy, lx, ly, button, mods, DragContext{}, PinchContext{})
 }
 
-func (s *Scene) fireDragStart(node *Node, pointerID int, wx, wy, startX, startY, deltaX, deltaY, screenDX, screenDY float64, button MouseButton, mods KeyModifiers) {
+func (s *Scene) fireDragStart(node *Node, pointerID int, wx, wy, startX, startY, deltaX, deltaY, screenDX, screenDY float64, button MouseButton, mods KeyModifiers) bool {
 	var lx, ly float64
 	var entityID uint32
 	var userData any
@@ -860,20 +1043,21 @@ func (s *Scene) fireDragStart(node *Node, pointerID int, wx, wy, startX, startY,
 		entityID = node.EntityID
 		userData = node.UserData
 	}
+	prop := &propagation{}
 	ctx := DragContext{
 		Node: node, EntityID: entityID, UserData: userData,
 		GlobalX: wx, GlobalY: wy, LocalX: lx, LocalY: ly,
 		StartX: startX, StartY: startY, DeltaX: deltaX, DeltaY: deltaY,
 		ScreenDeltaX: screenDX, ScreenDeltaY: screenDY,
 		Button: button, PointerID: pointerID, Modifiers: mods,
+		Phase: PhaseCapture, prop: prop,
 	}
 	for _, h := range s.handlers.dragStart {
 		h.fn(ctx)
 	}
-	if node != nil && node.OnDragStart != nil {
-		node.OnDragStart(ctx)
-	}
+	dispatchEvent(node, prop, ctx, func(n *Node) func(DragContext) { return n.OnDragStart })
 	s.emitInteractionEvent(EventDragStart, node, wx, wy, lx, ly, button, mods, ctx, PinchContext{})
+	return prop.prevented
 }
 
 func (s *Scene) fireDrag(node *Node, pointerID int, wx, wy, startX, startY, deltaX, deltaY, screenDX, screenDY float64, button MouseButton, mods KeyModifiers) {
@@ -885,19 +1069,19 @@ func (s *Scene) fireDrag(node *Node, pointerID int, wx, wy, startX, startY, delt
 		entityID = node.EntityID
 		userData = node.UserData
 	}
+	prop := &propagation{}
 	ctx := DragContext{
 		Node: node, EntityID: entityID, UserData: userData,
 		GlobalX: wx, GlobalY: wy, LocalX: lx, LocalY: ly,
 		StartX: startX, StartY: startY, DeltaX: deltaX, DeltaY: deltaY,
 		ScreenDeltaX: screenDX, ScreenDeltaY: screenDY,
 		Button: button, PointerID: pointerID, Modifiers: mods,
+		Phase: PhaseCapture, prop: prop,
 	}
 	for _, h := range s.handlers.drag {
 		h.fn(ctx)
 	}
-	if node != nil && node.OnDrag != nil {
-		node.OnDrag(ctx)
-	}
+	dispatchEvent(node, prop, ctx, func(n *Node) func(DragContext) { return n.OnDrag })
 	s.emitInteractionEvent(EventDrag, node, wx, wy, lx, ly, button, mods, ctx, PinchContext{})
 }
 
@@ -910,19 +1094,19 @@ func (s *Scene) fireDragEnd(node *Node, pointerID int, wx, wy, startX, startY, d
 		entityID = node.EntityID
 		userData = node.UserData
 	}
+	prop := &propagation{}
 	ctx := DragContext{
 		Node: node, EntityID: entityID, UserData: userData,
 		GlobalX: wx, GlobalY: wy, LocalX: lx, LocalY: ly,
 		StartX: startX, StartY: startY, DeltaX: deltaX, DeltaY: deltaY,
 		ScreenDeltaX: screenDX, ScreenDeltaY: screenDY,
 		Button: button, PointerID: pointerID, Modifiers: mods,
+		Phase: PhaseCapture, prop: prop,
 	}
 	for _, h := range s.handlers.dragEnd {
 		h.fn(ctx)
 	}
-	if node != nil && node.OnDragEnd != nil {
-		node.OnDragEnd(ctx)
-	}
+	dispatchEvent(node, prop, ctx, func(n *Node) func(DragContext) { return n.OnDragEnd })
 	s.emitInteractionEvent(EventDragEnd, node, wx, wy, lx, ly, button, mods, ctx, PinchContext{})
 }
 
@@ -34,6 +34,9 @@ const (
 	RopeCurveWave
 	// RopeCurveCustom calls a user-provided PointsFunc callback.
 	RopeCurveCustom
+	// RopeCurvePath samples Path evenly along its arc length, e.g. a
+	// Catmull-Rom spline through many points.
+	RopeCurvePath
 )
 
 // RopeConfig configures a Rope mesh.
@@ -62,6 +65,9 @@ type RopeConfig struct {
 
 	// Custom callback. Receives a preallocated buffer; must return the slice to use.
 	PointsFunc func(buf []Vec2) []Vec2
+
+	// Path drawn by RopeCurvePath.
+	Path *Path
 }
 
 // Rope generates a ribbon/rope mesh that follows a polyline path.
@@ -94,9 +100,14 @@ func (r *Rope) Config() *RopeConfig {
 
 // Update recomputes the rope's point path from the current RopeConfig and
 // rebuilds the mesh. Call this in your update loop after changing the bound
-// Vec2 values. Start and End must be non-nil (except for RopeCurveCustom).
+// Vec2 values. Start and End must be non-nil (except for RopeCurveCustom
+// and RopeCurvePath).
 func (r *Rope) Update() {
-	if r.config.CurveMode != RopeCurveCustom {
+	if r.config.CurveMode == RopeCurvePath {
+		if r.config.Path == nil {
+			return
+		}
+	} else if r.config.CurveMode != RopeCurveCustom {
 		if r.config.Start == nil || r.config.End == nil {
 			return
 		}
@@ -141,12 +152,7 @@ func (r *Rope) Update() {
 		}
 		a, c, b := *r.config.Start, *r.config.Controls[0], *r.config.End
 		for i := 0; i < n; i++ {
-			t := float64(i) / float64(segs)
-			u := 1 - t
-			r.ptsBuf[i] = Vec2{
-				X: u*u*a.X + 2*u*t*c.X + t*t*b.X,
-				Y: u*u*a.Y + 2*u*t*c.Y + t*t*b.Y,
-			}
+			r.ptsBuf[i] = quadBezier(a, c, b, float64(i)/float64(segs))
 		}
 
 	case RopeCurveCubicBezier:
@@ -155,14 +161,7 @@ func (r *Rope) Update() {
 		}
 		a, c1, c2, b := *r.config.Start, *r.config.Controls[0], *r.config.Controls[1], *r.config.End
 		for i := 0; i < n; i++ {
-			t := float64(i) / float64(segs)
-			u := 1 - t
-			u2 := u * u
-			t2 := t * t
-			r.ptsBuf[i] = Vec2{
-				X: u2*u*a.X + 3*u2*t*c1.X + 3*u*t2*c2.X + t2*t*b.X,
-				Y: u2*u*a.Y + 3*u2*t*c1.Y + 3*u*t2*c2.Y + t2*t*b.Y,
-			}
+			r.ptsBuf[i] = cubicBezier(a, c1, c2, b, float64(i)/float64(segs))
 		}
 
 	case RopeCurveWave:
@@ -188,6 +187,9 @@ func (r *Rope) Update() {
 		if r.config.PointsFunc != nil {
 			r.ptsBuf = r.config.PointsFunc(r.ptsBuf)
 		}
+
+	case RopeCurvePath:
+		r.ptsBuf = r.config.Path.Sample(r.ptsBuf, n)
 	}
 
 	r.SetPoints(r.ptsBuf)
@@ -35,50 +35,76 @@ type HitShape interface {
 
 // PointerContext carries pointer event data passed to pointer callbacks.
 type PointerContext struct {
-	Node      *Node        // the node under the pointer, or nil if none
-	EntityID  uint32       // the hit node's EntityID (for ECS bridging)
-	UserData  any          // the hit node's UserData
-	GlobalX   float64      // pointer X in world coordinates
-	GlobalY   float64      // pointer Y in world coordinates
-	LocalX    float64      // pointer X in the hit node's local coordinates
-	LocalY    float64      // pointer Y in the hit node's local coordinates
-	Button    MouseButton  // which mouse button is involved
-	PointerID int          // 0 = mouse, 1-9 = touch contacts
-	Modifiers KeyModifiers // keyboard modifier keys held during the event
+	Node          *Node        // the node under the pointer, or nil if none
+	EntityID      uint32       // the hit node's EntityID (for ECS bridging)
+	UserData      any          // the hit node's UserData
+	GlobalX       float64      // pointer X in world coordinates
+	GlobalY       float64      // pointer Y in world coordinates
+	LocalX        float64      // pointer X in the hit node's local coordinates
+	LocalY        float64      // pointer Y in the hit node's local coordinates
+	Button        MouseButton  // which mouse button is involved
+	PointerID     int          // 0 = mouse, 1-9 = touch contacts
+	Modifiers     KeyModifiers // keyboard modifier keys held during the event
+	CurrentTarget *Node        // the node whose callback is running; nil in scene-level handlers
+	Phase         EventPhase   // propagation phase of the running callback
+	prop          *propagation
 }
 
 // ClickContext carries click event data passed to click callbacks.
 type ClickContext struct {
-	Node      *Node        // the clicked node
-	EntityID  uint32       // the clicked node's EntityID (for ECS bridging)
-	UserData  any          // the clicked node's UserData
-	GlobalX   float64      // click X in world coordinates
-	GlobalY   float64      // click Y in world coordinates
-	LocalX    float64      // click X in the node's local coordinates
-	LocalY    float64      // click Y in the node's local coordinates
-	Button    MouseButton  // which mouse button was clicked
-	PointerID int          // 0 = mouse, 1-9 = touch contacts
-	Modifiers KeyModifiers // keyboard modifier keys held during the click
+	Node          *Node        // the clicked node
+	EntityID      uint32       // the clicked node's EntityID (for ECS bridging)
+	UserData      any          // the clicked node's UserData
+	GlobalX       float64      // click X in world coordinates
+	GlobalY       float64      // click Y in world coordinates
+	LocalX        float64      // click X in the node's local coordinates
+	LocalY        float64      // click Y in the node's local coordinates
+	Button        MouseButton  // which mouse button was clicked
+	PointerID     int          // 0 = mouse, 1-9 = touch contacts, FocusPointerID = keyboard/gamepad
+	Modifiers     KeyModifiers // keyboard modifier keys held during the click
+	CurrentTarget *Node        // the node whose callback is running; nil in scene-level handlers
+	Phase         EventPhase   // propagation phase of the running callback
+	prop          *propagation
 }
 
 // DragContext carries drag event data passed to drag callbacks.
 type DragContext struct {
-	Node         *Node        // the node being dragged
-	EntityID     uint32       // the dragged node's EntityID (for ECS bridging)
-	UserData     any          // the dragged node's UserData
-	GlobalX      float64      // current pointer X in world coordinates
-	GlobalY      float64      // current pointer Y in world coordinates
-	LocalX       float64      // current pointer X in the node's local coordinates
-	LocalY       float64      // current pointer Y in the node's local coordinates
-	StartX       float64      // world X where the drag began
-	StartY       float64      // world Y where the drag began
-	DeltaX       float64      // X movement since the previous drag event
-	DeltaY       float64      // Y movement since the previous drag event
-	ScreenDeltaX float64      // X movement in screen pixels since the previous drag event
-	ScreenDeltaY float64      // Y movement in screen pixels since the previous drag event
-	Button       MouseButton  // which mouse button initiated the drag
-	PointerID    int          // 0 = mouse, 1-9 = touch contacts
-	Modifiers    KeyModifiers // keyboard modifier keys held during the drag
+	Node          *Node        // the node being dragged
+	EntityID      uint32       // the dragged node's EntityID (for ECS bridging)
+	UserData      any          // the dragged node's UserData
+	GlobalX       float64      // current pointer X in world coordinates
+	GlobalY       float64      // current pointer Y in world coordinates
+	LocalX        float64      // current pointer X in the node's local coordinates
+	LocalY        float64      // current pointer Y in the node's local coordinates
+	StartX        float64      // world X where the drag began
+	StartY        float64      // world Y where the drag began
+	DeltaX        float64      // X movement since the previous drag event
+	DeltaY        float64      // Y movement since the previous drag event
+	ScreenDeltaX  float64      // X movement in screen pixels since the previous drag event
+	ScreenDeltaY  float64      // Y movement in screen pixels since the previous drag event
+	Button        MouseButton  // which mouse button initiated the drag
+	PointerID     int          // 0 = mouse, 1-9 = touch contacts
+	Modifiers     KeyModifiers // keyboard modifier keys held during the drag
+	CurrentTarget *Node        // the node whose callback is running; nil in scene-level handlers
+	Phase         EventPhase   // propagation phase of the running callback
+	prop          *propagation
+}
+
+// WheelContext carries mouse wheel event data passed to wheel callbacks.
+type WheelContext struct {
+	Node          *Node        // the node under the pointer, or nil if none
+	EntityID      uint32       // the hit node's EntityID (for ECS bridging)
+	UserData      any          // the hit node's UserData
+	GlobalX       float64      // pointer X in world coordinates
+	GlobalY       float64      // pointer Y in world coordinates
+	LocalX        float64      // pointer X in the hit node's local coordinates
+	LocalY        float64      // pointer Y in the hit node's local coordinates
+	DeltaX        float64      // horizontal scroll amount, in wheel notches
+	DeltaY        float64      // vertical scroll amount, in wheel notches (positive = up)
+	Modifiers     KeyModifiers // keyboard modifier keys held during the event
+	CurrentTarget *Node        // the node whose callback is running; nil in scene-level handlers
+	Phase         EventPhase   // propagation phase of the running callback
+	prop          *propagation
 }
 
 // PinchContext carries two-finger pinch/rotate gesture data.
@@ -219,6 +245,11 @@ type Node struct {
 	// OnUpdate is called once per tick during Scene.Update if set.
 	OnUpdate func(dt float64)
 
+	// update, when non-nil, is called once per tick after OnUpdate. Used by
+	// AnimatedSprite and Skeleton to advance playback without taking over
+	// OnUpdate.
+	update func(dt float64)
+
 	// customEmit, when non-nil, is called during traverse instead of the
 	// normal command-emit path. Used by TileMapLayer to emit
 	// CommandTilemap commands. The callback receives the scene and a
@@ -233,6 +264,13 @@ type Node struct {
 	// HitShape overrides the default AABB hit test with a custom shape.
 	// Nil means use the node's bounding box.
 	HitShape HitShape
+	// Focusable includes this node in keyboard and gamepad focus navigation.
+	// It must also be Interactable. See Scene.SetFocus.
+	Focusable bool
+	// CaptureEvents makes this node's callbacks receive its descendants'
+	// propagating events in the capture phase, before the target, instead of
+	// the bubble phase.
+	CaptureEvents bool
 
 	// ---- COLD: filters, cache, mask ----
 
@@ -246,7 +284,11 @@ type Node struct {
 	mask         *Node
 
 	// ---- COLD: per-node pointer callbacks (nil by default; zero cost when unused) ----
-	// Scene-level handlers fire before per-node callbacks.
+	// Scene-level handlers fire before per-node callbacks. Pointer down, up,
+	// move, click, double-click, long-press, drag and wheel events then
+	// propagate like DOM events: capturing ancestors, the target, then
+	// bubbling up to the root. Enter, leave, pinch and focus events only
+	// reach the target.
 
 	// OnPointerDown fires when a pointer button is pressed over this node.
 	OnPointerDown func(PointerContext)
@@ -256,6 +298,15 @@ type Node struct {
 	OnPointerMove func(PointerContext)
 	// OnClick fires on press then release over this node.
 	OnClick func(ClickContext)
+	// OnDoubleClick fires on the second of two quick clicks on this node,
+	// after its OnClick. See Scene.SetDoubleClickTime.
+	OnDoubleClick func(ClickContext)
+	// OnLongPress fires once when a pointer is held still on this node. The
+	// click that would follow on release is suppressed. See
+	// Scene.SetLongPressTime.
+	OnLongPress func(PointerContext)
+	// OnWheel fires when the mouse wheel scrolls over this node.
+	OnWheel func(WheelContext)
 	// OnDragStart fires when a drag gesture begins on this node.
 	OnDragStart func(DragContext)
 	// OnDrag fires each frame while this node is being dragged.
@@ -268,9 +319,16 @@ type Node struct {
 	OnPointerEnter func(PointerContext)
 	// OnPointerLeave fires when the pointer leaves this node's bounds.
 	OnPointerLeave func(PointerContext)
+	// OnFocus fires when this node gains keyboard focus.
+	OnFocus func(FocusContext)
+	// OnBlur fires when this node loses keyboard focus.
+	OnBlur func(FocusContext)
 
 	// ---- COLD: internal ----
 	disposed bool
+	// tileLayer receives this node's pointer events after its callbacks
+	// when the node belongs to a TileMapLayer.
+	tileLayer *TileMapLayer
 }
 
 // nodeDefaults sets the common default field values shared by all constructors.
@@ -739,6 +797,7 @@ func (n *Node) dispose() {
 	n.cachedCommands = nil
 	n.customImage = nil
 	n.customEmit = nil
+	n.update = nil
 	n.MeshImage = nil
 	n.transformedVerts = nil
 	n.Emitter = nil
@@ -754,6 +813,12 @@ func (n *Node) dispose() {
 	n.OnPinch = nil
 	n.OnPointerEnter = nil
 	n.OnPointerLeave = nil
+	n.OnDoubleClick = nil
+	n.OnLongPress = nil
+	n.OnWheel = nil
+	n.OnFocus = nil
+	n.OnBlur = nil
+	n.tileLayer = nil
 }
 
 // IsDisposed returns true if this node has been disposed.
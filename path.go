@@ -0,0 +1,342 @@
+package willow
+
+import (
+	"math"
+	"sort"
+
+	"github.com/tanema/gween/ease"
+)
+
+// PathKind selects how a Path interpolates its points.
+type PathKind uint8
+
+const (
+	// PathPolyline joins the points with straight segments.
+	PathPolyline PathKind = iota
+	// PathCatmullRom passes a smooth Catmull-Rom spline through every point.
+	PathCatmullRom
+	// PathBezier chains cubic Bézier segments. Points are laid out as
+	// anchor, control, control, anchor, control, control, anchor, ... so an
+	// open path has 3n+1 points and a closed one 3n.
+	PathBezier
+)
+
+// pathSteps is the number of arc-length samples per curved segment.
+const pathSteps = 16
+
+// Path is a curve through a list of points that can be sampled by curve
+// parameter or by distance along its arc length. Ropes draw paths with
+// RopeCurvePath, and PathFollower moves nodes along them.
+type Path struct {
+	kind   PathKind
+	points []Vec2
+	closed bool
+
+	steps   int       // arc-length samples per segment
+	lengths []float64 // cumulative length at each sample
+}
+
+// NewPath creates a path of the given kind through points. A closed path
+// joins its last point back to its first.
+func NewPath(kind PathKind, points []Vec2, closed bool) *Path {
+	p := &Path{kind: kind, closed: closed}
+	p.SetPoints(points)
+	return p
+}
+
+// Kind returns how the path interpolates its points.
+func (p *Path) Kind() PathKind { return p.kind }
+
+// Closed reports whether the path loops back to its first point.
+func (p *Path) Closed() bool { return p.closed }
+
+// Points returns the path's points. Call SetPoints after modifying them.
+func (p *Path) Points() []Vec2 { return p.points }
+
+// SetPoints replaces the path's points and rebuilds its arc-length table.
+func (p *Path) SetPoints(points []Vec2) {
+	p.points = points
+	p.steps = pathSteps
+	if p.kind == PathPolyline {
+		p.steps = 1
+	}
+	n := p.segments()*p.steps + 1
+	if cap(p.lengths) < n {
+		p.lengths = make([]float64, n)
+	}
+	p.lengths = p.lengths[:n]
+	p.lengths[0] = 0
+	prev := p.at(0)
+	for i := 1; i < n; i++ {
+		cur := p.at(float64(i) / float64(p.steps))
+		dx, dy := cur.X-prev.X, cur.Y-prev.Y
+		p.lengths[i] = p.lengths[i-1] + math.Sqrt(dx*dx+dy*dy)
+		prev = cur
+	}
+}
+
+// Length returns the path's arc length in pixels.
+func (p *Path) Length() float64 {
+	return p.lengths[len(p.lengths)-1]
+}
+
+// PointAt returns the point at curve parameter t in [0, 1]. Each segment
+// covers an equal share of t, so speed varies with segment length; use
+// PointAtDistance for constant speed.
+func (p *Path) PointAt(t float64) Vec2 {
+	return p.at(p.paramAt(t))
+}
+
+// PointAtDistance returns the point d pixels along the path, clamped to
+// its ends.
+func (p *Path) PointAtDistance(d float64) Vec2 {
+	return p.at(p.paramAtDistance(d))
+}
+
+// AngleAt returns the direction of travel in radians at curve parameter t.
+func (p *Path) AngleAt(t float64) float64 {
+	return p.angle(p.paramAt(t))
+}
+
+// AngleAtDistance returns the direction of travel in radians d pixels along
+// the path.
+func (p *Path) AngleAtDistance(d float64) float64 {
+	return p.angle(p.paramAtDistance(d))
+}
+
+// Sample fills buf with n points spaced evenly along the arc length and
+// returns it, growing buf if needed.
+func (p *Path) Sample(buf []Vec2, n int) []Vec2 {
+	if n < 2 {
+		n = 2
+	}
+	if cap(buf) < n {
+		buf = make([]Vec2, n)
+	}
+	buf = buf[:n]
+	total := p.Length()
+	for i := range buf {
+		buf[i] = p.PointAtDistance(total * float64(i) / float64(n-1))
+	}
+	return buf
+}
+
+// segments returns the number of curve segments.
+func (p *Path) segments() int {
+	n := len(p.points)
+	switch {
+	case n < 2:
+		return 0
+	case p.kind == PathBezier && p.closed:
+		return n / 3
+	case p.kind == PathBezier:
+		return (n - 1) / 3
+	case p.closed:
+		return n
+	default:
+		return n - 1
+	}
+}
+
+// paramAt maps t in [0, 1] to the global segment parameter.
+func (p *Path) paramAt(t float64) float64 {
+	return math.Max(0, math.Min(t, 1)) * float64(p.segments())
+}
+
+// paramAtDistance maps an arc length to the global segment parameter.
+func (p *Path) paramAtDistance(d float64) float64 {
+	l := p.lengths
+	if d <= 0 || len(l) < 2 {
+		return 0
+	}
+	i := sort.SearchFloat64s(l, d)
+	if i >= len(l) {
+		return float64(p.segments())
+	}
+	span := l[i] - l[i-1]
+	f := 0.0
+	if span > 0 {
+		f = (d - l[i-1]) / span
+	}
+	return (float64(i-1) + f) / float64(p.steps)
+}
+
+// angle returns the tangent direction at global parameter u.
+func (p *Path) angle(u float64) float64 {
+	const h = 1e-3
+	segs := float64(p.segments())
+	a, b := math.Max(u-h, 0), math.Min(u+h, segs)
+	pa, pb := p.at(a), p.at(b)
+	return math.Atan2(pb.Y-pa.Y, pb.X-pa.X)
+}
+
+// at evaluates the path at global parameter u (segment index plus the
+// position within it).
+func (p *Path) at(u float64) Vec2 {
+	segs := p.segments()
+	if segs == 0 {
+		if len(p.points) == 1 {
+			return p.points[0]
+		}
+		return Vec2{}
+	}
+	seg := int(u)
+	if seg >= segs {
+		seg = segs - 1
+	}
+	seg = max(seg, 0)
+	t := math.Max(0, math.Min(u-float64(seg), 1))
+	pt := func(i int) Vec2 {
+		n := len(p.points)
+		if p.closed {
+			return p.points[((i%n)+n)%n]
+		}
+		return p.points[max(0, min(i, n-1))]
+	}
+
+	switch p.kind {
+	case PathCatmullRom:
+		return catmullRom(pt(seg-1), pt(seg), pt(seg+1), pt(seg+2), t)
+	case PathBezier:
+		i := seg * 3
+		return cubicBezier(pt(i), pt(i+1), pt(i+2), pt(i+3), t)
+	default:
+		a, b := pt(seg), pt(seg+1)
+		return Vec2{X: a.X + (b.X-a.X)*t, Y: a.Y + (b.Y-a.Y)*t}
+	}
+}
+
+// quadBezier evaluates a quadratic Bézier curve at t.
+func quadBezier(a, c, b Vec2, t float64) Vec2 {
+	u := 1 - t
+	return Vec2{
+		X: u*u*a.X + 2*u*t*c.X + t*t*b.X,
+		Y: u*u*a.Y + 2*u*t*c.Y + t*t*b.Y,
+	}
+}
+
+// cubicBezier evaluates a cubic Bézier curve at t.
+func cubicBezier(a, c1, c2, b Vec2, t float64) Vec2 {
+	u := 1 - t
+	u2 := u * u
+	t2 := t * t
+	return Vec2{
+		X: u2*u*a.X + 3*u2*t*c1.X + 3*u*t2*c2.X + t2*t*b.X,
+		Y: u2*u*a.Y + 3*u2*t*c1.Y + 3*u*t2*c2.Y + t2*t*b.Y,
+	}
+}
+
+// catmullRom evaluates the uniform Catmull-Rom segment between p1 and p2.
+func catmullRom(p0, p1, p2, p3 Vec2, t float64) Vec2 {
+	t2 := t * t
+	t3 := t2 * t
+	return Vec2{
+		X: 0.5 * (2*p1.X + (p2.X-p0.X)*t + (2*p0.X-5*p1.X+4*p2.X-p3.X)*t2 + (3*p1.X-p0.X-3*p2.X+p3.X)*t3),
+		Y: 0.5 * (2*p1.Y + (p2.Y-p0.Y)*t + (2*p0.Y-5*p1.Y+4*p2.Y-p3.Y)*t2 + (3*p1.Y-p0.Y-3*p2.Y+p3.Y)*t3),
+	}
+}
+
+// --- PathFollower ---
+
+// PathFollower moves a node along a Path. It implements the same interfaces
+// as TweenGroup, so it can be ticked by hand, registered with Scene.Tweens
+// or placed in a Timeline.
+type PathFollower struct {
+	// Path is the path to follow, in the node's parent coordinate space.
+	Path *Path
+	// Duration is the time in seconds for one traversal.
+	Duration float32
+	// Ease shapes progress over the traversal. Nil is linear.
+	Ease ease.TweenFunc
+	// ConstantSpeed eases over arc length, so a linear follower moves at a
+	// constant speed. When false, progress maps to the curve parameter and
+	// speed varies with segment length.
+	ConstantSpeed bool
+	// Orient sets the node's Rotation to the direction of travel plus
+	// RotationOffset.
+	Orient         bool
+	RotationOffset float64
+	// Loop restarts the traversal each time it ends; the follower is then
+	// never Done.
+	Loop bool
+
+	// Done is true once the traversal ends or the node is disposed.
+	Done bool
+
+	node    *Node
+	elapsed float32
+}
+
+// NewPathFollower creates a follower that moves node along path over
+// duration seconds at constant speed.
+func NewPathFollower(node *Node, path *Path, duration float32, fn ease.TweenFunc) *PathFollower {
+	return &PathFollower{Path: path, Duration: duration, Ease: fn, ConstantSpeed: true, node: node}
+}
+
+// SetSpeed sets Duration so a linear traversal moves at speed pixels per
+// second.
+func (f *PathFollower) SetSpeed(speed float64) {
+	if speed > 0 {
+		f.Duration = float32(f.Path.Length() / speed)
+	}
+}
+
+// Update advances the follower by dt seconds and moves the node.
+func (f *PathFollower) Update(dt float32) {
+	if f.Done {
+		return
+	}
+	if f.node.IsDisposed() {
+		f.Done = true
+		return
+	}
+	f.elapsed += dt
+	if f.Loop && f.Duration > 0 {
+		f.elapsed = float32(math.Mod(float64(f.elapsed), float64(f.Duration)))
+	}
+	f.seek(f.elapsed)
+	f.Done = !f.Loop && f.elapsed >= f.Duration
+}
+
+// seek places the node at time t of the traversal.
+func (f *PathFollower) seek(t float32) {
+	if f.node.IsDisposed() {
+		return
+	}
+	var p float64
+	switch {
+	case t <= 0:
+		p = 0
+	case t >= f.Duration:
+		p = 1
+	case f.Ease == nil:
+		p = float64(t / f.Duration)
+	default:
+		p = float64(f.Ease(t, 0, 1, f.Duration))
+	}
+	var pos Vec2
+	var angle float64
+	if f.ConstantSpeed {
+		d := p * f.Path.Length()
+		pos = f.Path.PointAtDistance(d)
+		angle = f.Path.AngleAtDistance(d)
+	} else {
+		pos = f.Path.PointAt(p)
+		angle = f.Path.AngleAt(p)
+	}
+	f.node.X, f.node.Y = pos.X, pos.Y
+	if f.Orient {
+		f.node.Rotation = angle + f.RotationOffset
+	}
+	f.node.Invalidate()
+}
+
+func (f *PathFollower) tweenDone() bool { return f.Done }
+
+func (f *PathFollower) tweenTargets(fn func(*Node) bool) bool { return fn(f.node) }
+
+func (f *PathFollower) timelineDuration() float32 { return f.Duration }
+
+func (f *PathFollower) timelineEnter() {}
+
+func (f *PathFollower) timelineSeek(t float32, _ bool) { f.seek(t) }
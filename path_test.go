@@ -0,0 +1,120 @@
+package willow
+
+import (
+	"math"
+	"testing"
+
+	"github.com/tanema/gween/ease"
+)
+
+func TestPathPolylineDistance(t *testing.T) {
+	p := NewPath(PathPolyline, []Vec2{{0, 0}, {100, 0}, {100, 50}}, false)
+	if p.Length() != 150 {
+		t.Fatalf("Length = %f, want 150", p.Length())
+	}
+	if pt := p.PointAtDistance(125); pt != (Vec2{100, 25}) {
+		t.Errorf("PointAtDistance(125) = %v, want {100 25}", pt)
+	}
+	// Curve parameter splits time evenly between segments.
+	if pt := p.PointAt(0.75); pt != (Vec2{100, 25}) {
+		t.Errorf("PointAt(0.75) = %v, want {100 25}", pt)
+	}
+	if a := p.AngleAtDistance(125); math.Abs(a-math.Pi/2) > 1e-6 {
+		t.Errorf("angle = %f, want pi/2", a)
+	}
+
+	closed := NewPath(PathPolyline, []Vec2{{0, 0}, {10, 0}, {10, 10}, {0, 10}}, true)
+	if closed.Length() != 40 {
+		t.Errorf("closed Length = %f, want 40", closed.Length())
+	}
+}
+
+func TestPathCurvesPassThroughAnchors(t *testing.T) {
+	pts := []Vec2{{0, 0}, {50, 40}, {100, 0}, {150, 40}}
+	cr := NewPath(PathCatmullRom, pts, false)
+	for i, want := range pts {
+		got := cr.PointAt(float64(i) / 3)
+		if math.Abs(got.X-want.X) > 1e-9 || math.Abs(got.Y-want.Y) > 1e-9 {
+			t.Errorf("catmull-rom point %d = %v, want %v", i, got, want)
+		}
+	}
+	if cr.Length() <= 150 {
+		t.Errorf("catmull-rom Length = %f, want longer than the chord", cr.Length())
+	}
+
+	bz := NewPath(PathBezier, []Vec2{{0, 0}, {0, 50}, {100, 50}, {100, 0}}, false)
+	if mid := bz.PointAt(0.5); mid != (Vec2{50, 37.5}) {
+		t.Errorf("bezier mid = %v, want {50 37.5}", mid)
+	}
+	if got := cubicBezier(Vec2{}, Vec2{0, 50}, Vec2{100, 50}, Vec2{100, 0}, 0.5); got != bz.PointAt(0.5) {
+		t.Errorf("path and shared cubicBezier disagree: %v", got)
+	}
+}
+
+func TestPathSampleEvenSpacing(t *testing.T) {
+	p := NewPath(PathCatmullRom, []Vec2{{0, 0}, {100, 40}, {200, 0}}, false)
+	pts := p.Sample(nil, 11)
+	step := p.Length() / 10
+	for i := 1; i < len(pts); i++ {
+		dx, dy := pts[i].X-pts[i-1].X, pts[i].Y-pts[i-1].Y
+		if d := math.Sqrt(dx*dx + dy*dy); math.Abs(d-step) > step*0.05 {
+			t.Errorf("sample %d spacing %f, want ~%f", i, d, step)
+		}
+	}
+}
+
+func TestPathFollower(t *testing.T) {
+	n := NewContainer("walker")
+	p := NewPath(PathPolyline, []Vec2{{0, 0}, {100, 0}, {100, 100}}, false)
+	f := NewPathFollower(n, p, 2, ease.Linear)
+	f.Orient = true
+
+	f.Update(1.5)
+	if n.X != 100 || n.Y != 50 {
+		t.Errorf("position = %f,%f, want 100,50", n.X, n.Y)
+	}
+	if math.Abs(n.Rotation-math.Pi/2) > 1e-6 {
+		t.Errorf("Rotation = %f, want pi/2", n.Rotation)
+	}
+	f.Update(1)
+	if !f.Done || n.Y != 100 {
+		t.Errorf("Done=%v Y=%f", f.Done, n.Y)
+	}
+
+	f = NewPathFollower(n, p, 0, nil)
+	f.SetSpeed(100)
+	f.Loop = true
+	f.Update(2.5)
+	if f.Done || n.X != 50 || n.Y != 0 {
+		t.Errorf("loop: Done=%v pos=%f,%f, want 50,0", f.Done, n.X, n.Y)
+	}
+}
+
+func TestPathFollowerInTimeline(t *testing.T) {
+	s := NewScene()
+	n := NewContainer("walker")
+	p := NewPath(PathPolyline, []Vec2{{0, 0}, {100, 0}}, false)
+	tl := NewTimeline().Then(NewPathFollower(n, p, 1, ease.Linear))
+	s.Tweens().Add(tl)
+	tl.Seek(0.25)
+	if n.X != 25 {
+		t.Errorf("X = %f, want 25", n.X)
+	}
+	n.Dispose()
+	s.Update()
+	if s.Tweens().Len() != 0 {
+		t.Error("timeline kept after its follower's node was disposed")
+	}
+}
+
+func TestRopeCurvePath(t *testing.T) {
+	path := NewPath(PathPolyline, []Vec2{{0, 0}, {100, 0}}, false)
+	r, n := NewRope("rope", nil, nil, RopeConfig{Width: 4, CurveMode: RopeCurvePath, Path: path, Segments: 4})
+	r.Update()
+	if len(n.Vertices) != 10 {
+		t.Fatalf("vertices = %d, want 10", len(n.Vertices))
+	}
+	if n.Vertices[4].DstX != 50 {
+		t.Errorf("middle vertex X = %f, want 50", n.Vertices[4].DstX)
+	}
+}
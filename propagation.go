@@ -0,0 +1,144 @@
+package willow
+
+// EventPhase identifies which stage of propagation a pointer event handler
+// is running in.
+type EventPhase uint8
+
+const (
+	// PhaseTarget runs the callback on the node the event happened on.
+	PhaseTarget EventPhase = iota
+	// PhaseCapture runs scene-level handlers, then callbacks on ancestors
+	// with CaptureEvents set, from the root down, before the target.
+	PhaseCapture
+	// PhaseBubble runs callbacks on the remaining ancestors, from the parent
+	// up to the root, after the target.
+	PhaseBubble
+)
+
+// propagation is shared by every copy of one event's context, so
+// StopPropagation and PreventDefault calls from any handler reach the
+// dispatcher.
+type propagation struct {
+	stopped   bool
+	prevented bool
+}
+
+func (p *propagation) stop() {
+	if p != nil {
+		p.stopped = true
+	}
+}
+
+func (p *propagation) preventDefault() {
+	if p != nil {
+		p.prevented = true
+	}
+}
+
+func (p *propagation) defaultPrevented() bool {
+	return p != nil && p.prevented
+}
+
+// StopPropagation stops the event from reaching any further nodes.
+func (c PointerContext) StopPropagation() { c.prop.stop() }
+
+// PreventDefault cancels the event's built-in behavior. For pointer down,
+// the pressed node does not take focus and the press cannot start a drag.
+func (c PointerContext) PreventDefault() { c.prop.preventDefault() }
+
+// DefaultPrevented reports whether a handler called PreventDefault.
+func (c PointerContext) DefaultPrevented() bool { return c.prop.defaultPrevented() }
+
+// StopPropagation stops the event from reaching any further nodes.
+func (c ClickContext) StopPropagation() { c.prop.stop() }
+
+// PreventDefault marks the click as handled. Clicks have no built-in
+// behavior; handlers further along can check DefaultPrevented.
+func (c ClickContext) PreventDefault() { c.prop.preventDefault() }
+
+// DefaultPrevented reports whether a handler called PreventDefault.
+func (c ClickContext) DefaultPrevented() bool { return c.prop.defaultPrevented() }
+
+// StopPropagation stops the event from reaching any further nodes.
+func (c DragContext) StopPropagation() { c.prop.stop() }
+
+// PreventDefault cancels the event's built-in behavior. For drag start, the
+// drag is cancelled: no drag or drag end events follow for this press.
+func (c DragContext) PreventDefault() { c.prop.preventDefault() }
+
+// DefaultPrevented reports whether a handler called PreventDefault.
+func (c DragContext) DefaultPrevented() bool { return c.prop.defaultPrevented() }
+
+// StopPropagation stops the event from reaching any further nodes.
+func (c WheelContext) StopPropagation() { c.prop.stop() }
+
+// PreventDefault cancels the event's built-in behavior: PanZoomController
+// does not zoom on this scroll.
+func (c WheelContext) PreventDefault() { c.prop.preventDefault() }
+
+// DefaultPrevented reports whether a handler called PreventDefault.
+func (c WheelContext) DefaultPrevented() bool { return c.prop.defaultPrevented() }
+
+func (c PointerContext) at(n *Node, p EventPhase) PointerContext {
+	c.CurrentTarget, c.Phase = n, p
+	return c
+}
+
+func (c ClickContext) at(n *Node, p EventPhase) ClickContext {
+	c.CurrentTarget, c.Phase = n, p
+	return c
+}
+
+func (c DragContext) at(n *Node, p EventPhase) DragContext {
+	c.CurrentTarget, c.Phase = n, p
+	return c
+}
+
+func (c WheelContext) at(n *Node, p EventPhase) WheelContext {
+	c.CurrentTarget, c.Phase = n, p
+	return c
+}
+
+// eventContext is a context type that can be retargeted during dispatch.
+type eventContext[C any] interface {
+	at(n *Node, p EventPhase) C
+}
+
+// dispatchEvent delivers ctx to the per-node callbacks along target's
+// ancestor path: ancestors with CaptureEvents set from the root down, the
+// target itself, then the other ancestors bubbling up to the root. callback
+// returns a node's callback for the event, or nil. Dispatch ends as soon as
+// a handler, including a scene-level one that ran earlier, calls
+// StopPropagation.
+func dispatchEvent[C eventContext[C]](target *Node, prop *propagation, ctx C, callback func(*Node) func(C)) {
+	if target == nil || prop.stopped {
+		return
+	}
+	// Ancestors, nearest first. Most trees are shallow.
+	var buf [16]*Node
+	path := buf[:0]
+	for p := target.Parent; p != nil; p = p.Parent {
+		path = append(path, p)
+	}
+
+	for i := len(path) - 1; i >= 0; i-- {
+		n := path[i]
+		if fn := callback(n); n.CaptureEvents && fn != nil {
+			if fn(ctx.at(n, PhaseCapture)); prop.stopped {
+				return
+			}
+		}
+	}
+	if fn := callback(target); fn != nil {
+		if fn(ctx.at(target, PhaseTarget)); prop.stopped {
+			return
+		}
+	}
+	for _, n := range path {
+		if fn := callback(n); !n.CaptureEvents && fn != nil {
+			if fn(ctx.at(n, PhaseBubble)); prop.stopped {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package willow
+
+import (
+	"fmt"
+	"testing"
+)
+
+// compositeButton builds a container "button" holding a 50x50 sprite
+// "label" at the world origin.
+func compositeButton() (s *Scene, button, label *Node) {
+	s = NewScene()
+	button = NewContainer("button")
+	button.Interactable = true
+	label = NewSprite("label", TextureRegion{OriginalW: 50, OriginalH: 50})
+	label.Interactable = true
+	button.AddChild(label)
+	s.Root().AddChild(button)
+	updateWorldTransform(s.root, identityTransform, 1.0, false, false)
+	return s, button, label
+}
+
+func clickAt(s *Scene, x, y float64) {
+	s.processPointer(0, x, y, x, y, true, MouseButtonLeft, 0)
+	s.processPointer(0, x, y, x, y, false, MouseButtonLeft, 0)
+}
+
+func TestClickBubblesToAncestors(t *testing.T) {
+	s, button, label := compositeButton()
+	var log []string
+	record := func(ctx ClickContext) {
+		name := "scene"
+		if ctx.CurrentTarget != nil {
+			name = ctx.CurrentTarget.Name
+		}
+		if ctx.Node != label {
+			t.Errorf("%s: Node = %v, want the label", name, ctx.Node)
+		}
+		log = append(log, fmt.Sprintf("%s/%d", name, ctx.Phase))
+	}
+	s.OnClick(record)
+	button.OnClick = record
+	label.OnClick = record
+
+	clickAt(s, 10, 10)
+	want := fmt.Sprint([]string{
+		fmt.Sprintf("scene/%d", PhaseCapture),
+		fmt.Sprintf("label/%d", PhaseTarget),
+		fmt.Sprintf("button/%d", PhaseBubble),
+	})
+	if got := fmt.Sprint(log); got != want {
+		t.Errorf("bubble order = %s, want %s", got, want)
+	}
+
+	log = nil
+	button.CaptureEvents = true
+	clickAt(s, 10, 10)
+	want = fmt.Sprint([]string{
+		fmt.Sprintf("scene/%d", PhaseCapture),
+		fmt.Sprintf("button/%d", PhaseCapture),
+		fmt.Sprintf("label/%d", PhaseTarget),
+	})
+	if got := fmt.Sprint(log); got != want {
+		t.Errorf("capture order = %s, want %s", got, want)
+	}
+}
+
+func TestStopPropagation(t *testing.T) {
+	s, button, label := compositeButton()
+	var buttonClicks, labelClicks int
+	button.OnClick = func(ClickContext) { buttonClicks++ }
+	label.OnClick = func(ctx ClickContext) {
+		labelClicks++
+		ctx.StopPropagation()
+	}
+	clickAt(s, 10, 10)
+	if labelClicks != 1 || buttonClicks != 0 {
+		t.Errorf("label %d, button %d clicks; want 1, 0", labelClicks, buttonClicks)
+	}
+
+	// Stopping in a scene-level handler keeps the event from every node.
+	h := s.OnClick(func(ctx ClickContext) { ctx.StopPropagation() })
+	clickAt(s, 10, 10)
+	if labelClicks != 1 {
+		t.Error("scene-level StopPropagation did not reach node dispatch")
+	}
+	h.Remove()
+}
+
+func TestPreventDefaultPointerDown(t *testing.T) {
+	s, button, label := compositeButton()
+	button.HitShape = HitRect{Width: 50, Height: 50}
+	button.Focusable = true
+	var drags int
+	label.OnDragStart = func(DragContext) { drags++ }
+
+	clickAt(s, 10, 10)
+	if s.Focused() != button {
+		t.Fatalf("pressing the label focused %v, want the button", s.Focused())
+	}
+	s.SetFocus(nil)
+
+	button.OnPointerDown = func(ctx PointerContext) { ctx.PreventDefault() }
+	s.processPointer(0, 10, 10, 10, 10, true, MouseButtonLeft, 0)
+	s.processPointer(0, 40, 10, 40, 10, true, MouseButtonLeft, 0)
+	s.processPointer(0, 40, 10, 40, 10, false, MouseButtonLeft, 0)
+	if s.Focused() != nil || drags != 0 {
+		t.Errorf("prevented press focused %v and started %d drags", s.Focused(), drags)
+	}
+}
+
+func TestPreventDefaultDragStart(t *testing.T) {
+	s, _, label := compositeButton()
+	var drags, ends, clicks int
+	label.OnDragStart = func(ctx DragContext) { ctx.PreventDefault() }
+	label.OnDrag = func(DragContext) { drags++ }
+	label.OnDragEnd = func(DragContext) { ends++ }
+	label.OnClick = func(ClickContext) { clicks++ }
+
+	s.processPointer(0, 10, 10, 10, 10, true, MouseButtonLeft, 0)
+	s.processPointer(0, 30, 10, 30, 10, true, MouseButtonLeft, 0)
+	s.processPointer(0, 40, 10, 40, 10, true, MouseButtonLeft, 0)
+	s.processPointer(0, 40, 10, 40, 10, false, MouseButtonLeft, 0)
+	if drags != 0 || ends != 0 {
+		t.Errorf("cancelled drag fired %d drag and %d drag end events", drags, ends)
+	}
+	if clicks != 1 {
+		t.Errorf("clicks = %d, want 1 for a release over the same node", clicks)
+	}
+}
@@ -70,6 +70,33 @@ func affine32(m [6]float64) [6]float32 {
 	return [6]float32{float32(m[0]), float32(m[1]), float32(m[2]), float32(m[3]), float32(m[4]), float32(m[5])}
 }
 
+// filterCommandsByLayer drops commands whose RenderLayer is not in mask,
+// keeping the rest in order.
+func (s *Scene) filterCommandsByLayer(mask LayerMask) {
+	if mask == (LayerMask{}) {
+		return
+	}
+	kept := s.commands[:0]
+	for i := range s.commands {
+		if mask.Has(s.commands[i].RenderLayer) {
+			kept = append(kept, s.commands[i])
+		}
+	}
+	s.commands = kept
+}
+
+// dropCommandsDrawing removes commands that draw img, so a camera never
+// draws its own target texture into itself.
+func (s *Scene) dropCommandsDrawing(img *ebiten.Image) {
+	kept := s.commands[:0]
+	for i := range s.commands {
+		if s.commands[i].directImage != img {
+			kept = append(kept, s.commands[i])
+		}
+	}
+	s.commands = kept
+}
+
 // traverse walks the node tree depth-first, emitting render commands for
 // visible, renderable leaf nodes. It is read-only w.r.t. worldTransform —
 // transforms are computed by updateWorldTransform in Update, and traverse
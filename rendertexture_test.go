@@ -296,3 +296,72 @@ func BenchmarkRenderTextureDrawImage(b *testing.B) {
 		rt.DrawImageAt(src, 10, 10, BlendNormal)
 	}
 }
+
+func TestCameraRenderTarget(t *testing.T) {
+	s := NewScene()
+	box := NewSprite("box", TextureRegion{})
+	box.Color = Color{R: 1, G: 0, B: 0, A: 1}
+	box.ScaleX, box.ScaleY = 10, 10
+	s.Root().AddChild(box)
+
+	rt := NewRenderTexture(20, 20)
+	cam := s.NewCamera(Rect{Width: 20, Height: 20})
+	cam.X, cam.Y = 10, 10
+	cam.Target = rt
+	monitor := rt.NewSpriteNode("monitor")
+	monitor.X = 40
+	s.Root().AddChild(monitor)
+
+	// The camera sees the monitor too, but never draws its target into
+	// itself.
+	cam.CullEnabled = false
+	traverseSceneWithCamera(s, cam)
+	s.dropCommandsDrawing(rt.Image())
+	if len(s.commands) != 1 || s.commands[0].directImage == rt.Image() {
+		t.Errorf("target camera commands = %d, want only the box", len(s.commands))
+	}
+
+	// No screen camera: the implicit camera draws the monitor this frame,
+	// after the target camera rendered.
+	s.Draw(ebiten.NewImage(80, 40))
+	if len(s.commands) != 2 {
+		t.Errorf("screen commands = %d, want box and monitor", len(s.commands))
+	}
+}
+
+func TestCameraRenderTargetMapsInput(t *testing.T) {
+	s := NewScene()
+	button := NewSprite("button", TextureRegion{OriginalW: 10, OriginalH: 10})
+	button.Interactable = true
+	button.X, button.Y = 100, 100
+	s.Root().AddChild(button)
+
+	rt := NewRenderTexture(40, 40)
+	cam := s.NewCamera(Rect{Width: 40, Height: 40})
+	cam.X, cam.Y = 105, 105 // button spans texture pixels 15..25
+	cam.Target = rt
+	cam.MapInput = true
+	monitor := rt.NewSpriteNode("monitor")
+	monitor.Interactable = true
+	monitor.ScaleX, monitor.ScaleY = 2, 2
+	s.Root().AddChild(monitor)
+	updateWorldTransform(s.root, identityTransform, 1.0, false, false)
+
+	var hit *Node
+	var hitX float64
+	s.OnPointerDown(func(ctx PointerContext) { hit, hitX = ctx.Node, ctx.GlobalX })
+	// Screen (40,40) is texture pixel (20,20), world (105,105).
+	wx, wy := s.pointerToWorld(0, nil, 40, 40)
+	s.processPointer(0, wx, wy, 40, 40, true, MouseButtonLeft, 0)
+	if hit != button || !approxEqual(hitX, 105, 1e-9) {
+		t.Errorf("hit %v at x=%f, want button at 105", hit, hitX)
+	}
+
+	cam.MapInput = false
+	s.processPointer(0, wx, wy, 40, 40, false, MouseButtonLeft, 0)
+	wx, wy = s.pointerToWorld(0, nil, 40, 40)
+	s.processPointer(0, wx, wy, 40, 40, true, MouseButtonLeft, 0)
+	if hit != monitor {
+		t.Errorf("without MapInput hit %v, want monitor", hit)
+	}
+}
@@ -0,0 +1,162 @@
+package willow
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// ScaleMode selects how [Run] maps a virtual resolution onto the window.
+type ScaleMode uint8
+
+const (
+	// ScaleModeFit scales uniformly so the whole virtual screen is visible,
+	// letterboxing the leftover space. This is the default.
+	ScaleModeFit ScaleMode = iota
+	// ScaleModeFill scales uniformly so the window is covered, cropping the
+	// edges of the virtual screen that overflow it.
+	ScaleModeFill
+	// ScaleModeStretch scales each axis independently to fill the window,
+	// distorting the aspect ratio.
+	ScaleModeStretch
+	// ScaleModeInteger scales uniformly by the largest whole number that
+	// fits, with nearest filtering, and letterboxes the rest. Best for
+	// pixel art.
+	ScaleModeInteger
+	// ScaleModeExpand scales uniformly like ScaleModeFit, then grows the
+	// virtual screen along one axis to cover the window, so no bars are
+	// shown and more of the world is visible on wider or taller windows.
+	ScaleModeExpand
+)
+
+// screenLayout maps a virtual canvas onto the window.
+type screenLayout struct {
+	canvasW, canvasH int     // virtual canvas size in pixels
+	scaleX, scaleY   float64 // canvas to window scale
+	offX, offY       float64 // canvas origin in the window
+}
+
+// computeScreenLayout fits a vw x vh virtual screen into an outW x outH
+// window using mode.
+func computeScreenLayout(mode ScaleMode, vw, vh, outW, outH int) screenLayout {
+	l := screenLayout{canvasW: vw, canvasH: vh, scaleX: 1, scaleY: 1}
+	if vw <= 0 || vh <= 0 || outW <= 0 || outH <= 0 {
+		return l
+	}
+	fw, fh := float64(outW), float64(outH)
+	sx, sy := fw/float64(vw), fh/float64(vh)
+	switch mode {
+	case ScaleModeFill:
+		l.scaleX = math.Max(sx, sy)
+		l.scaleY = l.scaleX
+	case ScaleModeStretch:
+		l.scaleX, l.scaleY = sx, sy
+	case ScaleModeInteger:
+		l.scaleX = math.Max(1, math.Floor(math.Min(sx, sy)))
+		l.scaleY = l.scaleX
+	case ScaleModeExpand:
+		s := math.Min(sx, sy)
+		l.scaleX, l.scaleY = s, s
+		l.canvasW = max(vw, int(math.Round(fw/s)))
+		l.canvasH = max(vh, int(math.Round(fh/s)))
+	default:
+		l.scaleX = math.Min(sx, sy)
+		l.scaleY = l.scaleX
+	}
+	l.offX = math.Floor((fw - float64(l.canvasW)*l.scaleX) / 2)
+	l.offY = math.Floor((fh - float64(l.canvasH)*l.scaleY) / 2)
+	return l
+}
+
+// toCanvas converts a window position to virtual canvas coordinates.
+func (l *screenLayout) toCanvas(x, y float64) (float64, float64) {
+	return (x - l.offX) / l.scaleX, (y - l.offY) / l.scaleY
+}
+
+// pointerToCanvas converts a raw pointer position to the scene's screen
+// coordinates, undoing the virtual resolution scaling applied by Run.
+func (s *Scene) pointerToCanvas(x, y int) (float64, float64) {
+	if s.screenLayout == nil {
+		return float64(x), float64(y)
+	}
+	return s.screenLayout.toCanvas(float64(x), float64(y))
+}
+
+// ScreenSize returns the size of the scene's screen in pixels: the virtual
+// canvas when run with a virtual resolution, otherwise the window size set
+// in RunConfig. Returns 0, 0 if the scene is not run via [Run].
+func (s *Scene) ScreenSize() (w, h int) {
+	return s.screenW, s.screenH
+}
+
+// SetResizeFunc registers a callback that is called when the scene's screen
+// size changes, for example when the window is resized in
+// ScaleModeExpand. Camera viewports are already rescaled by then. Pass nil
+// to clear.
+func (s *Scene) SetResizeFunc(fn func(w, h int)) {
+	s.resizeFunc = fn
+}
+
+// resize records a new screen size and rescales the viewports of on-screen
+// cameras proportionally, so full-screen and split-screen layouts keep
+// covering the same share of the screen.
+func (s *Scene) resize(w, h int) {
+	pw, ph := s.screenW, s.screenH
+	if w == pw && h == ph {
+		return
+	}
+	s.screenW, s.screenH = w, h
+	if pw > 0 && ph > 0 {
+		kx, ky := float64(w)/float64(pw), float64(h)/float64(ph)
+		for _, cam := range s.cameras {
+			if cam.Target != nil {
+				continue
+			}
+			vp := &cam.Viewport
+			vp.X, vp.Width = vp.X*kx, vp.Width*kx
+			vp.Y, vp.Height = vp.Y*ky, vp.Height*ky
+			cam.Invalidate()
+		}
+	}
+	if s.resizeFunc != nil {
+		s.resizeFunc(w, h)
+	}
+}
+
+// layoutVirtual updates the shell's screen layout for a window of
+// outW x outH pixels.
+func (g *gameShell) layoutVirtual(outW, outH int) {
+	l := computeScreenLayout(g.mode, g.w, g.h, outW, outH)
+	g.layout = l
+	g.scene.screenLayout = &g.layout
+	g.scene.resize(l.canvasW, l.canvasH)
+}
+
+// drawVirtual renders the scene into the virtual canvas and composites it
+// onto the window with letterbox bars.
+func (g *gameShell) drawVirtual(screen *ebiten.Image) {
+	l := &g.layout
+	if g.canvas == nil || g.canvas.Bounds().Dx() != l.canvasW || g.canvas.Bounds().Dy() != l.canvasH {
+		if g.canvas != nil {
+			g.canvas.Deallocate()
+		}
+		g.canvas = ebiten.NewImage(l.canvasW, l.canvasH)
+	}
+	g.canvas.Clear()
+	g.drawScene(g.canvas)
+
+	if g.letterbox.A > 0 {
+		screen.Fill(g.letterbox.toRGBA())
+	} else {
+		screen.Clear()
+	}
+	var op ebiten.DrawImageOptions
+	if g.mode == ScaleModeInteger {
+		op.Filter = ebiten.FilterNearest
+	} else {
+		op.Filter = ebiten.FilterLinear
+	}
+	op.GeoM.Scale(l.scaleX, l.scaleY)
+	op.GeoM.Translate(l.offX, l.offY)
+	screen.DrawImage(g.canvas, &op)
+}
@@ -0,0 +1,67 @@
+package willow
+
+import "testing"
+
+func TestComputeScreenLayout(t *testing.T) {
+	tests := []struct {
+		mode       ScaleMode
+		want       screenLayout
+		outW, outH int
+	}{
+		// 320x180 into 1000x700.
+		{ScaleModeFit, screenLayout{320, 180, 3.125, 3.125, 0, 68}, 1000, 700},
+		{ScaleModeFill, screenLayout{320, 180, 700.0 / 180, 700.0 / 180, -123, 0}, 1000, 700},
+		{ScaleModeStretch, screenLayout{320, 180, 3.125, 700.0 / 180, 0, 0}, 1000, 700},
+		{ScaleModeInteger, screenLayout{320, 180, 3, 3, 20, 80}, 1000, 700},
+		{ScaleModeExpand, screenLayout{320, 224, 3.125, 3.125, 0, 0}, 1000, 700},
+		// Smaller than the virtual screen: integer never goes below 1x.
+		{ScaleModeInteger, screenLayout{320, 180, 1, 1, -10, -10}, 300, 160},
+	}
+	for _, tt := range tests {
+		got := computeScreenLayout(tt.mode, 320, 180, tt.outW, tt.outH)
+		if got != tt.want {
+			t.Errorf("mode %d into %dx%d = %+v, want %+v", tt.mode, tt.outW, tt.outH, got, tt.want)
+		}
+	}
+}
+
+func TestScreenLayoutMapsPointer(t *testing.T) {
+	s := NewScene()
+	g := &gameShell{scene: s, w: 320, h: 180, virtual: true, mode: ScaleModeInteger}
+	s.screenW, s.screenH = 320, 180
+	g.layoutVirtual(1000, 700)
+
+	// Window (20, 80) is the canvas origin; each canvas pixel is 3 wide.
+	x, y := s.pointerToCanvas(20+3*100, 80+3*50)
+	if x != 100 || y != 50 {
+		t.Errorf("pointerToCanvas = %f,%f, want 100,50", x, y)
+	}
+	if x, y := NewScene().pointerToCanvas(7, 9); x != 7 || y != 9 {
+		t.Errorf("unmapped pointer = %f,%f, want 7,9", x, y)
+	}
+}
+
+func TestSceneResizeAdaptsCameras(t *testing.T) {
+	s := NewScene()
+	s.screenW, s.screenH = 320, 180
+	left := s.NewCamera(Rect{Width: 160, Height: 180})
+	right := s.NewCamera(Rect{X: 160, Width: 160, Height: 180})
+	rt := s.NewCamera(Rect{Width: 64, Height: 64})
+	rt.Target = NewRenderTexture(64, 64)
+	var gotW, gotH int
+	s.SetResizeFunc(func(w, h int) { gotW, gotH = w, h })
+
+	g := &gameShell{scene: s, w: 320, h: 180, virtual: true, mode: ScaleModeExpand}
+	g.layoutVirtual(1280, 540) // 3x, canvas grows to 427x180
+
+	if w, h := s.ScreenSize(); w != 427 || h != 180 || gotW != 427 || gotH != 180 {
+		t.Fatalf("screen = %dx%d, callback %dx%d", w, h, gotW, gotH)
+	}
+	if !approxEqual(left.Viewport.Width, 213.5, 1e-9) || !approxEqual(right.Viewport.X, 213.5, 1e-9) ||
+		!approxEqual(right.Viewport.X+right.Viewport.Width, 427, 1e-9) {
+		t.Errorf("split viewports = %v %v", left.Viewport, right.Viewport)
+	}
+	if rt.Viewport.Width != 64 {
+		t.Error("render-target camera viewport should not change")
+	}
+}
@@ -2,6 +2,7 @@ package willow
 
 import (
 	"image"
+	"math"
 	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
@@ -25,7 +26,8 @@ type InteractionEvent struct {
 	LocalY    float64      // pointer Y in the hit node's local coordinates
 	Button    MouseButton  // which mouse button is involved
 	Modifiers KeyModifiers // keyboard modifier keys held during the event
-	// Drag fields (valid for EventDragStart, EventDrag, EventDragEnd)
+	// Drag fields (valid for EventDragStart, EventDrag, EventDragEnd). For
+	// EventWheel, DeltaX and DeltaY carry the scroll amount.
 	StartX       float64 // world X where the drag began
 	StartY       float64 // world Y where the drag began
 	DeltaX       float64 // X movement since the previous drag event
@@ -70,10 +72,18 @@ type Scene struct {
 
 	updateFunc   func() error               // user callback set via SetUpdateFunc
 	postDrawFunc func(screen *ebiten.Image) // user callback after Draw, set via SetPostDrawFunc
+	resizeFunc   func(w, h int)             // user callback on screen resize, set via SetResizeFunc
+
+	// Screen size and virtual resolution mapping (set by Run).
+	screenW, screenH int
+	screenLayout     *screenLayout // nil when pointer coordinates need no mapping
 
 	// Cameras
 	cameras []*Camera
 
+	// Tweens ticked during Update
+	tweens TweenRunner
+
 	// Batch mode
 	batchMode  BatchMode
 	batchVerts []ebiten.Vertex // preallocated vertex accumulation buffer
@@ -102,11 +112,23 @@ type Scene struct {
 	captured     [maxPointers]*Node
 	pointers     [maxPointers]pointerState
 	hitBuf       []*Node
+	hitMask      LayerMask // render mask of the camera the pointer is routed to
 	dragDeadZone float64
 	touchMap     [maxPointers]ebiten.TouchID
 	touchUsed    [maxPointers]bool
 	prevTouchIDs []ebiten.TouchID
 	pinch        pinchState
+	panZoom      []*PanZoomController
+	actions      *ActionMap
+	focused      *Node
+	focusNav     bool
+	focusBuf     []*Node
+
+	// Gesture timing and wheel state
+	inputTime       float64 // seconds of Update time, for gesture timing
+	doubleClickTime float64 // max seconds between the clicks of a double-click
+	longPressTime   float64 // seconds a pointer must be held for a long press
+	wheelBlocked    bool    // a wheel handler called PreventDefault this tick
 
 	// Screenshot capture (debug tool)
 	screenshotQueue []string
@@ -124,11 +146,15 @@ func NewScene() *Scene {
 	root := NewContainer("root")
 	root.Interactable = true
 	return &Scene{
-		root:          root,
-		commands:      make([]RenderCommand, 0, defaultCommandCap),
-		sortBuf:       make([]RenderCommand, 0, defaultCommandCap),
-		dragDeadZone:  defaultDragDeadZone,
-		ScreenshotDir: "screenshots",
+		root:            root,
+		commands:        make([]RenderCommand, 0, defaultCommandCap),
+		sortBuf:         make([]RenderCommand, 0, defaultCommandCap),
+		dragDeadZone:    defaultDragDeadZone,
+		doubleClickTime: defaultDoubleClickTime,
+		longPressTime:   defaultLongPressTime,
+		ScreenshotDir:   "screenshots",
+		tweens:          TweenRunner{TimeScale: 1},
+		actions:         NewActionMap(),
 	}
 }
 
@@ -146,6 +172,19 @@ type RunConfig struct {
 	// If zero, defaults to 640x480.
 	Width, Height int
 
+	// VirtualWidth and VirtualHeight set a resolution the scene is laid out
+	// at independently of the window. The scene renders to a canvas of this
+	// size, which is scaled onto the window according to ScaleMode, and
+	// pointer coordinates are mapped back to it. The window becomes
+	// resizable. If zero, the scene renders at Width x Height and Ebitengine
+	// scales it to the window.
+	VirtualWidth, VirtualHeight int
+	// ScaleMode selects how the virtual resolution fits the window.
+	ScaleMode ScaleMode
+	// LetterboxColor fills the bars around the scaled canvas. The zero
+	// value gives black bars.
+	LetterboxColor Color
+
 	// ShowFPS enables a small FPS/TPS widget in the top-left corner.
 	ShowFPS bool
 }
@@ -182,6 +221,14 @@ func Run(scene *Scene, cfg RunConfig) error {
 		ebiten.SetWindowTitle(cfg.Title)
 	}
 	g := &gameShell{scene: scene, w: w, h: h}
+	if cfg.VirtualWidth > 0 && cfg.VirtualHeight > 0 {
+		g.w, g.h = cfg.VirtualWidth, cfg.VirtualHeight
+		g.virtual = true
+		g.mode = cfg.ScaleMode
+		g.letterbox = cfg.LetterboxColor
+		ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
+	}
+	scene.screenW, scene.screenH = g.w, g.h
 	if cfg.ShowFPS {
 		g.fpsWid = NewFPSWidget()
 		g.fpsWid.X, g.fpsWid.Y = 8, 8
@@ -195,9 +242,18 @@ type gameShell struct {
 	scene  *Scene
 	w, h   int
 	fpsWid *Node // screen-space FPS overlay (not in scene graph)
+
+	// Virtual resolution (RunConfig.VirtualWidth/Height).
+	virtual   bool
+	mode      ScaleMode
+	letterbox Color
+	layout    screenLayout
+	canvas    *ebiten.Image
 }
 
 func (g *gameShell) Update() error {
+	// Poll actions before the update func so it sees this tick's input.
+	g.scene.actions.Update()
 	if g.scene.updateFunc != nil {
 		if err := g.scene.updateFunc(); err != nil {
 			return err
@@ -211,6 +267,15 @@ func (g *gameShell) Update() error {
 }
 
 func (g *gameShell) Draw(screen *ebiten.Image) {
+	if g.virtual {
+		g.drawVirtual(screen)
+		return
+	}
+	g.drawScene(screen)
+}
+
+// drawScene clears, draws the scene and overlays onto screen.
+func (g *gameShell) drawScene(screen *ebiten.Image) {
 	if g.scene.ClearColor.A > 0 {
 		screen.Fill(g.scene.ClearColor.toRGBA())
 	}
@@ -227,12 +292,21 @@ func (g *gameShell) Draw(screen *ebiten.Image) {
 }
 
 func (g *gameShell) Layout(outsideWidth, outsideHeight int) (int, int) {
+	if g.virtual {
+		// Lay out at device pixels so the canvas is scaled only once.
+		k := ebiten.Monitor().DeviceScaleFactor()
+		w := int(math.Ceil(float64(outsideWidth) * k))
+		h := int(math.Ceil(float64(outsideHeight) * k))
+		g.layoutVirtual(w, h)
+		return w, h
+	}
 	return g.w, g.h
 }
 
 // Update processes input, advances animations, and simulates particles.
 func (s *Scene) Update() {
 	dt := float32(1.0 / float64(ebiten.TPS()))
+	s.actions.Update()
 
 	// Refresh world transforms first so camera follow targets and hit testing
 	// have accurate positions this frame.
@@ -242,11 +316,20 @@ func (s *Scene) Update() {
 	for _, cam := range s.cameras {
 		cam.update(dt)
 	}
+	s.tweens.update(dt)
 	updateNodesAndParticles(s.root, float64(dt))
 	if s.testRunner != nil {
 		s.testRunner.step(s)
 	}
+	s.inputTime += float64(dt)
 	s.processInput()
+	s.checkFocus()
+	if s.focusNav {
+		s.processFocus(readFocusInput())
+	}
+	for _, p := range s.panZoom {
+		p.update(dt)
+	}
 }
 
 func updateNodesAndParticles(n *Node, dt float64) {
@@ -256,6 +339,9 @@ func updateNodesAndParticles(n *Node, dt float64) {
 	if n.OnUpdate != nil {
 		n.OnUpdate(dt)
 	}
+	if n.update != nil {
+		n.update(dt)
+	}
 	if n.Type == NodeTypeParticleEmitter && n.Emitter != nil {
 		if n.Emitter.config.WorldSpace {
 			n.Emitter.worldX = n.worldTransform[4]
@@ -276,23 +362,40 @@ func updateNodesAndParticles(n *Node, dt float64) {
 // Draw traverses the scene tree, emits render commands, sorts them, and submits
 // batches to the given screen image.
 func (s *Scene) Draw(screen *ebiten.Image) {
-	if len(s.cameras) == 0 {
-		// No explicit cameras: use implicit identity camera, full screen.
+	// Cameras with a Target render first so sprites showing their textures
+	// display this frame's content.
+	onScreen := 0
+	for _, cam := range s.cameras {
+		if cam.Target == nil {
+			onScreen++
+			continue
+		}
+		cameraSubImage(cam.Target.image, cam.Viewport).Clear()
+		s.drawCamera(cam.Target.image, cam)
+	}
+
+	if onScreen == 0 {
+		// No screen cameras: use implicit identity camera, full screen.
 		s.drawWithCamera(screen, nil)
 	} else {
 		for _, cam := range s.cameras {
-			vp := cam.Viewport
-			viewportImg := screen.SubImage(image.Rect(
-				int(vp.X), int(vp.Y),
-				int(vp.X+vp.Width), int(vp.Y+vp.Height),
-			)).(*ebiten.Image)
-			s.drawWithCamera(viewportImg, cam)
+			if cam.Target == nil {
+				s.drawCamera(screen, cam)
+			}
 		}
 	}
 
 	s.flushScreenshots(screen)
 }
 
+// cameraSubImage returns the part of dst covered by a camera viewport.
+func cameraSubImage(dst *ebiten.Image, vp Rect) *ebiten.Image {
+	return dst.SubImage(image.Rect(
+		int(vp.X), int(vp.Y),
+		int(vp.X+vp.Width), int(vp.Y+vp.Height),
+	)).(*ebiten.Image)
+}
+
 // drawWithCamera renders the scene from a camera's perspective.
 // If cam is nil, uses identity view (no camera).
 func (s *Scene) drawWithCamera(target *ebiten.Image, cam *Camera) {
@@ -306,7 +409,12 @@ func (s *Scene) drawWithCamera(target *ebiten.Image, cam *Camera) {
 	s.commands = s.commands[:0]
 	s.commandsDirtyThisFrame = false
 
-	if cam != nil {
+	if cam != nil && cam.PixelPerfect {
+		_, lw, lh, _ := cam.pixelLayout()
+		s.viewTransform = cam.pixelViewMatrix()
+		s.cullActive = cam.CullEnabled
+		s.cullBounds = Rect{Width: float64(lw), Height: float64(lh)}
+	} else if cam != nil {
 		s.viewTransform = cam.computeViewMatrix()
 		s.cullActive = cam.CullEnabled
 		if cam.CullEnabled {
@@ -326,6 +434,12 @@ func (s *Scene) drawWithCamera(target *ebiten.Image, cam *Camera) {
 
 	treeOrder := 0
 	s.traverse(s.root, &treeOrder)
+	if cam != nil {
+		s.filterCommandsByLayer(cam.RenderMask)
+		if cam.Target != nil {
+			s.dropCommandsDrawing(cam.Target.image)
+		}
+	}
 
 	if s.debug {
 		stats.traverseTime = time.Since(t0)
@@ -375,6 +489,16 @@ func (s *Scene) NewCamera(viewport Rect) *Camera {
 
 // RemoveCamera removes a camera from the scene.
 func (s *Scene) RemoveCamera(cam *Camera) {
+	for i := range s.pointers {
+		ps := &s.pointers[i]
+		if ps.camera == cam {
+			ps.camera = nil
+		}
+		if ps.throughCam == cam {
+			ps.throughCam, ps.throughNode = nil, nil
+		}
+	}
+	cam.releasePixelTarget()
 	for i, c := range s.cameras {
 		if c == cam {
 			s.cameras = append(s.cameras[:i], s.cameras[i+1:]...)
@@ -444,3 +568,19 @@ func (s *Scene) LoadAtlas(jsonData []byte, pages []*ebiten.Image) (*Atlas, error
 	}
 	return atlas, nil
 }
+
+// LoadAseprite parses an Aseprite JSON export, registers its pages with the
+// scene and returns the sheet. Pages are registered starting at the next
+// available page index, the same as LoadAtlas.
+func (s *Scene) LoadAseprite(jsonData []byte, pages []*ebiten.Image) (*AsepriteSheet, error) {
+	startIndex := s.nextPage
+	sheet, err := loadAseprite(jsonData, pages, uint16(startIndex))
+	if err != nil {
+		return nil, err
+	}
+	for i, page := range pages {
+		s.RegisterPage(startIndex+i, page)
+	}
+	s.nextPage = startIndex + len(pages)
+	return sheet, nil
+}
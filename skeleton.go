@@ -0,0 +1,762 @@
+package willow
+
+import (
+	"log"
+	"math"
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// BoneData is the setup pose of one bone. Positions are in the parent
+// bone's space with y pointing down; Rotation is in radians, clockwise.
+type BoneData struct {
+	Name   string
+	Parent int // index into SkeletonData.Bones, -1 for a root bone
+	X, Y   float64
+	// Rotation, ScaleX and ScaleY are the bone's local transform.
+	Rotation       float64
+	ScaleX, ScaleY float64
+	Length         float64 // informational; used by debug drawing and tools
+}
+
+// SlotData is the setup state of one slot. Slots are drawn in the order
+// they appear in SkeletonData.Slots.
+type SlotData struct {
+	Name       string
+	Bone       int // index into SkeletonData.Bones
+	Color      Color
+	Attachment string // attachment shown in the setup pose, "" for none
+	BlendMode  BlendMode
+}
+
+// AttachmentKind distinguishes the kinds of SkeletonAttachment.
+type AttachmentKind uint8
+
+const (
+	// AttachmentRegion is a textured quad placed relative to its bone.
+	AttachmentRegion AttachmentKind = iota
+	// AttachmentMesh is a textured triangle mesh whose vertices follow one
+	// bone or are weighted across several.
+	AttachmentMesh
+)
+
+// VertexWeight is one bone's influence on a weighted mesh vertex. X and Y
+// are the vertex position in that bone's space.
+type VertexWeight struct {
+	Bone   int
+	X, Y   float64
+	Weight float64
+}
+
+// SkeletonAttachment is an image that a slot can show.
+type SkeletonAttachment struct {
+	Name   string
+	Kind   AttachmentKind
+	Region TextureRegion
+	Image  *ebiten.Image // atlas page holding Region
+
+	// Region attachments: the quad's center, rotation (radians) and scale
+	// in bone space, and its size in pixels.
+	X, Y           float64
+	Rotation       float64
+	ScaleX, ScaleY float64
+	Width, Height  float64
+
+	// Mesh attachments: per-vertex texture coordinates in [0, 1] over the
+	// packed region, and triangle indices. Unweighted meshes give bone-space
+	// positions in Vertices; weighted meshes give per-vertex influences in
+	// Weights instead.
+	UVs       []Vec2
+	Triangles []uint16
+	Vertices  []Vec2
+	Weights   [][]VertexWeight
+}
+
+// SkeletonSkin maps slot and attachment names to attachments.
+type SkeletonSkin struct {
+	Name        string
+	attachments map[skinKey]*SkeletonAttachment
+}
+
+type skinKey struct {
+	slot int
+	name string
+}
+
+// Attachment returns the skin's attachment for a slot, or nil.
+func (s *SkeletonSkin) Attachment(slot int, name string) *SkeletonAttachment {
+	if s == nil {
+		return nil
+	}
+	return s.attachments[skinKey{slot, name}]
+}
+
+// SkeletonData is a loaded skeleton: bones, slots, skins and animations.
+// It is immutable once loaded and can be shared by any number of
+// Skeleton instances.
+type SkeletonData struct {
+	Bones      []BoneData
+	Slots      []SlotData
+	Skins      map[string]*SkeletonSkin
+	Animations map[string]*SkeletonAnimation
+}
+
+// FindBone returns the index of the named bone, or -1.
+func (d *SkeletonData) FindBone(name string) int {
+	for i := range d.Bones {
+		if d.Bones[i].Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// FindSlot returns the index of the named slot, or -1.
+func (d *SkeletonData) FindSlot(name string) int {
+	for i := range d.Slots {
+		if d.Slots[i].Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// --- Animations ---
+
+// SkeletonAnimation is a set of keyframed bone and slot timelines.
+type SkeletonAnimation struct {
+	Name     string
+	Duration float64 // seconds; the time of the last key or event
+
+	bones  []boneTimeline
+	slots  []slotTimeline
+	events []skeletonEvent
+}
+
+type boneTimelineKind uint8
+
+const (
+	boneRotate boneTimelineKind = iota
+	boneTranslate
+	boneScale
+)
+
+// curve shapes the interpolation from one key to the next.
+type curve struct {
+	stepped bool
+	bezier  bool
+	c       [4]float64 // cx1, cy1, cx2, cy2 of a bezier curve
+}
+
+// apply maps linear progress p in [0, 1] through the curve.
+func (c curve) apply(p float64) float64 {
+	switch {
+	case c.stepped:
+		return 0
+	case !c.bezier:
+		return p
+	}
+	// Solve x(s) = p by bisection, then evaluate y(s).
+	lo, hi := 0.0, 1.0
+	s := p
+	for i := 0; i < 16; i++ {
+		if bez1(c.c[0], c.c[2], s) < p {
+			lo = s
+		} else {
+			hi = s
+		}
+		s = (lo + hi) / 2
+	}
+	return bez1(c.c[1], c.c[3], s)
+}
+
+// bez1 evaluates one axis of a cubic bezier from 0 to 1 with the given
+// control values.
+func bez1(c1, c2, s float64) float64 {
+	u := 1 - s
+	return 3*u*u*s*c1 + 3*u*s*s*c2 + s*s*s
+}
+
+type boneKey struct {
+	time  float64
+	x, y  float64 // rotate keys use x only, in radians
+	curve curve
+}
+
+type boneTimeline struct {
+	bone int
+	kind boneTimelineKind
+	keys []boneKey
+}
+
+// sample returns the timeline's values at time t.
+func (tl *boneTimeline) sample(t float64) (float64, float64) {
+	k := tl.keys
+	i := sort.Search(len(k), func(i int) bool { return k[i].time > t })
+	if i == 0 {
+		return k[0].x, k[0].y
+	}
+	if i == len(k) {
+		return k[i-1].x, k[i-1].y
+	}
+	a, b := &k[i-1], &k[i]
+	p := a.curve.apply((t - a.time) / (b.time - a.time))
+	return a.x + (b.x-a.x)*p, a.y + (b.y-a.y)*p
+}
+
+type attachmentKey struct {
+	time float64
+	name string
+}
+
+type colorKey struct {
+	time  float64
+	color Color
+	curve curve
+}
+
+type slotTimeline struct {
+	slot        int
+	attachments []attachmentKey
+	colors      []colorKey
+}
+
+type skeletonEvent struct {
+	time float64
+	name string
+}
+
+// --- Runtime ---
+
+// Skeleton is an instance of SkeletonData driving a node tree. Each bone
+// is a container node, so other nodes can be attached to a bone with
+// Bone(name).AddChild; each slot is a mesh node drawn in slot order below
+// the bones. Playback is advanced during Scene.Update, so the node must be
+// part of the scene tree. The root node's OnUpdate stays free for other
+// uses.
+//
+// Animations play on tracks. Higher tracks are applied over lower ones,
+// mixed in by their Alpha, so a track can layer an aim or blink over a
+// walk cycle. CrossFade blends a track from its current animation into a
+// new one.
+type Skeleton struct {
+	data  *SkeletonData
+	node  *Node
+	bones []*Node
+	world [][6]float64 // skeleton-space bone matrices
+	slots []skeletonSlot
+	skin  *SkeletonSkin
+
+	tracks []*SkeletonTrack
+
+	// Speed scales playback time. 1 is normal speed.
+	Speed float64
+
+	// OnEvent fires when playback crosses a keyed event.
+	OnEvent func(anim, event string)
+	// OnComplete fires when a non-looping animation reaches its end.
+	OnComplete func(anim string)
+	// OnPose is called each update after animations are applied and before
+	// attachments are deformed. Adjust bone nodes here to override the
+	// animated pose, e.g. to aim a head at the pointer.
+	OnPose func()
+}
+
+type skeletonSlot struct {
+	node       *Node
+	base       string              // attachment shown when no animation keys the slot
+	next       *SkeletonAttachment // attachment chosen by the current pose
+	attachment *SkeletonAttachment // attachment the mesh is built for
+}
+
+// SkeletonTrack is one layer of animation on a Skeleton.
+type SkeletonTrack struct {
+	// Alpha is the weight this track is mixed in with, from 0 to 1.
+	Alpha float64
+	// Loop restarts the animation when it ends.
+	Loop bool
+
+	anim     *SkeletonAnimation
+	time     float64
+	complete bool
+
+	// Crossfade source.
+	from     *SkeletonAnimation
+	fromTime float64
+	fromLoop bool
+	mixTime  float64
+	mixDur   float64
+}
+
+// Animation returns the name of the track's animation.
+func (t *SkeletonTrack) Animation() string {
+	return t.anim.Name
+}
+
+// Time returns the seconds the track has been playing.
+func (t *SkeletonTrack) Time() float64 {
+	return t.time
+}
+
+// SetTime jumps the track to the given time without firing events.
+func (t *SkeletonTrack) SetTime(sec float64) {
+	t.time = sec
+	t.complete = false
+}
+
+// NewSkeleton creates a skeleton instance in its setup pose using the
+// "default" skin.
+func NewSkeleton(name string, data *SkeletonData) *Skeleton {
+	s := &Skeleton{
+		data:  data,
+		node:  NewContainer(name),
+		bones: make([]*Node, len(data.Bones)),
+		world: make([][6]float64, len(data.Bones)),
+		slots: make([]skeletonSlot, len(data.Slots)),
+		skin:  data.Skins["default"],
+		Speed: 1,
+	}
+	for i, sd := range data.Slots {
+		n := NewMesh(sd.Name, nil, nil, nil)
+		n.BlendMode = sd.BlendMode
+		s.slots[i].node = n
+		s.slots[i].base = sd.Attachment
+		s.node.AddChild(n)
+	}
+	for i, bd := range data.Bones {
+		b := NewContainer(bd.Name)
+		s.bones[i] = b
+		if bd.Parent < 0 {
+			s.node.AddChild(b)
+		} else {
+			s.bones[bd.Parent].AddChild(b)
+		}
+	}
+	s.node.update = s.update
+	s.pose()
+	return s
+}
+
+// Node returns the skeleton's root node.
+func (s *Skeleton) Node() *Node {
+	return s.node
+}
+
+// Data returns the skeleton's shared data.
+func (s *Skeleton) Data() *SkeletonData {
+	return s.data
+}
+
+// Bone returns the node of the named bone, or nil.
+func (s *Skeleton) Bone(name string) *Node {
+	if i := s.data.FindBone(name); i >= 0 {
+		return s.bones[i]
+	}
+	return nil
+}
+
+// Slot returns the mesh node of the named slot, or nil. Its Color is
+// rewritten from the slot's setup and animated color on every update.
+func (s *Skeleton) Slot(name string) *Node {
+	if i := s.data.FindSlot(name); i >= 0 {
+		return s.slots[i].node
+	}
+	return nil
+}
+
+// SetSkin switches to the named skin. Attachments missing from it fall
+// back to the "default" skin. Unknown names are ignored (with a warning in
+// debug mode).
+func (s *Skeleton) SetSkin(name string) {
+	skin, ok := s.data.Skins[name]
+	if !ok {
+		if globalDebug {
+			log.Printf("willow: skeleton %q has no skin %q", s.node.Name, name)
+		}
+		return
+	}
+	s.skin = skin
+	s.pose()
+}
+
+// SetAttachment shows the named attachment in a slot whenever no playing
+// animation keys that slot's attachment; "" hides the slot.
+func (s *Skeleton) SetAttachment(slot, attachment string) {
+	if i := s.data.FindSlot(slot); i >= 0 {
+		s.slots[i].base = attachment
+		s.pose()
+	}
+}
+
+// SetAnimation starts the named animation on a track, replacing whatever
+// the track was playing. Unknown names return nil (with a warning in debug
+// mode).
+func (s *Skeleton) SetAnimation(track int, name string, loop bool) *SkeletonTrack {
+	return s.CrossFade(track, name, 0, loop)
+}
+
+// CrossFade starts the named animation on a track, blending from the
+// track's current animation over duration seconds.
+func (s *Skeleton) CrossFade(track int, name string, duration float64, loop bool) *SkeletonTrack {
+	anim, ok := s.data.Animations[name]
+	if !ok {
+		if globalDebug {
+			log.Printf("willow: skeleton %q has no animation %q", s.node.Name, name)
+		}
+		return nil
+	}
+	for len(s.tracks) <= track {
+		s.tracks = append(s.tracks, nil)
+	}
+	t := &SkeletonTrack{Alpha: 1, Loop: loop, anim: anim}
+	if old := s.tracks[track]; old != nil && duration > 0 {
+		t.Alpha = old.Alpha
+		t.from, t.fromTime, t.fromLoop = old.anim, old.time, old.Loop
+		t.mixDur = duration
+	}
+	s.tracks[track] = t
+	return t
+}
+
+// Track returns the track at the given index, or nil if it is empty.
+func (s *Skeleton) Track(track int) *SkeletonTrack {
+	if track < 0 || track >= len(s.tracks) {
+		return nil
+	}
+	return s.tracks[track]
+}
+
+// ClearTrack stops the animation on a track.
+func (s *Skeleton) ClearTrack(track int) {
+	if track >= 0 && track < len(s.tracks) {
+		s.tracks[track] = nil
+	}
+}
+
+// update advances playback by dt seconds. Called from Scene.Update.
+func (s *Skeleton) update(dt float64) {
+	dt *= max(s.Speed, 0)
+	for _, t := range s.tracks {
+		if t == nil {
+			continue
+		}
+		prev := t.time
+		t.time += dt
+		s.fireEvents(t, prev)
+		if t.from != nil {
+			t.fromTime += dt
+			t.mixTime += dt
+			if t.mixTime >= t.mixDur {
+				t.from = nil
+			}
+		}
+		if !t.Loop && !t.complete && t.time >= t.anim.Duration {
+			t.complete = true
+			if s.OnComplete != nil {
+				s.OnComplete(t.anim.Name)
+			}
+		}
+	}
+	s.pose()
+}
+
+// fireEvents fires the track's events between prev and its current time.
+func (s *Skeleton) fireEvents(t *SkeletonTrack, prev float64) {
+	a := t.anim
+	if s.OnEvent == nil || len(a.events) == 0 {
+		return
+	}
+	if !t.Loop || a.Duration <= 0 {
+		for _, e := range a.events {
+			if e.time > prev && e.time <= t.time || (prev == 0 && e.time == 0) {
+				s.OnEvent(a.Name, e.name)
+			}
+		}
+		return
+	}
+	// Walk each loop iteration the step covers.
+	start := math.Floor(prev/a.Duration) * a.Duration
+	for base := start; base <= t.time; base += a.Duration {
+		for _, e := range a.events {
+			at := base + e.time
+			if at > prev && at <= t.time || (prev == 0 && at == 0) {
+				s.OnEvent(a.Name, e.name)
+			}
+		}
+	}
+}
+
+// pose resets the setup pose, applies every track, then deforms the
+// attachments.
+func (s *Skeleton) pose() {
+	for i, bd := range s.data.Bones {
+		b := s.bones[i]
+		b.X, b.Y = bd.X, bd.Y
+		b.Rotation = bd.Rotation
+		b.ScaleX, b.ScaleY = bd.ScaleX, bd.ScaleY
+		b.Invalidate()
+	}
+	for i, sd := range s.data.Slots {
+		s.slots[i].node.Color = sd.Color
+		s.slots[i].next = s.resolveAttachment(i, s.slots[i].base)
+	}
+	for _, t := range s.tracks {
+		if t == nil || t.Alpha <= 0 {
+			continue
+		}
+		if t.from != nil {
+			mix := t.Alpha * t.mixTime / t.mixDur
+			s.apply(t.from, animTime(t.from, t.fromTime, t.fromLoop), t.Alpha)
+			s.apply(t.anim, animTime(t.anim, t.time, t.Loop), mix)
+			s.mixOut(t.from, t.anim, mix)
+		} else {
+			s.apply(t.anim, animTime(t.anim, t.time, t.Loop), t.Alpha)
+		}
+	}
+	if s.OnPose != nil {
+		s.OnPose()
+	}
+	s.deform()
+}
+
+// animTime maps playing time to a time within the animation.
+func animTime(a *SkeletonAnimation, t float64, loop bool) float64 {
+	if loop && a.Duration > 0 {
+		return math.Mod(t, a.Duration)
+	}
+	return min(t, a.Duration)
+}
+
+// apply mixes animation a at time t over the current pose by alpha.
+func (s *Skeleton) apply(a *SkeletonAnimation, t, alpha float64) {
+	for i := range a.bones {
+		tl := &a.bones[i]
+		bd := &s.data.Bones[tl.bone]
+		b := s.bones[tl.bone]
+		x, y := tl.sample(t)
+		switch tl.kind {
+		case boneRotate:
+			d := math.Remainder(bd.Rotation+x-b.Rotation, 2*math.Pi)
+			b.Rotation += d * alpha
+		case boneTranslate:
+			b.X += (bd.X + x - b.X) * alpha
+			b.Y += (bd.Y + y - b.Y) * alpha
+		case boneScale:
+			b.ScaleX += (bd.ScaleX*x - b.ScaleX) * alpha
+			b.ScaleY += (bd.ScaleY*y - b.ScaleY) * alpha
+		}
+	}
+	for i := range a.slots {
+		tl := &a.slots[i]
+		if len(tl.attachments) > 0 && alpha >= 0.5 {
+			k := tl.attachments
+			j := sort.Search(len(k), func(j int) bool { return k[j].time > t })
+			if j > 0 {
+				s.slots[tl.slot].next = s.resolveAttachment(tl.slot, k[j-1].name)
+			}
+		}
+		if len(tl.colors) > 0 {
+			c := sampleColor(tl.colors, t)
+			n := s.slots[tl.slot].node
+			n.Color.R += (c.R - n.Color.R) * alpha
+			n.Color.G += (c.G - n.Color.G) * alpha
+			n.Color.B += (c.B - n.Color.B) * alpha
+			n.Color.A += (c.A - n.Color.A) * alpha
+		}
+	}
+}
+
+// mixOut moves what from keys but to does not back toward the setup pose
+// by alpha, so a crossfade ends in exactly to's pose.
+func (s *Skeleton) mixOut(from, to *SkeletonAnimation, alpha float64) {
+	for i := range from.bones {
+		tl := &from.bones[i]
+		if to.keysBone(tl.bone, tl.kind) {
+			continue
+		}
+		bd := &s.data.Bones[tl.bone]
+		b := s.bones[tl.bone]
+		switch tl.kind {
+		case boneRotate:
+			b.Rotation += math.Remainder(bd.Rotation-b.Rotation, 2*math.Pi) * alpha
+		case boneTranslate:
+			b.X += (bd.X - b.X) * alpha
+			b.Y += (bd.Y - b.Y) * alpha
+		case boneScale:
+			b.ScaleX += (bd.ScaleX - b.ScaleX) * alpha
+			b.ScaleY += (bd.ScaleY - b.ScaleY) * alpha
+		}
+	}
+	for i := range from.slots {
+		tl := &from.slots[i]
+		if len(tl.colors) == 0 || to.keysSlotColor(tl.slot) {
+			continue
+		}
+		c := s.data.Slots[tl.slot].Color
+		n := s.slots[tl.slot].node
+		n.Color.R += (c.R - n.Color.R) * alpha
+		n.Color.G += (c.G - n.Color.G) * alpha
+		n.Color.B += (c.B - n.Color.B) * alpha
+		n.Color.A += (c.A - n.Color.A) * alpha
+	}
+}
+
+func (a *SkeletonAnimation) keysBone(bone int, kind boneTimelineKind) bool {
+	for i := range a.bones {
+		if a.bones[i].bone == bone && a.bones[i].kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *SkeletonAnimation) keysSlotColor(slot int) bool {
+	for i := range a.slots {
+		if a.slots[i].slot == slot && len(a.slots[i].colors) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func sampleColor(k []colorKey, t float64) Color {
+	i := sort.Search(len(k), func(i int) bool { return k[i].time > t })
+	if i == 0 {
+		return k[0].color
+	}
+	if i == len(k) {
+		return k[i-1].color
+	}
+	a, b := &k[i-1], &k[i]
+	p := a.curve.apply((t - a.time) / (b.time - a.time))
+	return Color{
+		R: a.color.R + (b.color.R-a.color.R)*p,
+		G: a.color.G + (b.color.G-a.color.G)*p,
+		B: a.color.B + (b.color.B-a.color.B)*p,
+		A: a.color.A + (b.color.A-a.color.A)*p,
+	}
+}
+
+// resolveAttachment looks up an attachment of slot i in the current skin
+// and then the default skin.
+func (s *Skeleton) resolveAttachment(i int, name string) *SkeletonAttachment {
+	if name == "" {
+		return nil
+	}
+	if att := s.skin.Attachment(i, name); att != nil {
+		return att
+	}
+	return s.data.Skins["default"].Attachment(i, name)
+}
+
+// setAttachment rebuilds slot i's mesh for att.
+func (s *Skeleton) setAttachment(i int, att *SkeletonAttachment) {
+	sl := &s.slots[i]
+	if sl.attachment == att {
+		return
+	}
+	sl.attachment = att
+	n := sl.node
+	if att == nil {
+		n.Vertices = n.Vertices[:0]
+		n.Indices = n.Indices[:0]
+		return
+	}
+	n.MeshImage = att.Image
+
+	// Texture coordinates only change with the attachment.
+	var uvs []Vec2
+	if att.Kind == AttachmentRegion {
+		uvs = regionQuadUVs[:]
+		n.Indices = append(n.Indices[:0], 0, 1, 2, 0, 2, 3)
+	} else {
+		uvs = att.UVs
+		n.Indices = append(n.Indices[:0], att.Triangles...)
+	}
+	if cap(n.Vertices) < len(uvs) {
+		n.Vertices = make([]ebiten.Vertex, len(uvs))
+	}
+	n.Vertices = n.Vertices[:len(uvs)]
+	r := att.Region
+	for j, uv := range uvs {
+		sx, sy := regionSrc(r, uv.X*float64(r.Width), uv.Y*float64(r.Height))
+		n.Vertices[j] = ebiten.Vertex{SrcX: sx, SrcY: sy, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1}
+	}
+}
+
+var regionQuadUVs = [4]Vec2{{0, 0}, {1, 0}, {1, 1}, {0, 1}}
+
+// regionSrc maps a point in a region's unrotated pixel space to page
+// coordinates. Rotated regions are stored 90 degrees clockwise.
+func regionSrc(r TextureRegion, px, py float64) (float32, float32) {
+	if r.Rotated {
+		return float32(float64(r.X) + float64(r.Height) - py), float32(float64(r.Y) + px)
+	}
+	return float32(float64(r.X) + px), float32(float64(r.Y) + py)
+}
+
+// deform computes the bone matrices and positions every attachment's
+// vertices in skeleton space.
+func (s *Skeleton) deform() {
+	for i, bd := range s.data.Bones {
+		local := computeLocalTransform(s.bones[i])
+		if bd.Parent < 0 {
+			s.world[i] = local
+		} else {
+			s.world[i] = multiplyAffine(s.world[bd.Parent], local)
+		}
+	}
+	for i := range s.slots {
+		s.setAttachment(i, s.slots[i].next)
+		sl := &s.slots[i]
+		att := sl.attachment
+		if att == nil {
+			continue
+		}
+		m := s.world[s.data.Slots[i].Bone]
+		v := sl.node.Vertices
+		switch {
+		case att.Kind == AttachmentRegion:
+			local := regionAttachmentTransform(att)
+			m = multiplyAffine(m, local)
+			r := att.Region
+			w, h := float64(r.OriginalW), float64(r.OriginalH)
+			if w == 0 || h == 0 {
+				w, h = float64(r.Width), float64(r.Height)
+			}
+			for j, uv := range regionQuadUVs {
+				// Trimmed regions cover only part of the original image.
+				px := (float64(r.OffsetX)+uv.X*float64(r.Width))/w - 0.5
+				py := (float64(r.OffsetY)+uv.Y*float64(r.Height))/h - 0.5
+				x, y := transformPoint(m, px*att.Width, py*att.Height)
+				v[j].DstX, v[j].DstY = float32(x), float32(y)
+			}
+		case att.Weights != nil:
+			for j, ws := range att.Weights {
+				var x, y float64
+				for _, w := range ws {
+					bm := &s.world[w.Bone]
+					x += (bm[0]*w.X + bm[2]*w.Y + bm[4]) * w.Weight
+					y += (bm[1]*w.X + bm[3]*w.Y + bm[5]) * w.Weight
+				}
+				v[j].DstX, v[j].DstY = float32(x), float32(y)
+			}
+		default:
+			for j, p := range att.Vertices {
+				x, y := transformPoint(m, p.X, p.Y)
+				v[j].DstX, v[j].DstY = float32(x), float32(y)
+			}
+		}
+		sl.node.InvalidateMeshAABB()
+		sl.node.Invalidate()
+	}
+}
+
+// regionAttachmentTransform returns the bone-space transform of a region
+// attachment's unit quad center.
+func regionAttachmentTransform(att *SkeletonAttachment) [6]float64 {
+	sin, cos := math.Sincos(att.Rotation)
+	sx, sy := att.ScaleX, att.ScaleY
+	return [6]float64{cos * sx, sin * sx, -sin * sy, cos * sy, att.X, att.Y}
+}
@@ -0,0 +1,401 @@
+package willow
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// LoadSkeleton parses skeleton JSON and resolves its attachments from the
+// atlas. The format follows Spine 3.x JSON, with y pointing down and angles
+// in degrees clockwise:
+//
+//	{
+//	  "bones": [{"name": "root"}, {"name": "arm", "parent": "root", "x": 10, "rotation": 45}],
+//	  "slots": [{"name": "arm", "bone": "arm", "attachment": "arm", "color": "ffffffff"}],
+//	  "skins": {"default": {"arm": {"arm": {"x": 20, "width": 40, "height": 10}}}},
+//	  "animations": {"wave": {
+//	    "bones": {"arm": {"rotate": [{"time": 0, "angle": 0}, {"time": 0.5, "angle": 30, "curve": [0.25, 0, 0.75, 1]}]}},
+//	    "slots": {"arm": {"attachment": [{"time": 0.5, "name": "fist"}]}},
+//	    "events": [{"time": 0.25, "name": "whoosh"}]
+//	  }}
+//	}
+//
+// Bones must be listed parents first. Attachments are "region" (the
+// default) or "mesh"; their "path" (defaulting to the attachment name) is
+// looked up in the atlas. Mesh "vertices" hold x, y pairs in the slot
+// bone's space, or, when weighted, a bone count followed by bone index,
+// x, y and weight for each influence. Keys may set "curve" to "stepped" or
+// to bezier control points [cx1, cy1, cx2, cy2].
+func LoadSkeleton(jsonData []byte, atlas *Atlas) (*SkeletonData, error) {
+	var doc skeletonJSON
+	if err := json.Unmarshal(jsonData, &doc); err != nil {
+		return nil, fmt.Errorf("willow: failed to parse skeleton JSON: %w", err)
+	}
+	d := &SkeletonData{
+		Skins:      make(map[string]*SkeletonSkin, len(doc.Skins)),
+		Animations: make(map[string]*SkeletonAnimation, len(doc.Animations)),
+	}
+
+	for _, jb := range doc.Bones {
+		b := BoneData{
+			Name:     jb.Name,
+			Parent:   -1,
+			X:        jb.X,
+			Y:        jb.Y,
+			Rotation: jb.Rotation * math.Pi / 180,
+			ScaleX:   floatOr(jb.ScaleX, 1),
+			ScaleY:   floatOr(jb.ScaleY, 1),
+			Length:   jb.Length,
+		}
+		if jb.Parent != "" {
+			b.Parent = d.FindBone(jb.Parent)
+			if b.Parent < 0 {
+				return nil, fmt.Errorf("willow: skeleton bone %q: parent %q not found before it", jb.Name, jb.Parent)
+			}
+		}
+		d.Bones = append(d.Bones, b)
+	}
+
+	for _, js := range doc.Slots {
+		bone := d.FindBone(js.Bone)
+		if bone < 0 {
+			return nil, fmt.Errorf("willow: skeleton slot %q: bone %q not found", js.Name, js.Bone)
+		}
+		color, err := parseSkeletonColor(js.Color)
+		if err != nil {
+			return nil, fmt.Errorf("willow: skeleton slot %q: %w", js.Name, err)
+		}
+		blend, err := parseSkeletonBlend(js.Blend)
+		if err != nil {
+			return nil, fmt.Errorf("willow: skeleton slot %q: %w", js.Name, err)
+		}
+		d.Slots = append(d.Slots, SlotData{Name: js.Name, Bone: bone, Color: color, Attachment: js.Attachment, BlendMode: blend})
+	}
+
+	for skinName, slots := range doc.Skins {
+		skin := &SkeletonSkin{Name: skinName, attachments: make(map[skinKey]*SkeletonAttachment)}
+		for slotName, atts := range slots {
+			slot := d.FindSlot(slotName)
+			if slot < 0 {
+				return nil, fmt.Errorf("willow: skeleton skin %q: slot %q not found", skinName, slotName)
+			}
+			for attName, ja := range atts {
+				att, err := ja.toAttachment(attName, atlas, len(d.Bones))
+				if err != nil {
+					return nil, fmt.Errorf("willow: skeleton skin %q attachment %q: %w", skinName, attName, err)
+				}
+				skin.attachments[skinKey{slot, attName}] = att
+			}
+		}
+		d.Skins[skinName] = skin
+	}
+
+	for animName, ja := range doc.Animations {
+		anim, err := ja.toAnimation(animName, d)
+		if err != nil {
+			return nil, fmt.Errorf("willow: skeleton animation %q: %w", animName, err)
+		}
+		d.Animations[animName] = anim
+	}
+	return d, nil
+}
+
+// --- JSON structure types ---
+
+type skeletonJSON struct {
+	Bones []struct {
+		Name     string   `json:"name"`
+		Parent   string   `json:"parent"`
+		X        float64  `json:"x"`
+		Y        float64  `json:"y"`
+		Rotation float64  `json:"rotation"`
+		ScaleX   *float64 `json:"scaleX"`
+		ScaleY   *float64 `json:"scaleY"`
+		Length   float64  `json:"length"`
+	} `json:"bones"`
+	Slots []struct {
+		Name       string `json:"name"`
+		Bone       string `json:"bone"`
+		Color      string `json:"color"`
+		Attachment string `json:"attachment"`
+		Blend      string `json:"blend"`
+	} `json:"slots"`
+	Skins      map[string]map[string]map[string]skeletonAttachmentJSON `json:"skins"`
+	Animations map[string]skeletonAnimationJSON                        `json:"animations"`
+}
+
+type skeletonAttachmentJSON struct {
+	Type      string    `json:"type"`
+	Path      string    `json:"path"`
+	X         float64   `json:"x"`
+	Y         float64   `json:"y"`
+	Rotation  float64   `json:"rotation"`
+	ScaleX    *float64  `json:"scaleX"`
+	ScaleY    *float64  `json:"scaleY"`
+	Width     float64   `json:"width"`
+	Height    float64   `json:"height"`
+	UVs       []float64 `json:"uvs"`
+	Triangles []uint16  `json:"triangles"`
+	Vertices  []float64 `json:"vertices"`
+}
+
+type skeletonKeyJSON struct {
+	Time  float64         `json:"time"`
+	Angle float64         `json:"angle"`
+	X     *float64        `json:"x"`
+	Y     *float64        `json:"y"`
+	Name  *string         `json:"name"`
+	Color string          `json:"color"`
+	Curve json.RawMessage `json:"curve"`
+}
+
+type skeletonAnimationJSON struct {
+	Bones map[string]struct {
+		Rotate    []skeletonKeyJSON `json:"rotate"`
+		Translate []skeletonKeyJSON `json:"translate"`
+		Scale     []skeletonKeyJSON `json:"scale"`
+	} `json:"bones"`
+	Slots map[string]struct {
+		Attachment []skeletonKeyJSON `json:"attachment"`
+		Color      []skeletonKeyJSON `json:"color"`
+	} `json:"slots"`
+	Events []struct {
+		Time float64 `json:"time"`
+		Name string  `json:"name"`
+	} `json:"events"`
+}
+
+func (ja *skeletonAttachmentJSON) toAttachment(name string, atlas *Atlas, numBones int) (*SkeletonAttachment, error) {
+	path := ja.Path
+	if path == "" {
+		path = name
+	}
+	att := &SkeletonAttachment{
+		Name:     name,
+		X:        ja.X,
+		Y:        ja.Y,
+		Rotation: ja.Rotation * math.Pi / 180,
+		ScaleX:   floatOr(ja.ScaleX, 1),
+		ScaleY:   floatOr(ja.ScaleY, 1),
+		Width:    ja.Width,
+		Height:   ja.Height,
+	}
+	if atlas != nil {
+		att.Region = atlas.Region(path)
+		att.Image = regionPage(atlas, att.Region)
+	}
+	if att.Width == 0 {
+		att.Width = float64(max(att.Region.OriginalW, att.Region.Width))
+	}
+	if att.Height == 0 {
+		att.Height = float64(max(att.Region.OriginalH, att.Region.Height))
+	}
+
+	switch ja.Type {
+	case "", "region":
+		att.Kind = AttachmentRegion
+		return att, nil
+	case "mesh":
+	default:
+		return nil, fmt.Errorf("unknown type %q", ja.Type)
+	}
+
+	att.Kind = AttachmentMesh
+	if len(ja.UVs)%2 != 0 {
+		return nil, fmt.Errorf("odd number of uvs")
+	}
+	n := len(ja.UVs) / 2
+	att.UVs = make([]Vec2, n)
+	for i := range att.UVs {
+		att.UVs[i] = Vec2{X: ja.UVs[i*2], Y: ja.UVs[i*2+1]}
+	}
+	for _, idx := range ja.Triangles {
+		if int(idx) >= n {
+			return nil, fmt.Errorf("triangle index %d out of range", idx)
+		}
+	}
+	att.Triangles = ja.Triangles
+
+	if len(ja.Vertices) == n*2 {
+		att.Vertices = make([]Vec2, n)
+		for i := range att.Vertices {
+			att.Vertices[i] = Vec2{X: ja.Vertices[i*2], Y: ja.Vertices[i*2+1]}
+		}
+		return att, nil
+	}
+	att.Weights = make([][]VertexWeight, n)
+	v := ja.Vertices
+	for i := 0; i < n; i++ {
+		if len(v) == 0 {
+			return nil, fmt.Errorf("weighted vertices end early")
+		}
+		if c := v[0]; c < 1 || c != math.Trunc(c) {
+			return nil, fmt.Errorf("invalid vertex bone count %v", c)
+		}
+		if v[0] > float64((len(v)-1)/4) {
+			return nil, fmt.Errorf("weighted vertices end early")
+		}
+		count := int(v[0])
+		v = v[1:]
+		ws := make([]VertexWeight, count)
+		for j := range ws {
+			b := int(v[j*4])
+			if b < 0 || b >= numBones {
+				return nil, fmt.Errorf("weight bone %d out of range", b)
+			}
+			ws[j] = VertexWeight{Bone: b, X: v[j*4+1], Y: v[j*4+2], Weight: v[j*4+3]}
+		}
+		att.Weights[i] = ws
+		v = v[count*4:]
+	}
+	return att, nil
+}
+
+func (ja *skeletonAnimationJSON) toAnimation(name string, d *SkeletonData) (*SkeletonAnimation, error) {
+	a := &SkeletonAnimation{Name: name}
+	for boneName, tls := range ja.Bones {
+		bone := d.FindBone(boneName)
+		if bone < 0 {
+			return nil, fmt.Errorf("bone %q not found", boneName)
+		}
+		for _, t := range []struct {
+			kind boneTimelineKind
+			keys []skeletonKeyJSON
+			def  float64
+		}{{boneRotate, tls.Rotate, 0}, {boneTranslate, tls.Translate, 0}, {boneScale, tls.Scale, 1}} {
+			if len(t.keys) == 0 {
+				continue
+			}
+			tl := boneTimeline{bone: bone, kind: t.kind, keys: make([]boneKey, len(t.keys))}
+			for i, jk := range t.keys {
+				c, err := parseSkeletonCurve(jk.Curve)
+				if err != nil {
+					return nil, fmt.Errorf("bone %q: %w", boneName, err)
+				}
+				k := boneKey{time: jk.Time, x: floatOr(jk.X, t.def), y: floatOr(jk.Y, t.def), curve: c}
+				if t.kind == boneRotate {
+					k.x = jk.Angle * math.Pi / 180
+				}
+				tl.keys[i] = k
+				a.Duration = max(a.Duration, jk.Time)
+			}
+			// Sampling binary-searches keys by time.
+			sort.SliceStable(tl.keys, func(i, j int) bool { return tl.keys[i].time < tl.keys[j].time })
+			a.bones = append(a.bones, tl)
+		}
+	}
+	for slotName, tls := range ja.Slots {
+		slot := d.FindSlot(slotName)
+		if slot < 0 {
+			return nil, fmt.Errorf("slot %q not found", slotName)
+		}
+		tl := slotTimeline{slot: slot}
+		for _, jk := range tls.Attachment {
+			var att string
+			if jk.Name != nil {
+				att = *jk.Name
+			}
+			tl.attachments = append(tl.attachments, attachmentKey{time: jk.Time, name: att})
+			a.Duration = max(a.Duration, jk.Time)
+		}
+		for _, jk := range tls.Color {
+			c, err := parseSkeletonCurve(jk.Curve)
+			if err != nil {
+				return nil, fmt.Errorf("slot %q: %w", slotName, err)
+			}
+			color, err := parseSkeletonColor(jk.Color)
+			if err != nil {
+				return nil, fmt.Errorf("slot %q: %w", slotName, err)
+			}
+			tl.colors = append(tl.colors, colorKey{time: jk.Time, color: color, curve: c})
+			a.Duration = max(a.Duration, jk.Time)
+		}
+		sort.SliceStable(tl.attachments, func(i, j int) bool { return tl.attachments[i].time < tl.attachments[j].time })
+		sort.SliceStable(tl.colors, func(i, j int) bool { return tl.colors[i].time < tl.colors[j].time })
+		a.slots = append(a.slots, tl)
+	}
+	for _, e := range ja.Events {
+		a.events = append(a.events, skeletonEvent{time: e.Time, name: e.Name})
+		a.Duration = max(a.Duration, e.Time)
+	}
+	sort.SliceStable(a.events, func(i, j int) bool { return a.events[i].time < a.events[j].time })
+	return a, nil
+}
+
+// regionPage returns the page image holding r.
+func regionPage(atlas *Atlas, r TextureRegion) *ebiten.Image {
+	if r.Page == magentaPlaceholderPage {
+		return ensureMagentaImage()
+	}
+	if int(r.Page) < len(atlas.Pages) {
+		return atlas.Pages[r.Page]
+	}
+	return nil
+}
+
+func floatOr(v *float64, def float64) float64 {
+	if v == nil {
+		return def
+	}
+	return *v
+}
+
+// parseSkeletonColor parses "rrggbbaa" or "rrggbb"; "" is opaque white.
+func parseSkeletonColor(s string) (Color, error) {
+	if s == "" {
+		return Color{R: 1, G: 1, B: 1, A: 1}, nil
+	}
+	if len(s) == 6 {
+		s += "ff"
+	}
+	v, err := strconv.ParseUint(s, 16, 32)
+	if len(s) != 8 || err != nil {
+		return Color{}, fmt.Errorf("invalid color %q", s)
+	}
+	return Color{
+		R: float64(v>>24&0xff) / 255,
+		G: float64(v>>16&0xff) / 255,
+		B: float64(v>>8&0xff) / 255,
+		A: float64(v&0xff) / 255,
+	}, nil
+}
+
+func parseSkeletonBlend(s string) (BlendMode, error) {
+	switch s {
+	case "", "normal":
+		return BlendNormal, nil
+	case "additive":
+		return BlendAdd, nil
+	case "multiply":
+		return BlendMultiply, nil
+	case "screen":
+		return BlendScreen, nil
+	}
+	return BlendNormal, fmt.Errorf("unknown blend %q", s)
+}
+
+func parseSkeletonCurve(raw json.RawMessage) (curve, error) {
+	if len(raw) == 0 {
+		return curve{}, nil
+	}
+	var name string
+	if json.Unmarshal(raw, &name) == nil {
+		switch name {
+		case "linear":
+			return curve{}, nil
+		case "stepped":
+			return curve{stepped: true}, nil
+		}
+		return curve{}, fmt.Errorf("unknown curve %q", name)
+	}
+	var c [4]float64
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return curve{}, fmt.Errorf("invalid curve %s", raw)
+	}
+	return curve{bezier: true, c: c}, nil
+}
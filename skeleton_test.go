@@ -0,0 +1,258 @@
+package willow
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const testSkeletonJSON = `{
+  "bones": [
+    {"name": "root"},
+    {"name": "arm", "parent": "root", "x": 10, "length": 20},
+    {"name": "hand", "parent": "arm", "x": 20}
+  ],
+  "slots": [
+    {"name": "arm", "bone": "arm", "attachment": "arm"},
+    {"name": "skin", "bone": "root", "attachment": "skin", "color": "ff000080", "blend": "additive"}
+  ],
+  "skins": {
+    "default": {
+      "arm": {
+        "arm": {"x": 10, "width": 20, "height": 4},
+        "fist": {"path": "arm", "width": 8, "height": 8}
+      },
+      "skin": {
+        "skin": {"type": "mesh", "path": "arm",
+          "uvs": [0, 0, 1, 0, 1, 1],
+          "triangles": [0, 1, 2],
+          "vertices": [1, 1, 0, 0, 1,   1, 2, 0, 0, 1,   2, 1, 0, 0, 0.5, 2, 0, 0, 0.5]}
+      }
+    },
+    "gold": {
+      "arm": {"arm": {"x": 10, "width": 40, "height": 4}}
+    }
+  },
+  "animations": {
+    "raise": {
+      "bones": {"arm": {"rotate": [{"time": 0, "angle": 0}, {"time": 1, "angle": 90}]}},
+      "events": [{"time": 0.5, "name": "half"}]
+    },
+    "reach": {
+      "bones": {"arm": {"translate": [{"time": 0, "x": 0, "y": 0}, {"time": 1, "x": 10, "y": 0, "curve": "stepped"}, {"time": 2, "x": 20, "y": 0}]}},
+      "slots": {"arm": {"attachment": [{"time": 1, "name": "fist"}], "color": [{"time": 0, "color": "ffffffff"}, {"time": 2, "color": "00000000"}]}}
+    }
+  }
+}`
+
+func loadTestSkeleton(t *testing.T) *SkeletonData {
+	t.Helper()
+	atlas := &Atlas{
+		Pages:   []*ebiten.Image{ebiten.NewImage(64, 64)},
+		regions: map[string]TextureRegion{"arm": {X: 8, Y: 16, Width: 20, Height: 4, OriginalW: 20, OriginalH: 4}},
+	}
+	d, err := LoadSkeleton([]byte(testSkeletonJSON), atlas)
+	if err != nil {
+		t.Fatalf("LoadSkeleton: %v", err)
+	}
+	return d
+}
+
+func vertNear(v ebiten.Vertex, x, y float64) bool {
+	return math.Abs(float64(v.DstX)-x) < 1e-3 && math.Abs(float64(v.DstY)-y) < 1e-3
+}
+
+func TestLoadSkeleton(t *testing.T) {
+	d := loadTestSkeleton(t)
+	if len(d.Bones) != 3 || d.Bones[2].Parent != 1 || d.Bones[1].Length != 20 {
+		t.Errorf("bones = %+v", d.Bones)
+	}
+	sl := d.Slots[1]
+	if sl.BlendMode != BlendAdd || sl.Color.R != 1 || math.Abs(sl.Color.A-128.0/255) > 1e-9 {
+		t.Errorf("slot = %+v", sl)
+	}
+	mesh := d.Skins["default"].Attachment(1, "skin")
+	if mesh == nil || mesh.Kind != AttachmentMesh || len(mesh.Weights) != 3 || len(mesh.Weights[2]) != 2 {
+		t.Fatalf("mesh attachment = %+v", mesh)
+	}
+	if a := d.Animations["reach"]; a == nil || a.Duration != 2 {
+		t.Errorf("reach animation = %+v", a)
+	}
+
+	for _, bad := range []string{
+		`{"bones": [{"name": "a", "parent": "b"}, {"name": "b"}]}`,
+		`{"bones": [{"name": "a"}], "slots": [{"name": "s", "bone": "x"}]}`,
+		`{"bones": [{"name": "a"}], "animations": {"x": {"bones": {"nope": {}}}}}`,
+		`{"bones": [{"name": "a"}], "slots": [{"name": "s", "bone": "a"}], "skins": {"default": {"s": {"m": {"type": "mesh", "uvs": [0, 0], "triangles": [3]}}}}}`,
+		`{"bones": [{"name": "a"}], "slots": [{"name": "s", "bone": "a"}], "skins": {"default": {"s": {"m": {"type": "mesh", "uvs": [0, 0], "vertices": [0, 1, 2, 3, 4]}}}}}`,
+		`{"bones": [{"name": "a"}], "slots": [{"name": "s", "bone": "a"}], "skins": {"default": {"s": {"m": {"type": "mesh", "uvs": [0, 0], "vertices": [-1, 0, 0, 0, 1]}}}}}`,
+		`{"bones": [{"name": "a"}], "slots": [{"name": "s", "bone": "a"}], "skins": {"default": {"s": {"m": {"type": "mesh", "uvs": [0, 0], "vertices": [2, 0, 0, 0, 1]}}}}}`,
+	} {
+		if _, err := LoadSkeleton([]byte(bad), nil); err == nil || !strings.HasPrefix(err.Error(), "willow: ") {
+			t.Errorf("LoadSkeleton(%s) error = %v", bad, err)
+		}
+	}
+}
+
+func TestSkeletonSetupPose(t *testing.T) {
+	sk := NewSkeleton("hero", loadTestSkeleton(t))
+	arm := sk.Slot("arm")
+	// Region centered 10px along the arm bone at x=10: spans x 10..30.
+	if len(arm.Vertices) != 4 || !vertNear(arm.Vertices[0], 10, -2) || !vertNear(arm.Vertices[2], 30, 2) {
+		t.Errorf("arm quad = %+v", arm.Vertices)
+	}
+	if arm.Vertices[0].SrcX != 8 || arm.Vertices[2].SrcY != 20 {
+		t.Errorf("arm uvs = %v,%v", arm.Vertices[0].SrcX, arm.Vertices[2].SrcY)
+	}
+	if sk.Bone("hand").X != 20 || sk.Bone("hand").Parent != sk.Bone("arm") {
+		t.Error("bone nodes not built from setup pose")
+	}
+	if sk.Slot("skin").BlendMode != BlendAdd {
+		t.Error("slot blend mode not applied")
+	}
+
+	sk.SetSkin("gold")
+	if !vertNear(arm.Vertices[2], 40, 2) {
+		t.Errorf("gold skin quad = %+v", arm.Vertices[2])
+	}
+}
+
+func TestSkeletonAnimationAndWeights(t *testing.T) {
+	sk := NewSkeleton("hero", loadTestSkeleton(t))
+	var events []string
+	sk.OnEvent = func(anim, ev string) { events = append(events, anim+":"+ev) }
+	completed := ""
+	sk.OnComplete = func(anim string) { completed = anim }
+	sk.SetAnimation(0, "raise", false)
+
+	sk.update(0.5)
+	if r := sk.Bone("arm").Rotation; math.Abs(r-math.Pi/4) > 1e-9 {
+		t.Errorf("arm rotation = %f, want pi/4", r)
+	}
+	if len(events) != 1 || events[0] != "raise:half" {
+		t.Errorf("events = %v", events)
+	}
+	sk.update(0.5)
+	// Arm points straight down; the region now spans y 0..20 below x=10.
+	arm := sk.Slot("arm")
+	if !vertNear(arm.Vertices[0], 12, 0) || !vertNear(arm.Vertices[2], 8, 20) {
+		t.Errorf("rotated quad = %+v %+v", arm.Vertices[0], arm.Vertices[2])
+	}
+	// Weighted vertex 2 is split between arm (10,0) and hand (10,20).
+	skin := sk.Slot("skin")
+	if !vertNear(skin.Vertices[0], 10, 0) || !vertNear(skin.Vertices[1], 10, 20) || !vertNear(skin.Vertices[2], 10, 10) {
+		t.Errorf("weighted mesh = %+v", skin.Vertices)
+	}
+	if completed != "raise" {
+		t.Errorf("OnComplete = %q", completed)
+	}
+}
+
+func TestSkeletonKeysCurvesAndSlots(t *testing.T) {
+	sk := NewSkeleton("hero", loadTestSkeleton(t))
+	sk.SetAnimation(0, "reach", true)
+	arm := sk.Bone("arm")
+
+	sk.update(0.5)
+	if arm.X != 15 || sk.Slot("arm").Color.R != 0.75 {
+		t.Errorf("at 0.5: X=%f R=%f, want 15, 0.75", arm.X, sk.Slot("arm").Color.R)
+	}
+	sk.update(1)
+	// Stepped from t=1: holds the t=1 key; the attachment switched to fist.
+	if arm.X != 20 || len(sk.Slot("arm").Vertices) != 4 || !vertNear(sk.Slot("arm").Vertices[0], 16, -4) {
+		t.Errorf("at 1.5: X=%f verts=%+v", arm.X, sk.Slot("arm").Vertices)
+	}
+	// Looping back to the start restores the setup attachment.
+	sk.update(0.75)
+	if arm.X != 12.5 || !vertNear(sk.Slot("arm").Vertices[0], 12.5, -2) {
+		t.Errorf("after loop: X=%f verts=%+v", arm.X, sk.Slot("arm").Vertices[0])
+	}
+	sk.ClearTrack(0)
+	sk.SetAttachment("arm", "")
+	if len(sk.Slot("arm").Vertices) != 0 {
+		t.Error("empty attachment should hide the slot")
+	}
+}
+
+func TestSkeletonCrossFadeAndLayers(t *testing.T) {
+	sk := NewSkeleton("hero", loadTestSkeleton(t))
+	arm := sk.Bone("arm")
+	sk.SetAnimation(0, "raise", false)
+	sk.update(0.5) // 45 degrees
+
+	sk.CrossFade(0, "reach", 1, true)
+	sk.update(0.5)
+	// raise at 1.0 (90°) mixed half-way toward reach, which leaves the
+	// rotation at setup (0); reach's translate is mixed in half-way too.
+	if math.Abs(arm.Rotation-math.Pi/4) > 1e-9 || arm.X != 12.5 {
+		t.Errorf("mid-fade: rotation=%f X=%f", arm.Rotation, arm.X)
+	}
+	sk.update(0.5)
+	if sk.Track(0).from != nil || arm.Rotation != 0 {
+		t.Errorf("fade should be finished: rotation=%f", arm.Rotation)
+	}
+
+	// A half-weight layer on track 1.
+	tr := sk.SetAnimation(1, "raise", false)
+	tr.Alpha = 0.5
+	sk.update(1)
+	if math.Abs(arm.Rotation-math.Pi/4) > 1e-9 {
+		t.Errorf("layered rotation = %f, want pi/4", arm.Rotation)
+	}
+	if sk.SetAnimation(0, "missing", false) != nil {
+		t.Error("unknown animation should return nil")
+	}
+}
+
+func TestSkeletonCurveBezier(t *testing.T) {
+	c := curve{bezier: true, c: [4]float64{0.25, 0.25, 0.75, 0.75}}
+	if v := c.apply(0.5); math.Abs(v-0.5) > 1e-3 {
+		t.Errorf("linear-ish bezier(0.5) = %f", v)
+	}
+	ease := curve{bezier: true, c: [4]float64{0.5, 0, 0.5, 1}}
+	if v := ease.apply(0.25); v >= 0.25 {
+		t.Errorf("ease-in-out bezier(0.25) = %f, want < 0.25", v)
+	}
+}
+
+func TestSkeletonUnsortedKeys(t *testing.T) {
+	d, err := LoadSkeleton([]byte(`{
+  "bones": [{"name": "root"}],
+  "slots": [{"name": "s", "bone": "root"}],
+  "animations": {"a": {
+    "bones": {"root": {"translate": [{"time": 2, "x": 20}, {"time": 0, "x": 0}, {"time": 1, "x": 10}]}},
+    "slots": {"s": {"color": [{"time": 1, "color": "00000000"}, {"time": 0, "color": "ffffffff"}]}},
+    "events": [{"time": 1, "name": "late"}, {"time": 0.5, "name": "early"}]
+  }}
+}`), nil)
+	if err != nil {
+		t.Fatalf("LoadSkeleton: %v", err)
+	}
+	sk := NewSkeleton("s", d)
+	var events []string
+	sk.OnEvent = func(_, ev string) { events = append(events, ev) }
+	sk.SetAnimation(0, "a", false)
+	sk.update(1.5)
+	if x := sk.Bone("root").X; x != 15 {
+		t.Errorf("X = %f, want 15", x)
+	}
+	if c := sk.Slot("s").Color; c.R != 0 {
+		t.Errorf("color = %+v, want the t=1 key", c)
+	}
+	if len(events) != 2 || events[0] != "early" || events[1] != "late" {
+		t.Errorf("events = %v, want [early late]", events)
+	}
+}
+
+func TestSkeletonKeepsPlayingWithOnUpdate(t *testing.T) {
+	sk := NewSkeleton("hero", loadTestSkeleton(t))
+	var ticks int
+	sk.Node().OnUpdate = func(float64) { ticks++ }
+	sk.SetAnimation(0, "raise", false)
+	updateNodesAndParticles(sk.Node(), 0.5)
+	if r := sk.Bone("arm").Rotation; ticks != 1 || math.Abs(r-math.Pi/4) > 1e-9 {
+		t.Errorf("ticks = %d, arm rotation = %f; want 1, pi/4", ticks, r)
+	}
+}
@@ -0,0 +1,1455 @@
+package willow
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"image"
+	_ "image/png" // Tiled tilesets are almost always PNG
+	"io"
+	"io/fs"
+	"math"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// TiledLayerType identifies the kind of a TiledLayer.
+type TiledLayerType uint8
+
+const (
+	// TiledLayerTile is a grid of tile GIDs.
+	TiledLayerTile TiledLayerType = iota
+	// TiledLayerObjects is an object group.
+	TiledLayerObjects
+	// TiledLayerImage is a single image.
+	TiledLayerImage
+	// TiledLayerGroup contains nested layers.
+	TiledLayerGroup
+)
+
+// TiledMap is a map loaded from a Tiled .tmx or .tmj file. The fields mirror
+// the Tiled format; NewViewport turns the map into willow nodes.
+type TiledMap struct {
+	Orientation   string // "orthogonal", "isometric", "staggered" or "hexagonal"
+	RenderOrder   string
+	Width         int // map width in tiles
+	Height        int // map height in tiles
+	TileWidth     int
+	TileHeight    int
+	Infinite      bool
+	StaggerAxis   string // "x" or "y" (staggered and hexagonal maps)
+	StaggerIndex  string // "odd" or "even" (staggered and hexagonal maps)
+	HexSideLength int
+
+	Tilesets   []*TiledTileset // sorted by FirstGID
+	Layers     []*TiledLayer   // top-level layers in draw order
+	Properties map[string]string
+}
+
+// TiledTileset is a tileset referenced by a TiledMap, either embedded or
+// loaded from an external .tsx/.tsj file.
+type TiledTileset struct {
+	FirstGID   uint32
+	Name       string
+	TileWidth  int
+	TileHeight int
+	Spacing    int
+	Margin     int
+	TileCount  int
+	Columns    int
+
+	// ImagePath is the tileset image path within the map's file system.
+	// Image is the decoded page (nil for image-collection tilesets).
+	ImagePath   string
+	ImageWidth  int
+	ImageHeight int
+	Image       *ebiten.Image
+
+	// Tiles holds per-tile metadata keyed by local tile ID. Only tiles that
+	// carry properties, animations or collision shapes are present.
+	Tiles      map[uint32]*TiledTile
+	Properties map[string]string
+
+	// WangSets holds the tileset's terrain sets, for autotiling.
+	WangSets []TiledWangSet
+}
+
+// TiledWangSet is a Tiled terrain (Wang) set.
+type TiledWangSet struct {
+	Name   string
+	Type   string   // "corner", "edge" or "mixed"
+	Colors []string // terrain names; color IDs start at 1
+	Tiles  []TiledWangTile
+}
+
+// TiledWangTile assigns terrain colors to the edges and corners of a tile.
+type TiledWangTile struct {
+	ID uint32 // local tile ID
+	// WangID holds a color ID (0 = none) per position: top, top-right,
+	// right, bottom-right, bottom, bottom-left, left, top-left.
+	WangID [8]uint8
+}
+
+// TiledTile is per-tile metadata from a tileset.
+type TiledTile struct {
+	ID         uint32 // local tile ID
+	Type       string
+	Properties map[string]string
+	// Animation uses global GIDs and is ready for TileMapLayer.SetAnimations.
+	Animation []AnimFrame
+	// Objects holds the tile's collision shapes, in tile-local pixels.
+	Objects []TiledObject
+}
+
+// TiledLayer is one layer of a TiledMap.
+type TiledLayer struct {
+	ID      int
+	Name    string
+	Type    TiledLayerType
+	Visible bool
+	Opacity float64
+	OffsetX float64
+	OffsetY float64
+
+	// Tile layers: row-major GIDs with Tiled flip bits, len = Width*Height.
+	// Infinite maps store tiles in Chunks instead.
+	Width  int
+	Height int
+	Data   []uint32
+	Chunks []TiledChunk
+
+	// Object groups.
+	Objects []TiledObject
+
+	// Image layers.
+	ImagePath string
+	Image     *ebiten.Image
+
+	// Group layers.
+	Layers []*TiledLayer
+
+	Properties map[string]string
+}
+
+// TiledChunk is a rectangular block of tile data in an infinite map.
+type TiledChunk struct {
+	X, Y          int // position in tiles
+	Width, Height int
+	Data          []uint32
+}
+
+// TiledObject is an object from an object group or a tile's collision shapes.
+type TiledObject struct {
+	ID       int
+	Name     string
+	Type     string // class in Tiled 1.9+
+	X, Y     float64
+	Width    float64
+	Height   float64
+	Rotation float64 // degrees clockwise, as stored by Tiled
+	GID      uint32  // non-zero for tile objects (may carry flip bits)
+	Visible  bool
+
+	Point    bool
+	Ellipse  bool
+	Polygon  []Vec2 // points relative to X, Y
+	Polyline []Vec2 // points relative to X, Y
+	Text     string
+
+	Properties map[string]string
+}
+
+// LoadTiledMap reads a Tiled map from fsys. Files ending in .tmx are parsed
+// as XML and anything else as JSON (.tmj). External tilesets and images are
+// resolved relative to the file that references them. Tile data may use
+// CSV, base64, zlib or gzip encoding.
+func LoadTiledMap(fsys fs.FS, name string) (*TiledMap, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("willow: failed to read tiled map: %w", err)
+	}
+	l := &tiledLoader{fsys: fsys, images: make(map[string]*ebiten.Image)}
+	dir := path.Dir(name)
+	if strings.EqualFold(path.Ext(name), ".tmx") {
+		return l.parseTMX(data, dir)
+	}
+	return l.parseTMJ(data, dir)
+}
+
+// TilesetForGID returns the tileset that owns gid (flip bits are ignored),
+// or nil if no tileset's GID range covers it.
+func (m *TiledMap) TilesetForGID(gid uint32) *TiledTileset {
+	if i := m.tilesetIndex(gid); i >= 0 {
+		return m.Tilesets[i]
+	}
+	return nil
+}
+
+func (m *TiledMap) tilesetIndex(gid uint32) int {
+	gid &^= tileFlagMask
+	if gid == 0 {
+		return -1
+	}
+	found := -1
+	for i, ts := range m.Tilesets {
+		if ts.FirstGID > gid {
+			break
+		}
+		found = i
+	}
+	if found >= 0 && gid-m.Tilesets[found].FirstGID >= uint32(m.Tilesets[found].TileCount) {
+		return -1 // past the end of the last tileset that could own it
+	}
+	return found
+}
+
+// Layer returns the first layer with the given name, searching group layers
+// depth-first. Returns nil if there is none.
+func (m *TiledMap) Layer(name string) *TiledLayer {
+	return findTiledLayer(m.Layers, name)
+}
+
+func findTiledLayer(layers []*TiledLayer, name string) *TiledLayer {
+	for _, l := range layers {
+		if l.Name == name {
+			return l
+		}
+		if found := findTiledLayer(l.Layers, name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// Animations returns the tile animations of every tileset keyed by base GID,
+// in the form TileMapLayer.SetAnimations expects. Returns nil if the map has
+// no animated tiles.
+func (m *TiledMap) Animations() map[uint32][]AnimFrame {
+	var anims map[uint32][]AnimFrame
+	for _, ts := range m.Tilesets {
+		for id, t := range ts.Tiles {
+			if len(t.Animation) == 0 {
+				continue
+			}
+			if anims == nil {
+				anims = make(map[uint32][]AnimFrame)
+			}
+			anims[ts.FirstGID+id] = t.Animation
+		}
+	}
+	return anims
+}
+
+// Collisions returns the collision shapes of every tile with collision
+// objects, keyed by global GID and ready for TileMapLayer.SetCollisions.
+// A rectangle covering the whole tile is solid, and a polygon over the
+// tile's bottom edge whose other points lie on its side edges is a slope.
+// Other rectangles and polygons become TileCollisionPolygon (the first
+// shape only). Tiles whose type is "oneway" or with a true "oneway"
+// property are one-way platforms.
+func (m *TiledMap) Collisions() map[uint32]TileCollision {
+	var shapes map[uint32]TileCollision
+	for _, ts := range m.Tilesets {
+		tw, th := float64(ts.TileWidth), float64(ts.TileHeight)
+		for id, t := range ts.Tiles {
+			if len(t.Objects) == 0 {
+				continue
+			}
+			var c TileCollision
+			if t.Type == "oneway" || t.Properties["oneway"] == "true" {
+				c.Kind = TileCollisionOneWay
+			} else if c = tiledCollision(&t.Objects[0], tw, th); c.Kind == TileCollisionNone {
+				continue
+			}
+			if shapes == nil {
+				shapes = make(map[uint32]TileCollision)
+			}
+			shapes[ts.FirstGID+id] = c
+		}
+	}
+	return shapes
+}
+
+// tiledCollision converts a tile collision object in a tw×th tile.
+func tiledCollision(o *TiledObject, tw, th float64) TileCollision {
+	const eps = 0.5
+	near := func(a, b float64) bool { return math.Abs(a-b) < eps }
+	switch {
+	case o.Point || o.Ellipse || len(o.Polyline) > 0:
+		return TileCollision{}
+	case len(o.Polygon) > 0:
+		pts := make([]Vec2, len(o.Polygon))
+		for i, p := range o.Polygon {
+			pts[i] = Vec2{X: o.X + p.X, Y: o.Y + p.Y}
+		}
+		if c, ok := tiledSlope(pts, tw, th, near); ok {
+			return c
+		}
+		return TileCollision{Kind: TileCollisionPolygon, Polygon: pts}
+	case near(o.X, 0) && near(o.Y, 0) && near(o.Width, tw) && near(o.Height, th):
+		return TileCollision{Kind: TileCollisionSolid}
+	case o.Width > 0 && o.Height > 0:
+		return TileCollision{Kind: TileCollisionPolygon, Polygon: []Vec2{
+			{X: o.X, Y: o.Y}, {X: o.X + o.Width, Y: o.Y},
+			{X: o.X + o.Width, Y: o.Y + o.Height}, {X: o.X, Y: o.Y + o.Height},
+		}}
+	}
+	return TileCollision{}
+}
+
+// tiledSlope reports whether pts is a slope: both bottom corners plus at
+// most one point on each side edge.
+func tiledSlope(pts []Vec2, tw, th float64, near func(a, b float64) bool) (TileCollision, bool) {
+	if len(pts) != 3 && len(pts) != 4 {
+		return TileCollision{}, false
+	}
+	var bl, br bool
+	left, right := -1.0, -1.0
+	for _, p := range pts {
+		switch {
+		case near(p.Y, th) && near(p.X, 0) && !bl:
+			bl = true
+		case near(p.Y, th) && near(p.X, tw) && !br:
+			br = true
+		case near(p.X, 0) && left < 0:
+			left = th - p.Y
+		case near(p.X, tw) && right < 0:
+			right = th - p.Y
+		default:
+			return TileCollision{}, false
+		}
+	}
+	if !bl || !br {
+		return TileCollision{}, false
+	}
+	return TileCollision{Kind: TileCollisionSlope, SlopeLeft: max(left, 0), SlopeRight: max(right, 0)}, true
+}
+
+// AutotileRules converts the named Wang set to autotile rules with global
+// GIDs, using color IDs as terrain IDs. Corner sets map to AutotileCorner,
+// edge sets to AutotileEdge and mixed sets to AutotileBlob. Each color is
+// matched on its own, so a transition tile is registered under every color
+// it contains, with the positions holding that color as its mask; the
+// first tile wins when masks repeat. Returns nil if no set has that name.
+func (ts *TiledTileset) AutotileRules(name string) *AutotileRules {
+	for i := range ts.WangSets {
+		ws := &ts.WangSets[i]
+		if ws.Name != name {
+			continue
+		}
+		r := &AutotileRules{Terrains: make(map[int]map[uint8]uint32)}
+		switch ws.Type {
+		case "corner":
+			r.Mode = AutotileCorner
+		case "edge":
+			r.Mode = AutotileEdge
+		default:
+			r.Mode = AutotileBlob
+		}
+		for _, wt := range ws.Tiles {
+			for _, color := range wt.WangID {
+				if color == 0 {
+					continue
+				}
+				var mask uint8
+				for p, c := range wt.WangID {
+					if c == color {
+						mask |= 1 << p
+					}
+				}
+				tiles := r.Terrains[int(color)]
+				if tiles == nil {
+					tiles = make(map[uint8]uint32)
+					r.Terrains[int(color)] = tiles
+				}
+				if _, ok := tiles[mask]; !ok {
+					tiles[mask] = ts.FirstGID + wt.ID
+				}
+			}
+		}
+		return r
+	}
+	return nil
+}
+
+// Region returns the texture region of a local tile ID within the tileset
+// image, accounting for margin and spacing.
+func (ts *TiledTileset) Region(localID uint32) TextureRegion {
+	cols := ts.Columns
+	if cols <= 0 {
+		cols = 1
+	}
+	col := int(localID) % cols
+	row := int(localID) / cols
+	return TextureRegion{
+		X:         uint16(ts.Margin + col*(ts.TileWidth+ts.Spacing)),
+		Y:         uint16(ts.Margin + row*(ts.TileHeight+ts.Spacing)),
+		Width:     uint16(ts.TileWidth),
+		Height:    uint16(ts.TileHeight),
+		OriginalW: uint16(ts.TileWidth),
+		OriginalH: uint16(ts.TileHeight),
+	}
+}
+
+// Regions returns a GID-indexed region slice covering this tileset, suitable
+// for TileMapViewport.AddTileLayer. Entries below FirstGID are empty.
+func (ts *TiledTileset) Regions() []TextureRegion {
+	regions := make([]TextureRegion, int(ts.FirstGID)+ts.TileCount)
+	for i := 0; i < ts.TileCount; i++ {
+		regions[int(ts.FirstGID)+i] = ts.Region(uint32(i))
+	}
+	return regions
+}
+
+// TileSet returns the tileset as a TileSet for
+// TileMapViewport.AddTileLayerSets.
+func (ts *TiledTileset) TileSet() TileSet {
+	regions := make([]TextureRegion, ts.TileCount)
+	for i := range regions {
+		regions[i] = ts.Region(uint32(i))
+	}
+	return TileSet{
+		FirstGID: ts.FirstGID, Regions: regions, Image: ts.Image,
+		TileWidth: ts.TileWidth, TileHeight: ts.TileHeight,
+	}
+}
+
+// NewViewport builds a TileMapViewport from the map. Tile layers become
+// TileMapLayers with the map's tile animations and collision shapes; object
+// groups become containers holding one node per object (a sprite for tile
+// objects, an empty container otherwise) with UserData pointing at the
+// TiledObject; image layers become sprites. Group layers are flattened, combining their
+// visibility, opacity and offset into their children. Layer visibility,
+// opacity and offset map to Node.Visible, Node.Alpha and Node.X/Y.
+//
+// A tile layer may mix tiles from any of the map's tilesets. The map's
+// orientation and stagger settings are copied to the viewport. Object
+// positions are used as-is, so on non-orthogonal maps they are in Tiled's
+// unprojected pixel space. Tile layers of infinite maps become chunked
+// layers streaming the map's chunks.
+func (m *TiledMap) NewViewport(name string) (*TileMapViewport, error) {
+	v := NewTileMapViewport(name, m.TileWidth, m.TileHeight)
+	switch m.Orientation {
+	case "isometric":
+		v.Orientation = TileIsometric
+	case "staggered":
+		v.Orientation = TileStaggered
+	case "hexagonal":
+		v.Orientation = TileHexagonal
+	}
+	v.StaggerX = m.StaggerAxis == "x"
+	v.StaggerEven = m.StaggerIndex == "even"
+	v.HexSideLength = m.HexSideLength
+	anims := m.Animations()
+	collisions := m.Collisions()
+	if err := m.addLayers(v, m.Layers, true, 1, 0, 0, anims, collisions); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (m *TiledMap) addLayers(v *TileMapViewport, layers []*TiledLayer, visible bool, opacity, offX, offY float64, anims map[uint32][]AnimFrame, collisions map[uint32]TileCollision) error {
+	for _, tl := range layers {
+		vis := visible && tl.Visible
+		alpha := opacity * tl.Opacity
+		ox, oy := offX+tl.OffsetX, offY+tl.OffsetY
+
+		var n *Node
+		switch tl.Type {
+		case TiledLayerGroup:
+			if err := m.addLayers(v, tl.Layers, vis, alpha, ox, oy, anims, collisions); err != nil {
+				return err
+			}
+			continue
+		case TiledLayerTile:
+			sets, err := m.layerTileSets(tl)
+			if err != nil {
+				return err
+			}
+			var layer *TileMapLayer
+			if m.Infinite {
+				if layer, err = tiledChunkedLayer(v, tl, sets); err != nil {
+					return err
+				}
+			} else {
+				layer = v.AddTileLayerSets(tl.Name, tl.Width, tl.Height, tl.Data, sets)
+			}
+			layer.SetAnimations(anims)
+			layer.SetCollisions(collisions)
+			n = layer.Node()
+			n.X, n.Y = ox, oy
+		case TiledLayerObjects:
+			n = NewContainer(tl.Name)
+			n.X, n.Y = ox, oy
+			for i := range tl.Objects {
+				n.AddChild(m.objectNode(&tl.Objects[i]))
+			}
+			v.AddChild(n)
+		case TiledLayerImage:
+			n = NewSprite(tl.Name, TextureRegion{})
+			n.X, n.Y = ox, oy
+			if tl.Image != nil {
+				n.SetCustomImage(tl.Image)
+			}
+			v.AddChild(n)
+		}
+		n.Visible = vis
+		n.Alpha = alpha
+	}
+	return nil
+}
+
+// tiledChunkedLayer adds an infinite map's tile layer to v as a chunked
+// layer whose LoadChunk returns a copy of the matching chunk from the map,
+// or nil for chunks the map does not contain. The chunk size is taken from
+// the layer's first chunk (16x16 when it has none). Tiled writes equally
+// sized chunks at multiples of their size; other layouts are rejected.
+func tiledChunkedLayer(v *TileMapViewport, tl *TiledLayer, sets []TileSet) (*TileMapLayer, error) {
+	w, h := 16, 16
+	if len(tl.Chunks) > 0 {
+		w, h = tl.Chunks[0].Width, tl.Chunks[0].Height
+	}
+	chunks := make(map[chunkKey][]uint32, len(tl.Chunks))
+	for _, c := range tl.Chunks {
+		if c.Width != w || c.Height != h || c.X%w != 0 || c.Y%h != 0 || len(c.Data) != w*h {
+			return nil, fmt.Errorf("willow: tiled layer %q has unaligned chunk at (%d, %d)", tl.Name, c.X, c.Y)
+		}
+		chunks[chunkKey{floorDiv(c.X, w), floorDiv(c.Y, h)}] = c.Data
+	}
+	layer := v.AddChunkedLayer(tl.Name, w, h, sets)
+	layer.LoadChunk = func(cx, cy int) []uint32 {
+		data := chunks[chunkKey{cx, cy}]
+		if data == nil {
+			return nil
+		}
+		return append([]uint32(nil), data...)
+	}
+	return layer, nil
+}
+
+// layerTileSets returns the tile sets used by a tile layer, in FirstGID
+// order. Tilesets the layer never references are left out so the layer
+// does not allocate buffers for them.
+func (m *TiledMap) layerTileSets(tl *TiledLayer) ([]TileSet, error) {
+	used := make([]bool, len(m.Tilesets))
+	mark := func(data []uint32) error {
+		for _, gid := range data {
+			if gid&^tileFlagMask == 0 {
+				continue
+			}
+			i := m.tilesetIndex(gid)
+			if i < 0 {
+				return fmt.Errorf("willow: tiled layer %q references GID %d with no tileset", tl.Name, gid&^tileFlagMask)
+			}
+			used[i] = true
+		}
+		return nil
+	}
+	if err := mark(tl.Data); err != nil {
+		return nil, err
+	}
+	for _, c := range tl.Chunks {
+		if err := mark(c.Data); err != nil {
+			return nil, err
+		}
+	}
+	var sets []TileSet
+	for i, ts := range m.Tilesets {
+		if !used[i] {
+			continue
+		}
+		if ts.Image == nil {
+			return nil, fmt.Errorf("willow: tiled layer %q uses tileset %q, which has no image", tl.Name, ts.Name)
+		}
+		sets = append(sets, ts.TileSet())
+	}
+	return sets, nil
+}
+
+// objectNode creates the node for one object. Tile objects are anchored at
+// their bottom-left corner like in Tiled.
+func (m *TiledMap) objectNode(o *TiledObject) *Node {
+	var n *Node
+	ts := m.TilesetForGID(o.GID)
+	if ts != nil && ts.Image != nil {
+		r := ts.Region(o.GID&^tileFlagMask - ts.FirstGID)
+		w, h := float64(r.Width), float64(r.Height)
+		n = NewSprite(o.Name, TextureRegion{})
+		n.SetCustomImage(ts.Image.SubImage(image.Rect(int(r.X), int(r.Y), int(r.X)+int(r.Width), int(r.Y)+int(r.Height))).(*ebiten.Image))
+		n.PivotY = h
+		if o.Width > 0 && o.Height > 0 {
+			n.ScaleX, n.ScaleY = o.Width/w, o.Height/h
+		}
+		if o.GID&tileFlipH != 0 {
+			n.ScaleX = -n.ScaleX
+			n.PivotX = w
+		}
+		if o.GID&tileFlipV != 0 {
+			n.ScaleY = -n.ScaleY
+			n.PivotY = 0
+		}
+	} else {
+		n = NewContainer(o.Name)
+	}
+	n.X, n.Y = o.X, o.Y
+	n.Rotation = o.Rotation * math.Pi / 180
+	n.Visible = o.Visible
+	n.UserData = o
+	return n
+}
+
+// --- loading ---
+
+// tiledLoader carries the file system and an image cache across the map
+// and its external tilesets.
+type tiledLoader struct {
+	fsys   fs.FS
+	images map[string]*ebiten.Image
+}
+
+func (l *tiledLoader) loadImage(p string) (*ebiten.Image, error) {
+	if img, ok := l.images[p]; ok {
+		return img, nil
+	}
+	data, err := fs.ReadFile(l.fsys, p)
+	if err != nil {
+		return nil, fmt.Errorf("willow: failed to read tiled image: %w", err)
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("willow: failed to decode tiled image %q: %w", p, err)
+	}
+	img := ebiten.NewImageFromImage(src)
+	l.images[p] = img
+	return img, nil
+}
+
+// finishTileset resolves the tileset image and fills in derived fields.
+func (l *tiledLoader) finishTileset(ts *TiledTileset, dir string) error {
+	if ts.ImagePath != "" {
+		ts.ImagePath = path.Join(dir, ts.ImagePath)
+		img, err := l.loadImage(ts.ImagePath)
+		if err != nil {
+			return err
+		}
+		ts.Image = img
+		if ts.ImageWidth == 0 {
+			b := img.Bounds()
+			ts.ImageWidth, ts.ImageHeight = b.Dx(), b.Dy()
+		}
+	}
+	if ts.Columns == 0 && ts.TileWidth > 0 && ts.ImageWidth > 0 {
+		ts.Columns = (ts.ImageWidth - 2*ts.Margin + ts.Spacing) / (ts.TileWidth + ts.Spacing)
+	}
+	for _, t := range ts.Tiles {
+		for i := range t.Animation {
+			t.Animation[i].GID += ts.FirstGID
+		}
+	}
+	return nil
+}
+
+// finishLayers resolves image layer paths relative to the map.
+func (l *tiledLoader) finishLayers(layers []*TiledLayer, dir string) error {
+	for _, tl := range layers {
+		if tl.Type == TiledLayerImage && tl.ImagePath != "" {
+			tl.ImagePath = path.Join(dir, tl.ImagePath)
+			img, err := l.loadImage(tl.ImagePath)
+			if err != nil {
+				return err
+			}
+			tl.Image = img
+		}
+		if err := l.finishLayers(tl.Layers, dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadExternalTileset reads a .tsx (XML) or .tsj/.json tileset.
+func (l *tiledLoader) loadExternalTileset(dir, source string, firstGID uint32) (*TiledTileset, error) {
+	p := path.Join(dir, source)
+	data, err := fs.ReadFile(l.fsys, p)
+	if err != nil {
+		return nil, fmt.Errorf("willow: failed to read tiled tileset: %w", err)
+	}
+	var ts *TiledTileset
+	if strings.EqualFold(path.Ext(p), ".tsx") {
+		var x tmxTileset
+		if err := xml.Unmarshal(data, &x); err != nil {
+			return nil, fmt.Errorf("willow: failed to parse tileset %q: %w", p, err)
+		}
+		ts = x.toTileset()
+	} else {
+		var j tmjTileset
+		if err := json.Unmarshal(data, &j); err != nil {
+			return nil, fmt.Errorf("willow: failed to parse tileset %q: %w", p, err)
+		}
+		ts = j.toTileset()
+	}
+	ts.FirstGID = firstGID
+	if err := l.finishTileset(ts, path.Dir(p)); err != nil {
+		return nil, err
+	}
+	return ts, nil
+}
+
+// decodeTiledData decodes a layer or chunk payload. An empty encoding means
+// the caller already has the GIDs (XML <tile> elements or a JSON array).
+func decodeTiledData(encoding, compression, payload string) ([]uint32, error) {
+	switch encoding {
+	case "csv":
+		fields := strings.Split(payload, ",")
+		gids := make([]uint32, 0, len(fields))
+		for _, f := range fields {
+			f = strings.TrimSpace(f)
+			if f == "" {
+				continue
+			}
+			v, err := strconv.ParseUint(f, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("willow: invalid tiled CSV value %q: %w", f, err)
+			}
+			gids = append(gids, uint32(v))
+		}
+		return gids, nil
+	case "base64":
+		raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(payload))
+		if err != nil {
+			return nil, fmt.Errorf("willow: invalid tiled base64 data: %w", err)
+		}
+		var r io.Reader
+		switch compression {
+		case "":
+		case "zlib":
+			r, err = zlib.NewReader(bytes.NewReader(raw))
+		case "gzip":
+			r, err = gzip.NewReader(bytes.NewReader(raw))
+		default:
+			return nil, fmt.Errorf("willow: unsupported tiled compression %q", compression)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("willow: failed to open %s tile data: %w", compression, err)
+		}
+		if r != nil {
+			if raw, err = io.ReadAll(r); err != nil {
+				return nil, fmt.Errorf("willow: failed to decompress %s tile data: %w", compression, err)
+			}
+		}
+		if len(raw)%4 != 0 {
+			return nil, fmt.Errorf("willow: tiled tile data length %d is not a multiple of 4", len(raw))
+		}
+		gids := make([]uint32, len(raw)/4)
+		for i := range gids {
+			gids[i] = binary.LittleEndian.Uint32(raw[i*4:])
+		}
+		return gids, nil
+	default:
+		return nil, fmt.Errorf("willow: unsupported tiled encoding %q", encoding)
+	}
+}
+
+func sortTilesets(ts []*TiledTileset) {
+	sort.Slice(ts, func(i, j int) bool { return ts[i].FirstGID < ts[j].FirstGID })
+}
+
+func checkTiledData(tl *TiledLayer) error {
+	if tl.Data != nil && len(tl.Data) != tl.Width*tl.Height {
+		return fmt.Errorf("willow: tiled layer %q has %d tiles, want %d", tl.Name, len(tl.Data), tl.Width*tl.Height)
+	}
+	return nil
+}
+
+func tiledPoints(s string) []Vec2 {
+	fields := strings.Fields(s)
+	pts := make([]Vec2, 0, len(fields))
+	for _, f := range fields {
+		x, y, ok := strings.Cut(f, ",")
+		if !ok {
+			continue
+		}
+		px, _ := strconv.ParseFloat(x, 64)
+		py, _ := strconv.ParseFloat(y, 64)
+		pts = append(pts, Vec2{X: px, Y: py})
+	}
+	return pts
+}
+
+// --- TMX (XML) ---
+
+type tmxProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+	Text  string `xml:",chardata"`
+}
+
+func tmxProps(props []tmxProperty) map[string]string {
+	if len(props) == 0 {
+		return nil
+	}
+	m := make(map[string]string, len(props))
+	for _, p := range props {
+		if p.Value == "" {
+			p.Value = p.Text // multi-line strings are stored as element text
+		}
+		m[p.Name] = p.Value
+	}
+	return m
+}
+
+type tmxImage struct {
+	Source string `xml:"source,attr"`
+	Width  int    `xml:"width,attr"`
+	Height int    `xml:"height,attr"`
+}
+
+type tmxData struct {
+	Encoding    string `xml:"encoding,attr"`
+	Compression string `xml:"compression,attr"`
+	Text        string `xml:",chardata"`
+	Tiles       []struct {
+		GID uint32 `xml:"gid,attr"`
+	} `xml:"tile"`
+	Chunks []tmxChunk `xml:"chunk"`
+}
+
+type tmxChunk struct {
+	X      int    `xml:"x,attr"`
+	Y      int    `xml:"y,attr"`
+	Width  int    `xml:"width,attr"`
+	Height int    `xml:"height,attr"`
+	Text   string `xml:",chardata"`
+	Tiles  []struct {
+		GID uint32 `xml:"gid,attr"`
+	} `xml:"tile"`
+}
+
+type tmxObject struct {
+	ID       int       `xml:"id,attr"`
+	Name     string    `xml:"name,attr"`
+	Type     string    `xml:"type,attr"`
+	Class    string    `xml:"class,attr"`
+	X        float64   `xml:"x,attr"`
+	Y        float64   `xml:"y,attr"`
+	Width    float64   `xml:"width,attr"`
+	Height   float64   `xml:"height,attr"`
+	Rotation float64   `xml:"rotation,attr"`
+	GID      uint32    `xml:"gid,attr"`
+	Visible  string    `xml:"visible,attr"`
+	Point    *struct{} `xml:"point"`
+	Ellipse  *struct{} `xml:"ellipse"`
+	Polygon  *struct {
+		Points string `xml:"points,attr"`
+	} `xml:"polygon"`
+	Polyline *struct {
+		Points string `xml:"points,attr"`
+	} `xml:"polyline"`
+	Text *struct {
+		Text string `xml:",chardata"`
+	} `xml:"text"`
+	Properties []tmxProperty `xml:"properties>property"`
+}
+
+func (x *tmxObject) toObject() TiledObject {
+	o := TiledObject{
+		ID: x.ID, Name: x.Name, Type: x.Type,
+		X: x.X, Y: x.Y, Width: x.Width, Height: x.Height,
+		Rotation: x.Rotation, GID: x.GID,
+		Visible:    x.Visible != "0",
+		Point:      x.Point != nil,
+		Ellipse:    x.Ellipse != nil,
+		Properties: tmxProps(x.Properties),
+	}
+	if o.Type == "" {
+		o.Type = x.Class
+	}
+	if x.Polygon != nil {
+		o.Polygon = tiledPoints(x.Polygon.Points)
+	}
+	if x.Polyline != nil {
+		o.Polyline = tiledPoints(x.Polyline.Points)
+	}
+	if x.Text != nil {
+		o.Text = x.Text.Text
+	}
+	return o
+}
+
+// tmxLayer is the union of <layer>, <objectgroup>, <imagelayer> and <group>.
+// Layers are collected with ",any" so their document order is preserved.
+type tmxLayer struct {
+	XMLName    xml.Name
+	ID         int           `xml:"id,attr"`
+	Name       string        `xml:"name,attr"`
+	Visible    string        `xml:"visible,attr"`
+	Opacity    string        `xml:"opacity,attr"`
+	OffsetX    float64       `xml:"offsetx,attr"`
+	OffsetY    float64       `xml:"offsety,attr"`
+	Width      int           `xml:"width,attr"`
+	Height     int           `xml:"height,attr"`
+	Data       *tmxData      `xml:"data"`
+	Objects    []tmxObject   `xml:"object"`
+	Image      *tmxImage     `xml:"image"`
+	Properties []tmxProperty `xml:"properties>property"`
+	Layers     []tmxLayer    `xml:",any"`
+}
+
+func tmxLayers(src []tmxLayer) ([]*TiledLayer, error) {
+	var out []*TiledLayer
+	for i := range src {
+		x := &src[i]
+		tl := &TiledLayer{
+			ID: x.ID, Name: x.Name,
+			Visible: x.Visible != "0",
+			Opacity: 1,
+			OffsetX: x.OffsetX, OffsetY: x.OffsetY,
+			Width: x.Width, Height: x.Height,
+			Properties: tmxProps(x.Properties),
+		}
+		if x.Opacity != "" {
+			if op, err := strconv.ParseFloat(x.Opacity, 64); err == nil {
+				tl.Opacity = op
+			}
+		}
+		switch x.XMLName.Local {
+		case "layer":
+			tl.Type = TiledLayerTile
+			if x.Data != nil {
+				if err := x.Data.decode(tl); err != nil {
+					return nil, err
+				}
+			}
+		case "objectgroup":
+			tl.Type = TiledLayerObjects
+			tl.Objects = make([]TiledObject, len(x.Objects))
+			for j := range x.Objects {
+				tl.Objects[j] = x.Objects[j].toObject()
+			}
+		case "imagelayer":
+			tl.Type = TiledLayerImage
+			if x.Image != nil {
+				tl.ImagePath = x.Image.Source
+			}
+		case "group":
+			tl.Type = TiledLayerGroup
+			children, err := tmxLayers(x.Layers)
+			if err != nil {
+				return nil, err
+			}
+			tl.Layers = children
+		default:
+			continue
+		}
+		out = append(out, tl)
+	}
+	return out, nil
+}
+
+func (d *tmxData) decode(tl *TiledLayer) error {
+	if len(d.Chunks) > 0 {
+		for _, c := range d.Chunks {
+			gids, err := tmxGIDs(d.Encoding, d.Compression, c.Text, c.Tiles)
+			if err != nil {
+				return err
+			}
+			tl.Chunks = append(tl.Chunks, TiledChunk{X: c.X, Y: c.Y, Width: c.Width, Height: c.Height, Data: gids})
+		}
+		return nil
+	}
+	gids, err := tmxGIDs(d.Encoding, d.Compression, d.Text, d.Tiles)
+	if err != nil {
+		return err
+	}
+	tl.Data = gids
+	return checkTiledData(tl)
+}
+
+func tmxGIDs(encoding, compression, text string, tiles []struct {
+	GID uint32 `xml:"gid,attr"`
+}) ([]uint32, error) {
+	if encoding == "" {
+		gids := make([]uint32, len(tiles))
+		for i, t := range tiles {
+			gids[i] = t.GID
+		}
+		return gids, nil
+	}
+	return decodeTiledData(encoding, compression, text)
+}
+
+type tmxTileset struct {
+	FirstGID   uint32        `xml:"firstgid,attr"`
+	Source     string        `xml:"source,attr"`
+	Name       string        `xml:"name,attr"`
+	TileWidth  int           `xml:"tilewidth,attr"`
+	TileHeight int           `xml:"tileheight,attr"`
+	Spacing    int           `xml:"spacing,attr"`
+	Margin     int           `xml:"margin,attr"`
+	TileCount  int           `xml:"tilecount,attr"`
+	Columns    int           `xml:"columns,attr"`
+	Image      *tmxImage     `xml:"image"`
+	Tiles      []tmxTile     `xml:"tile"`
+	Properties []tmxProperty `xml:"properties>property"`
+	WangSets   []struct {
+		Name   string `xml:"name,attr"`
+		Type   string `xml:"type,attr"`
+		Colors []struct {
+			Name string `xml:"name,attr"`
+		} `xml:"wangcolor"`
+		Tiles []struct {
+			TileID uint32 `xml:"tileid,attr"`
+			WangID string `xml:"wangid,attr"`
+		} `xml:"wangtile"`
+	} `xml:"wangsets>wangset"`
+}
+
+type tmxTile struct {
+	ID         uint32        `xml:"id,attr"`
+	Type       string        `xml:"type,attr"`
+	Class      string        `xml:"class,attr"`
+	Properties []tmxProperty `xml:"properties>property"`
+	Animation  []struct {
+		TileID   uint32 `xml:"tileid,attr"`
+		Duration int    `xml:"duration,attr"`
+	} `xml:"animation>frame"`
+	ObjectGroup *tmxLayer `xml:"objectgroup"`
+}
+
+// toTileset converts an embedded or external tileset. Animation GIDs are
+// local until finishTileset adds FirstGID.
+func (x *tmxTileset) toTileset() *TiledTileset {
+	ts := &TiledTileset{
+		FirstGID: x.FirstGID, Name: x.Name,
+		TileWidth: x.TileWidth, TileHeight: x.TileHeight,
+		Spacing: x.Spacing, Margin: x.Margin,
+		TileCount: x.TileCount, Columns: x.Columns,
+		Properties: tmxProps(x.Properties),
+	}
+	if x.Image != nil {
+		ts.ImagePath = x.Image.Source
+		ts.ImageWidth, ts.ImageHeight = x.Image.Width, x.Image.Height
+	}
+	for _, xt := range x.Tiles {
+		t := &TiledTile{ID: xt.ID, Type: xt.Type, Properties: tmxProps(xt.Properties)}
+		if t.Type == "" {
+			t.Type = xt.Class
+		}
+		for _, f := range xt.Animation {
+			t.Animation = append(t.Animation, AnimFrame{GID: f.TileID, Duration: f.Duration})
+		}
+		if xt.ObjectGroup != nil {
+			for j := range xt.ObjectGroup.Objects {
+				t.Objects = append(t.Objects, xt.ObjectGroup.Objects[j].toObject())
+			}
+		}
+		if ts.Tiles == nil {
+			ts.Tiles = make(map[uint32]*TiledTile)
+		}
+		ts.Tiles[t.ID] = t
+	}
+	for _, xw := range x.WangSets {
+		ws := TiledWangSet{Name: xw.Name, Type: xw.Type}
+		for _, c := range xw.Colors {
+			ws.Colors = append(ws.Colors, c.Name)
+		}
+		for _, wt := range xw.Tiles {
+			t := TiledWangTile{ID: wt.TileID}
+			for i, f := range strings.SplitN(wt.WangID, ",", 8) {
+				n, _ := strconv.Atoi(strings.TrimSpace(f))
+				t.WangID[i] = uint8(n)
+			}
+			ws.Tiles = append(ws.Tiles, t)
+		}
+		ts.WangSets = append(ts.WangSets, ws)
+	}
+	return ts
+}
+
+type tmxMap struct {
+	Orientation   string        `xml:"orientation,attr"`
+	RenderOrder   string        `xml:"renderorder,attr"`
+	Width         int           `xml:"width,attr"`
+	Height        int           `xml:"height,attr"`
+	TileWidth     int           `xml:"tilewidth,attr"`
+	TileHeight    int           `xml:"tileheight,attr"`
+	Infinite      int           `xml:"infinite,attr"`
+	StaggerAxis   string        `xml:"staggeraxis,attr"`
+	StaggerIndex  string        `xml:"staggerindex,attr"`
+	HexSideLength int           `xml:"hexsidelength,attr"`
+	Properties    []tmxProperty `xml:"properties>property"`
+	Tilesets      []tmxTileset  `xml:"tileset"`
+	Layers        []tmxLayer    `xml:",any"`
+}
+
+func (l *tiledLoader) parseTMX(data []byte, dir string) (*TiledMap, error) {
+	var x tmxMap
+	if err := xml.Unmarshal(data, &x); err != nil {
+		return nil, fmt.Errorf("willow: failed to parse TMX: %w", err)
+	}
+	m := &TiledMap{
+		Orientation: x.Orientation, RenderOrder: x.RenderOrder,
+		Width: x.Width, Height: x.Height,
+		TileWidth: x.TileWidth, TileHeight: x.TileHeight,
+		Infinite:    x.Infinite != 0,
+		StaggerAxis: x.StaggerAxis, StaggerIndex: x.StaggerIndex,
+		HexSideLength: x.HexSideLength,
+		Properties:    tmxProps(x.Properties),
+	}
+	for i := range x.Tilesets {
+		xt := &x.Tilesets[i]
+		var ts *TiledTileset
+		var err error
+		if xt.Source != "" {
+			ts, err = l.loadExternalTileset(dir, xt.Source, xt.FirstGID)
+		} else {
+			ts = xt.toTileset()
+			err = l.finishTileset(ts, dir)
+		}
+		if err != nil {
+			return nil, err
+		}
+		m.Tilesets = append(m.Tilesets, ts)
+	}
+	sortTilesets(m.Tilesets)
+	layers, err := tmxLayers(x.Layers)
+	if err != nil {
+		return nil, err
+	}
+	m.Layers = layers
+	if err := l.finishLayers(m.Layers, dir); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// --- TMJ (JSON) ---
+
+type tmjProperty struct {
+	Name  string `json:"name"`
+	Value any    `json:"value"`
+}
+
+func tmjProps(props []tmjProperty) map[string]string {
+	if len(props) == 0 {
+		return nil
+	}
+	m := make(map[string]string, len(props))
+	for _, p := range props {
+		m[p.Name] = fmt.Sprint(p.Value)
+	}
+	return m
+}
+
+type tmjPoint struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+func tmjPoints(pts []tmjPoint) []Vec2 {
+	if pts == nil {
+		return nil
+	}
+	out := make([]Vec2, len(pts))
+	for i, p := range pts {
+		out[i] = Vec2{X: p.X, Y: p.Y}
+	}
+	return out
+}
+
+type tmjObject struct {
+	ID       int        `json:"id"`
+	Name     string     `json:"name"`
+	Type     string     `json:"type"`
+	Class    string     `json:"class"`
+	X        float64    `json:"x"`
+	Y        float64    `json:"y"`
+	Width    float64    `json:"width"`
+	Height   float64    `json:"height"`
+	Rotation float64    `json:"rotation"`
+	GID      uint32     `json:"gid"`
+	Visible  *bool      `json:"visible"`
+	Point    bool       `json:"point"`
+	Ellipse  bool       `json:"ellipse"`
+	Polygon  []tmjPoint `json:"polygon"`
+	Polyline []tmjPoint `json:"polyline"`
+	Text     *struct {
+		Text string `json:"text"`
+	} `json:"text"`
+	Properties []tmjProperty `json:"properties"`
+}
+
+func (j *tmjObject) toObject() TiledObject {
+	o := TiledObject{
+		ID: j.ID, Name: j.Name, Type: j.Type,
+		X: j.X, Y: j.Y, Width: j.Width, Height: j.Height,
+		Rotation: j.Rotation, GID: j.GID,
+		Visible:    j.Visible == nil || *j.Visible,
+		Point:      j.Point,
+		Ellipse:    j.Ellipse,
+		Polygon:    tmjPoints(j.Polygon),
+		Polyline:   tmjPoints(j.Polyline),
+		Properties: tmjProps(j.Properties),
+	}
+	if o.Type == "" {
+		o.Type = j.Class
+	}
+	if j.Text != nil {
+		o.Text = j.Text.Text
+	}
+	return o
+}
+
+type tmjChunk struct {
+	X      int             `json:"x"`
+	Y      int             `json:"y"`
+	Width  int             `json:"width"`
+	Height int             `json:"height"`
+	Data   json.RawMessage `json:"data"`
+}
+
+type tmjLayer struct {
+	ID          int             `json:"id"`
+	Name        string          `json:"name"`
+	Type        string          `json:"type"`
+	Visible     *bool           `json:"visible"`
+	Opacity     *float64        `json:"opacity"`
+	OffsetX     float64         `json:"offsetx"`
+	OffsetY     float64         `json:"offsety"`
+	Width       int             `json:"width"`
+	Height      int             `json:"height"`
+	Encoding    string          `json:"encoding"`
+	Compression string          `json:"compression"`
+	Data        json.RawMessage `json:"data"`
+	Chunks      []tmjChunk      `json:"chunks"`
+	Objects     []tmjObject     `json:"objects"`
+	Image       string          `json:"image"`
+	Layers      []tmjLayer      `json:"layers"`
+	Properties  []tmjProperty   `json:"properties"`
+}
+
+// tmjGIDs decodes a JSON data value: a GID array, or a string payload.
+func tmjGIDs(raw json.RawMessage, encoding, compression string) ([]uint32, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || raw[0] == 'n' {
+		return nil, nil
+	}
+	if raw[0] == '[' {
+		var gids []uint32
+		if err := json.Unmarshal(raw, &gids); err != nil {
+			return nil, fmt.Errorf("willow: failed to parse tiled layer data: %w", err)
+		}
+		return gids, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, fmt.Errorf("willow: failed to parse tiled layer data: %w", err)
+	}
+	if encoding == "" {
+		encoding = "base64"
+	}
+	return decodeTiledData(encoding, compression, s)
+}
+
+func tmjLayers(src []tmjLayer) ([]*TiledLayer, error) {
+	var out []*TiledLayer
+	for i := range src {
+		j := &src[i]
+		tl := &TiledLayer{
+			ID: j.ID, Name: j.Name,
+			Visible: j.Visible == nil || *j.Visible,
+			Opacity: 1,
+			OffsetX: j.OffsetX, OffsetY: j.OffsetY,
+			Width: j.Width, Height: j.Height,
+			Properties: tmjProps(j.Properties),
+		}
+		if j.Opacity != nil {
+			tl.Opacity = *j.Opacity
+		}
+		switch j.Type {
+		case "tilelayer":
+			tl.Type = TiledLayerTile
+			for _, c := range j.Chunks {
+				gids, err := tmjGIDs(c.Data, j.Encoding, j.Compression)
+				if err != nil {
+					return nil, err
+				}
+				tl.Chunks = append(tl.Chunks, TiledChunk{X: c.X, Y: c.Y, Width: c.Width, Height: c.Height, Data: gids})
+			}
+			gids, err := tmjGIDs(j.Data, j.Encoding, j.Compression)
+			if err != nil {
+				return nil, err
+			}
+			tl.Data = gids
+			if err := checkTiledData(tl); err != nil {
+				return nil, err
+			}
+		case "objectgroup":
+			tl.Type = TiledLayerObjects
+			tl.Objects = make([]TiledObject, len(j.Objects))
+			for k := range j.Objects {
+				tl.Objects[k] = j.Objects[k].toObject()
+			}
+		case "imagelayer":
+			tl.Type = TiledLayerImage
+			tl.ImagePath = j.Image
+		case "group":
+			tl.Type = TiledLayerGroup
+			children, err := tmjLayers(j.Layers)
+			if err != nil {
+				return nil, err
+			}
+			tl.Layers = children
+		default:
+			continue
+		}
+		out = append(out, tl)
+	}
+	return out, nil
+}
+
+type tmjTileset struct {
+	FirstGID    uint32        `json:"firstgid"`
+	Source      string        `json:"source"`
+	Name        string        `json:"name"`
+	TileWidth   int           `json:"tilewidth"`
+	TileHeight  int           `json:"tileheight"`
+	Spacing     int           `json:"spacing"`
+	Margin      int           `json:"margin"`
+	TileCount   int           `json:"tilecount"`
+	Columns     int           `json:"columns"`
+	Image       string        `json:"image"`
+	ImageWidth  int           `json:"imagewidth"`
+	ImageHeight int           `json:"imageheight"`
+	Tiles       []tmjTile     `json:"tiles"`
+	Properties  []tmjProperty `json:"properties"`
+	WangSets    []struct {
+		Name   string `json:"name"`
+		Type   string `json:"type"`
+		Colors []struct {
+			Name string `json:"name"`
+		} `json:"colors"`
+		Tiles []struct {
+			TileID uint32   `json:"tileid"`
+			WangID [8]uint8 `json:"wangid"`
+		} `json:"wangtiles"`
+	} `json:"wangsets"`
+}
+
+type tmjTile struct {
+	ID         uint32        `json:"id"`
+	Type       string        `json:"type"`
+	Class      string        `json:"class"`
+	Properties []tmjProperty `json:"properties"`
+	Animation  []struct {
+		TileID   uint32 `json:"tileid"`
+		Duration int    `json:"duration"`
+	} `json:"animation"`
+	ObjectGroup *tmjLayer `json:"objectgroup"`
+}
+
+func (j *tmjTileset) toTileset() *TiledTileset {
+	ts := &TiledTileset{
+		FirstGID: j.FirstGID, Name: j.Name,
+		TileWidth: j.TileWidth, TileHeight: j.TileHeight,
+		Spacing: j.Spacing, Margin: j.Margin,
+		TileCount: j.TileCount, Columns: j.Columns,
+		ImagePath: j.Image, ImageWidth: j.ImageWidth, ImageHeight: j.ImageHeight,
+		Properties: tmjProps(j.Properties),
+	}
+	for _, jt := range j.Tiles {
+		t := &TiledTile{ID: jt.ID, Type: jt.Type, Properties: tmjProps(jt.Properties)}
+		if t.Type == "" {
+			t.Type = jt.Class
+		}
+		for _, f := range jt.Animation {
+			t.Animation = append(t.Animation, AnimFrame{GID: f.TileID, Duration: f.Duration})
+		}
+		if jt.ObjectGroup != nil {
+			for k := range jt.ObjectGroup.Objects {
+				t.Objects = append(t.Objects, jt.ObjectGroup.Objects[k].toObject())
+			}
+		}
+		if ts.Tiles == nil {
+			ts.Tiles = make(map[uint32]*TiledTile)
+		}
+		ts.Tiles[t.ID] = t
+	}
+	for _, jw := range j.WangSets {
+		ws := TiledWangSet{Name: jw.Name, Type: jw.Type}
+		for _, c := range jw.Colors {
+			ws.Colors = append(ws.Colors, c.Name)
+		}
+		for _, wt := range jw.Tiles {
+			ws.Tiles = append(ws.Tiles, TiledWangTile{ID: wt.TileID, WangID: wt.WangID})
+		}
+		ts.WangSets = append(ts.WangSets, ws)
+	}
+	return ts
+}
+
+type tmjMap struct {
+	Orientation   string        `json:"orientation"`
+	RenderOrder   string        `json:"renderorder"`
+	Width         int           `json:"width"`
+	Height        int           `json:"height"`
+	TileWidth     int           `json:"tilewidth"`
+	TileHeight    int           `json:"tileheight"`
+	Infinite      bool          `json:"infinite"`
+	StaggerAxis   string        `json:"staggeraxis"`
+	StaggerIndex  string        `json:"staggerindex"`
+	HexSideLength int           `json:"hexsidelength"`
+	Properties    []tmjProperty `json:"properties"`
+	Tilesets      []tmjTileset  `json:"tilesets"`
+	Layers        []tmjLayer    `json:"layers"`
+}
+
+func (l *tiledLoader) parseTMJ(data []byte, dir string) (*TiledMap, error) {
+	var j tmjMap
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, fmt.Errorf("willow: failed to parse TMJ: %w", err)
+	}
+	m := &TiledMap{
+		Orientation: j.Orientation, RenderOrder: j.RenderOrder,
+		Width: j.Width, Height: j.Height,
+		TileWidth: j.TileWidth, TileHeight: j.TileHeight,
+		Infinite:    j.Infinite,
+		StaggerAxis: j.StaggerAxis, StaggerIndex: j.StaggerIndex,
+		HexSideLength: j.HexSideLength,
+		Properties:    tmjProps(j.Properties),
+	}
+	for i := range j.Tilesets {
+		jt := &j.Tilesets[i]
+		var ts *TiledTileset
+		var err error
+		if jt.Source != "" {
+			ts, err = l.loadExternalTileset(dir, jt.Source, jt.FirstGID)
+		} else {
+			ts = jt.toTileset()
+			err = l.finishTileset(ts, dir)
+		}
+		if err != nil {
+			return nil, err
+		}
+		m.Tilesets = append(m.Tilesets, ts)
+	}
+	sortTilesets(m.Tilesets)
+	layers, err := tmjLayers(j.Layers)
+	if err != nil {
+		return nil, err
+	}
+	m.Layers = layers
+	if err := l.finishLayers(m.Layers, dir); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
@@ -0,0 +1,375 @@
+package willow
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// testPNG returns an encoded w×h PNG.
+func testPNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, w, h))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// encodeGIDs returns base64 tile data, optionally compressed.
+func encodeGIDs(t *testing.T, gids []uint32, compression string) string {
+	t.Helper()
+	raw := make([]byte, len(gids)*4)
+	for i, g := range gids {
+		binary.LittleEndian.PutUint32(raw[i*4:], g)
+	}
+	var buf bytes.Buffer
+	switch compression {
+	case "zlib":
+		w := zlib.NewWriter(&buf)
+		w.Write(raw)
+		w.Close()
+	case "gzip":
+		w := gzip.NewWriter(&buf)
+		w.Write(raw)
+		w.Close()
+	default:
+		buf.Write(raw)
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+const testTMX = `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" renderorder="right-down" width="3" height="2" tilewidth="16" tileheight="16" infinite="0">
+ <properties>
+  <property name="music" value="town.ogg"/>
+ </properties>
+ <tileset firstgid="1" name="ground" tilewidth="16" tileheight="16" spacing="1" margin="1" tilecount="4" columns="2">
+  <image source="ground.png" width="35" height="35"/>
+  <tile id="2" type="water">
+   <properties><property name="speed" type="float" value="0.5"/></properties>
+   <animation>
+    <frame tileid="2" duration="200"/>
+    <frame tileid="3" duration="200"/>
+   </animation>
+  </tile>
+  <wangsets>
+   <wangset name="paths" type="edge" tile="-1">
+    <wangcolor name="dirt" color="#ff0000" tile="-1" probability="1"/>
+    <wangtile tileid="1" wangid="1,0,1,0,0,0,0,0"/>
+   </wangset>
+  </wangsets>
+ </tileset>
+ <tileset firstgid="5" source="props.tsx"/>
+ <layer id="1" name="ground" width="3" height="2">
+  <data encoding="csv">
+1,2,3,
+2147483652,6,1
+</data>
+ </layer>
+ <group id="5" name="upper" opacity="0.5" offsetx="4">
+  <layer id="2" name="deco" width="3" height="2" opacity="0.5" visible="0" offsety="2">
+   <data encoding="base64" compression="zlib">ZLIBDATA</data>
+  </layer>
+  <objectgroup id="3" name="things">
+   <object id="1" name="spawn" type="marker" x="8" y="24"><point/></object>
+   <object id="2" name="crate" gid="5" x="16" y="32" width="16" height="16"/>
+   <object id="3" name="zone" x="0" y="0" rotation="90">
+    <polygon points="0,0 10,0 10,10"/>
+   </object>
+  </objectgroup>
+ </group>
+ <imagelayer id="4" name="sky">
+  <image source="sky.png"/>
+ </imagelayer>
+</map>`
+
+const testTSX = `<?xml version="1.0" encoding="UTF-8"?>
+<tileset version="1.10" name="props" tilewidth="16" tileheight="16" tilecount="2" columns="2">
+ <image source="img/props.png" width="32" height="16"/>
+ <tile id="0">
+  <objectgroup><object id="1" x="0" y="8" width="16" height="8"/></objectgroup>
+ </tile>
+</tileset>`
+
+func testTiledFS(t *testing.T) fstest.MapFS {
+	t.Helper()
+	tmx := strings.Replace(testTMX, "ZLIBDATA", encodeGIDs(t, []uint32{0, 4, 0, 0, 0, 4}, "zlib"), 1)
+	return fstest.MapFS{
+		"maps/town.tmx":          {Data: []byte(tmx)},
+		"maps/ground.png":        {Data: testPNG(t, 35, 35)},
+		"maps/sky.png":           {Data: testPNG(t, 8, 8)},
+		"maps/props.tsx":         {Data: []byte(testTSX)},
+		"maps/img/props.png":     {Data: testPNG(t, 32, 16)},
+		"maps/town.tmj":          {Data: []byte(testTMJ(t))},
+		"maps/props.tsj":         {Data: []byte(testTSJ)},
+		"maps/bad_zstd.tmj":      {Data: []byte(`{"width":1,"height":1,"layers":[{"type":"tilelayer","width":1,"height":1,"encoding":"base64","compression":"zstd","data":"AAAA"}]}`)},
+		"maps/bad_size.tmx":      {Data: []byte(`<map width="2" height="2"><layer name="x" width="2" height="2"><data encoding="csv">1,2,3</data></layer></map>`)},
+		"maps/missing_image.tmx": {Data: []byte(`<map><tileset firstgid="1" name="x" tilewidth="8" tileheight="8"><image source="nope.png"/></tileset></map>`)},
+	}
+}
+
+func TestLoadTiledMap_TMX(t *testing.T) {
+	m, err := LoadTiledMap(testTiledFS(t), "maps/town.tmx")
+	if err != nil {
+		t.Fatalf("LoadTiledMap: %v", err)
+	}
+	if m.Width != 3 || m.Height != 2 || m.TileWidth != 16 || m.Orientation != "orthogonal" {
+		t.Errorf("map header = %+v", m)
+	}
+	if m.Properties["music"] != "town.ogg" {
+		t.Errorf("map properties = %v", m.Properties)
+	}
+	if len(m.Layers) != 3 {
+		t.Fatalf("top-level layers = %d, want 3", len(m.Layers))
+	}
+
+	ground := m.Layer("ground")
+	want := []uint32{1, 2, 3, tileFlipH | 4, 6, 1}
+	for i, g := range want {
+		if ground.Data[i] != g {
+			t.Fatalf("ground data = %v, want %v", ground.Data, want)
+		}
+	}
+
+	group := m.Layers[1]
+	if group.Type != TiledLayerGroup || group.Opacity != 0.5 || len(group.Layers) != 2 {
+		t.Fatalf("group = %+v", group)
+	}
+	deco := m.Layer("deco")
+	if deco.Visible || deco.Opacity != 0.5 || deco.Data[1] != 4 {
+		t.Errorf("deco = visible %v opacity %v data %v", deco.Visible, deco.Opacity, deco.Data)
+	}
+
+	things := m.Layer("things")
+	if len(things.Objects) != 3 {
+		t.Fatalf("objects = %d, want 3", len(things.Objects))
+	}
+	if o := things.Objects[0]; !o.Point || o.Type != "marker" || o.X != 8 {
+		t.Errorf("spawn = %+v", o)
+	}
+	if o := things.Objects[2]; len(o.Polygon) != 3 || o.Polygon[1] != (Vec2{X: 10, Y: 0}) {
+		t.Errorf("zone polygon = %v", o.Polygon)
+	}
+
+	sky := m.Layer("sky")
+	if sky.Type != TiledLayerImage || sky.ImagePath != "maps/sky.png" || sky.Image == nil {
+		t.Errorf("sky = %+v", sky)
+	}
+}
+
+func TestLoadTiledMap_Tilesets(t *testing.T) {
+	m, err := LoadTiledMap(testTiledFS(t), "maps/town.tmx")
+	if err != nil {
+		t.Fatalf("LoadTiledMap: %v", err)
+	}
+	if len(m.Tilesets) != 2 {
+		t.Fatalf("tilesets = %d, want 2", len(m.Tilesets))
+	}
+	ground := m.Tilesets[0]
+	// Margin 1, spacing 1: tile 3 is at column 1, row 1.
+	if r := ground.Region(3); r.X != 18 || r.Y != 18 || r.Width != 16 {
+		t.Errorf("Region(3) = %+v", r)
+	}
+	water := ground.Tiles[2]
+	if water.Type != "water" || water.Properties["speed"] != "0.5" {
+		t.Errorf("water tile = %+v", water)
+	}
+
+	props := m.Tilesets[1]
+	if props.FirstGID != 5 || props.Name != "props" || props.Image == nil {
+		t.Errorf("external tileset = %+v", props)
+	}
+	if props.ImagePath != "maps/img/props.png" {
+		t.Errorf("external image path = %q, want relative to the .tsx", props.ImagePath)
+	}
+	if objs := props.Tiles[0].Objects; len(objs) != 1 || objs[0].Height != 8 {
+		t.Errorf("collision objects = %+v", objs)
+	}
+
+	if m.TilesetForGID(tileFlipV|6) != props || m.TilesetForGID(4) != ground || m.TilesetForGID(0) != nil {
+		t.Error("TilesetForGID picked the wrong tileset")
+	}
+
+	if ws := ground.WangSets; len(ws) != 1 || ws[0].Type != "edge" || ws[0].Colors[0] != "dirt" ||
+		ws[0].Tiles[0] != (TiledWangTile{ID: 1, WangID: [8]uint8{1, 0, 1}}) {
+		t.Errorf("wang sets = %+v", ws)
+	}
+
+	anims := m.Animations()
+	frames := anims[3]
+	if len(frames) != 2 || frames[0] != (AnimFrame{GID: 3, Duration: 200}) || frames[1].GID != 4 {
+		t.Errorf("animations = %v, want global GIDs 3,4 under base GID 3", anims)
+	}
+}
+
+func testTMJ(t *testing.T) string {
+	return `{
+  "orientation": "orthogonal", "width": 2, "height": 2, "tilewidth": 16, "tileheight": 16, "infinite": false,
+  "tilesets": [
+    {"firstgid": 1, "name": "ground", "tilewidth": 16, "tileheight": 16, "tilecount": 4, "columns": 2,
+     "image": "ground.png", "imagewidth": 32, "imageheight": 32,
+     "tiles": [{"id": 0, "animation": [{"tileid": 0, "duration": 100}, {"tileid": 1, "duration": 100}]}],
+     "wangsets": [{"name": "paths", "type": "mixed", "colors": [{"name": "dirt"}], "wangtiles": [{"tileid": 3, "wangid": [0, 0, 1, 1, 1, 0, 0, 0]}]}]},
+    {"firstgid": 5, "source": "props.tsj"}
+  ],
+  "properties": [{"name": "depth", "type": "int", "value": 3}],
+  "layers": [
+    {"id": 1, "name": "ground", "type": "tilelayer", "width": 2, "height": 2, "opacity": 0.75, "visible": true, "data": [1, 2, 3, 4]},
+    {"id": 2, "name": "packed", "type": "tilelayer", "width": 2, "height": 2, "opacity": 1, "visible": false,
+     "encoding": "base64", "compression": "gzip", "data": "` + encodeGIDs(t, []uint32{5, 0, 0, 6}, "gzip") + `"},
+    {"id": 3, "name": "raw", "type": "tilelayer", "width": 2, "height": 2, "encoding": "base64", "data": "` + encodeGIDs(t, []uint32{1, 1, 1, 1}, "") + `"},
+    {"id": 4, "name": "objects", "type": "objectgroup", "objects": [
+      {"id": 1, "name": "door", "class": "portal", "x": 4, "y": 5, "width": 8, "height": 8, "visible": true,
+       "properties": [{"name": "target", "type": "string", "value": "cave"}]},
+      {"id": 2, "name": "path", "x": 0, "y": 0, "visible": false, "polyline": [{"x": 0, "y": 0}, {"x": 5, "y": 5}]}
+    ]}
+  ]
+}`
+}
+
+const testTSJ = `{"name": "props", "tilewidth": 16, "tileheight": 16, "tilecount": 2, "columns": 2,
+  "image": "img/props.png", "imagewidth": 32, "imageheight": 16}`
+
+func TestLoadTiledMap_TMJ(t *testing.T) {
+	m, err := LoadTiledMap(testTiledFS(t), "maps/town.tmj")
+	if err != nil {
+		t.Fatalf("LoadTiledMap: %v", err)
+	}
+	if m.Properties["depth"] != "3" {
+		t.Errorf("properties = %v", m.Properties)
+	}
+	if len(m.Tilesets) != 2 || m.Tilesets[1].FirstGID != 5 || m.Tilesets[1].Image == nil {
+		t.Fatalf("tilesets = %+v", m.Tilesets)
+	}
+	if r := m.Tilesets[0].AutotileRules("paths"); r == nil || r.Mode != AutotileBlob ||
+		r.Terrains[1][AutotileE|AutotileSE|AutotileS] != 4 {
+		t.Errorf("wang set rules = %+v", r)
+	}
+
+	ground := m.Layer("ground")
+	if ground.Opacity != 0.75 || !ground.Visible || ground.Data[3] != 4 {
+		t.Errorf("ground = %+v", ground)
+	}
+	packed := m.Layer("packed")
+	if packed.Visible || packed.Data[0] != 5 || packed.Data[3] != 6 {
+		t.Errorf("gzip layer = visible %v data %v", packed.Visible, packed.Data)
+	}
+	if raw := m.Layer("raw"); raw.Data[2] != 1 {
+		t.Errorf("uncompressed base64 data = %v", raw.Data)
+	}
+
+	objs := m.Layer("objects").Objects
+	if objs[0].Type != "portal" || objs[0].Properties["target"] != "cave" {
+		t.Errorf("door = %+v", objs[0])
+	}
+	if objs[1].Visible || len(objs[1].Polyline) != 2 {
+		t.Errorf("path = %+v", objs[1])
+	}
+	if anims := m.Animations(); len(anims[1]) != 2 || anims[1][1].GID != 2 {
+		t.Errorf("animations = %v", anims)
+	}
+}
+
+func TestLoadTiledMap_Errors(t *testing.T) {
+	fsys := testTiledFS(t)
+	for _, name := range []string{"maps/bad_zstd.tmj", "maps/bad_size.tmx", "maps/missing_image.tmx", "maps/nope.tmx"} {
+		if _, err := LoadTiledMap(fsys, name); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestTiledMapNewViewport(t *testing.T) {
+	m, err := LoadTiledMap(testTiledFS(t), "maps/town.tmx")
+	if err != nil {
+		t.Fatalf("LoadTiledMap: %v", err)
+	}
+	v, err := m.NewViewport("town")
+	if err != nil {
+		t.Fatalf("NewViewport: %v", err)
+	}
+	if v.TileWidth != 16 || len(v.layers) != 2 {
+		t.Fatalf("viewport tile width %d, layers %d", v.TileWidth, len(v.layers))
+	}
+
+	ground := v.layers[0]
+	if len(ground.pages) != 2 || ground.pages[0].image != m.Tilesets[0].Image || ground.pages[1].image != m.Tilesets[1].Image {
+		t.Errorf("ground layer should draw from both tilesets it references")
+	}
+	if ground.regions[6].X != 16 || ground.regionPage[6] != 1 {
+		t.Errorf("GID 6 = %+v on page %d, want props tile 1 on page 1", ground.regions[6], ground.regionPage[6])
+	}
+	if ground.anims[3] == nil {
+		t.Error("tile animations not applied to layer")
+	}
+
+	// Group opacity 0.5 and the layer's own 0.5 combine; the group's
+	// offset adds to the layer's own.
+	deco := v.layers[1]
+	if deco.node.Visible || deco.node.Alpha != 0.25 {
+		t.Errorf("deco node visible=%v alpha=%v", deco.node.Visible, deco.node.Alpha)
+	}
+	if deco.node.X != 4 || deco.node.Y != 2 {
+		t.Errorf("deco offset = (%v, %v), want (4, 2)", deco.node.X, deco.node.Y)
+	}
+	if ground.node.X != 0 || ground.node.Y != 0 {
+		t.Errorf("ground offset = (%v, %v), want (0, 0)", ground.node.X, ground.node.Y)
+	}
+	updateWorldTransform(v.Node(), identityTransform, 1.0, false, false)
+	if col, row, gid := deco.TileAt(4+16+1, 2+1); col != 1 || row != 0 || gid != 4 {
+		t.Errorf("deco TileAt = (%d, %d) gid %d, want (1, 0) gid 4", col, row, gid)
+	}
+
+	children := v.Node().Children()
+	if len(children) != 4 {
+		t.Fatalf("viewport children = %d, want 4", len(children))
+	}
+	things := children[2]
+	if things.X != 4 || things.Alpha != 0.5 || len(things.Children()) != 3 {
+		t.Fatalf("things container = %+v", things)
+	}
+	crate := things.Children()[1]
+	if crate.Type != NodeTypeSprite || crate.CustomImage() == nil || crate.PivotY != 16 {
+		t.Errorf("tile object node: type=%d image=%v pivotY=%v", crate.Type, crate.CustomImage() != nil, crate.PivotY)
+	}
+	if o, ok := crate.UserData.(*TiledObject); !ok || o.Name != "crate" {
+		t.Errorf("tile object UserData = %v", crate.UserData)
+	}
+	if zone := things.Children()[2]; zone.Type != NodeTypeContainer || zone.Rotation < 1.57 || zone.Rotation > 1.58 {
+		t.Errorf("zone node = type %d rotation %v", zone.Type, zone.Rotation)
+	}
+	if sky := children[3]; sky.CustomImage() == nil {
+		t.Error("image layer should display its image")
+	}
+}
+
+func TestTiledMapNewViewport_MixedTilesets(t *testing.T) {
+	m, err := LoadTiledMap(testTiledFS(t), "maps/town.tmj")
+	if err != nil {
+		t.Fatalf("LoadTiledMap: %v", err)
+	}
+	v, err := m.NewViewport("town")
+	if err != nil {
+		t.Fatalf("NewViewport: %v", err)
+	}
+	// "ground" only uses the first tileset; "packed" only the second.
+	if n := len(v.layers[0].pages); n != 1 {
+		t.Errorf("ground pages = %d, want 1", n)
+	}
+	if p := v.layers[1].pages; len(p) != 1 || p[0].image != m.Tilesets[1].Image {
+		t.Errorf("packed layer should use only the props tileset")
+	}
+
+	m.Layer("ground").Data[0] = 99
+	if _, err := m.NewViewport("town"); err == nil {
+		t.Error("expected error for a GID outside every tileset")
+	}
+}
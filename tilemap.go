@@ -24,6 +24,21 @@ type AnimFrame struct {
 	Duration int    // milliseconds
 }
 
+// TileSet maps a contiguous range of GIDs onto regions of one atlas image,
+// the way a Tiled tileset does. A layer may draw from several tile sets;
+// consecutive tiles on the same image are batched into one draw call.
+//
+// TileWidth and TileHeight give the size tiles are drawn at. Zero uses the
+// viewport's tile size. Tiles of another size are drawn at their own size
+// with their bottom-left corner on the cell's, as in Tiled.
+type TileSet struct {
+	FirstGID   uint32          // GID of Regions[0]
+	Regions    []TextureRegion // indexed by GID - FirstGID
+	Image      *ebiten.Image   // atlas page the regions refer to
+	TileWidth  int
+	TileHeight int
+}
+
 // uvOrder defines vertex UV assignment for each combination of flip flags.
 // Indexed by 3-bit flag value: (flipH << 2) | (flipV << 1) | flipD.
 // Each entry contains 4 corner indices: TL=0, TR=1, BL=2, BR=3.
@@ -60,6 +75,19 @@ type TileMapViewport struct {
 	// keep buffered. Prevents pop-in during fast pans. Default 2.
 	MarginTiles int
 
+	// Orientation selects the grid projection. Default TileOrthogonal.
+	// Changing it requires InvalidateBuffer on every layer.
+	Orientation TileOrientation
+	// StaggerX staggers columns instead of rows (staggered and hexagonal
+	// grids, Tiled's staggeraxis="x").
+	StaggerX bool
+	// StaggerEven shifts even rows/columns instead of odd ones (Tiled's
+	// staggerindex="even").
+	StaggerEven bool
+	// HexSideLength is the length in pixels of the flat hexagon side along
+	// the stagger axis (hexagonal grids only).
+	HexSideLength int
+
 	// Camera binding. nil means use scene.Cameras()[0].
 	camera *Camera
 
@@ -71,25 +99,28 @@ type TileMapViewport struct {
 }
 
 // TileMapLayer is a single layer of tile data. It stores the raw integer grid
-// and owns a geometry buffer that is rebuilt on tile boundary crossings and
-// transformed each frame.
+// and owns one geometry buffer per atlas image, rebuilt on tile boundary
+// crossings and transformed each frame.
 type TileMapLayer struct {
 	node   *Node    // container node in the scene graph (created by AddTileLayer)
 	data   []uint32 // row-major tile GIDs, len = width * height
 	width  int      // map width in tiles
 	height int      // map height in tiles
 
-	// Geometry buffer — preallocated to max visible tiles.
-	vertices  []ebiten.Vertex // 4 vertices per tile, len = bufferCapacity * 4
-	indices   []uint16        // 6 indices per tile, len = bufferCapacity * 6
-	tileCount int             // number of active (non-empty) tiles in the buffer
-
-	// World-space positions for each tile slot (used for CPU transform each frame).
-	worldX []float32 // per-tile-slot world X (len = bufferCapacity)
-	worldY []float32 // per-tile-slot world Y (len = bufferCapacity)
+	// Geometry buffers, one per distinct atlas image.
+	pages []tilePage
+	// runs lists the buffered tiles in drawing order as consecutive slots
+	// of one page, so tiles from different images keep Tiled's row order.
+	runs []tileRun
 
 	// Tile region lookup: GID -> TextureRegion. Populated from atlas/tileset data.
 	regions []TextureRegion // indexed by GID (after masking flags)
+	// regionPage maps each GID to its index in pages. nil means every GID
+	// uses pages[0] (the single-image layer created by AddTileLayer).
+	regionPage []uint16
+	// tileSize holds each GID's drawn width and height. nil, or a zero
+	// entry, means the viewport's tile size.
+	tileSize [][2]float32
 
 	// Tracking which grid columns/rows the buffer currently covers.
 	bufStartCol int
@@ -101,14 +132,72 @@ type TileMapLayer struct {
 	// Animation definitions for this layer's tileset.
 	anims map[uint32][]AnimFrame // base GID -> animation frames (nil if no animations)
 
-	// The atlas page (ebiten.Image) used for DrawTriangles.
-	// All tiles in a layer must come from the same atlas page.
-	atlasImage *ebiten.Image
+	// Collision shapes used by SweepAABB.
+	collisions map[uint32]TileCollision // base GID -> shape (nil if none)
+
+	// Terrain grid and rules for PaintTerrain (nil unless SetAutotile).
+	autotile *autotileState
+
+	// DepthSort splits the layer's draw calls per screen row and sets each
+	// call's GlobalOrder to the row's bottom edge in world pixels (the
+	// node's own GlobalOrder is ignored). Nodes in the same RenderLayer that
+	// set GlobalOrder to their foot Y then draw in front of or behind tiles
+	// correctly, which isometric and staggered maps need for tall tiles.
+	DepthSort bool
+
+	// OnTileClick fires when a pointer is pressed and released over the
+	// same non-empty tile. OnTileEnter and OnTileLeave fire as the hovered
+	// tile changes. The layer node becomes Interactable once one of them is
+	// set (on the next update); the viewport node must be Interactable too.
+	// The layer node's own pointer callbacks stay free for other uses.
+	OnTileClick func(TileContext)
+	OnTileEnter func(TileContext)
+	OnTileLeave func(TileContext)
+
+	// Tile pointer state.
+	pressCol, pressRow int
+	hoverCol, hoverRow int
+	hovering           bool
+
+	// LoadChunk returns the row-major GIDs of chunk (cx, cy) for layers
+	// created by AddChunkedLayer; chunk (cx, cy) covers columns
+	// cx*chunkW to (cx+1)*chunkW-1. Returning nil leaves the chunk empty.
+	// UnloadChunk receives each non-empty chunk evicted from the buffered
+	// range, with any SetTile edits, so it can be saved.
+	LoadChunk   func(cx, cy int) []uint32
+	UnloadChunk func(cx, cy int, data []uint32)
+
+	// Chunk streaming state (nil unless created by AddChunkedLayer).
+	chunks *tileChunks
 
 	// Parent viewport reference (for accessing tile dimensions, camera, etc.)
 	viewport *TileMapViewport
 }
 
+// tilePage is the geometry buffer for the buffered tiles that sample one
+// atlas image. Each page is drawn with its own DrawTriangles calls.
+type tilePage struct {
+	// The atlas page (ebiten.Image) used for DrawTriangles.
+	image *ebiten.Image
+
+	// Geometry buffer — preallocated to max visible tiles.
+	vertices  []ebiten.Vertex // 4 vertices per tile, len = bufferCapacity * 4
+	indices   []uint16        // 6 indices per tile, len = bufferCapacity * 6
+	tileCount int             // number of active (non-empty) tiles in the buffer
+
+	// World-space positions for each tile slot (used for CPU transform each frame).
+	worldX []float32    // per-tile-slot world X (len = bufferCapacity)
+	worldY []float32    // per-tile-slot world Y (len = bufferCapacity)
+	sizes  [][2]float32 // per-tile-slot drawn width and height (len = bufferCapacity)
+	gids   []uint32     // per-tile-slot GID with flags, for animation (len = bufferCapacity)
+}
+
+// tileRun is a span of consecutively placed tiles on one page.
+type tileRun struct {
+	page       int
+	start, end int // slot range [start, end)
+}
+
 // NewTileMapViewport creates a new tilemap viewport node with the given tile
 // dimensions. The viewport is a container node that should be added to the
 // scene graph.
@@ -139,13 +228,27 @@ func (v *TileMapViewport) SetCamera(cam *Camera) {
 // child of the viewport. All regions must come from the provided atlas image.
 // Returns the layer for further configuration (RenderLayer, animations, etc.).
 func (v *TileMapViewport) AddTileLayer(name string, w, h int, data []uint32, regions []TextureRegion, atlasImage *ebiten.Image) *TileMapLayer {
+	layer := v.newTileLayer(name, w, h, data)
+	layer.regions = regions
+	layer.pages = []tilePage{{image: atlasImage}}
+	return layer
+}
+
+// AddTileLayerSets creates a tile layer whose tiles come from several tile
+// sets, each covering a GID range on its own atlas image. Tiles are drawn
+// in row order, batching each run of tiles that share an image.
+func (v *TileMapViewport) AddTileLayerSets(name string, w, h int, data []uint32, sets []TileSet) *TileMapLayer {
+	layer := v.newTileLayer(name, w, h, data)
+	layer.SetTileSets(sets)
+	return layer
+}
+
+func (v *TileMapViewport) newTileLayer(name string, w, h int, data []uint32) *TileMapLayer {
 	layer := &TileMapLayer{
 		node:        NewContainer(name),
 		data:        data,
 		width:       w,
 		height:      h,
-		regions:     regions,
-		atlasImage:  atlasImage,
 		viewport:    v,
 		bufDirty:    true,
 		bufStartCol: -1, // force initial rebuild
@@ -154,12 +257,26 @@ func (v *TileMapViewport) AddTileLayer(name string, w, h int, data []uint32, reg
 
 	// Set up customEmit on the layer's node.
 	layer.node.customEmit = layer.emitCommands
+	layer.initTileInput()
 
 	v.node.AddChild(layer.node)
 	v.layers = append(v.layers, layer)
 	return layer
 }
 
+// localBounds converts a world-space rectangle to the bounding rectangle of
+// its corners in the layer node's local space.
+func (l *TileMapLayer) localBounds(r Rect) Rect {
+	x0, y0 := math.Inf(1), math.Inf(1)
+	x1, y1 := math.Inf(-1), math.Inf(-1)
+	for _, p := range [4]Vec2{{r.X, r.Y}, {r.X + r.Width, r.Y}, {r.X, r.Y + r.Height}, {r.X + r.Width, r.Y + r.Height}} {
+		lx, ly := l.node.WorldToLocal(p.X, p.Y)
+		x0, y0 = math.Min(x0, lx), math.Min(y0, ly)
+		x1, y1 = math.Max(x1, lx), math.Max(y1, ly)
+	}
+	return Rect{X: x0, Y: y0, Width: x1 - x0, Height: y1 - y0}
+}
+
 // AddChild adds a regular node container as a child of the viewport
 // (for sandwich layers: NPCs, items, effects, etc.).
 func (v *TileMapViewport) AddChild(child *Node) {
@@ -169,10 +286,18 @@ func (v *TileMapViewport) AddChild(child *Node) {
 // SetTile updates a single tile in the given layer. If the tile is currently
 // visible in the buffer, its vertex UVs are updated immediately.
 func (l *TileMapLayer) SetTile(col, row int, newGID uint32) {
-	if col < 0 || col >= l.width || row < 0 || row >= l.height {
-		return
+	if l.chunks != nil {
+		data, i := l.chunkCell(col, row)
+		if data == nil {
+			return
+		}
+		data[i] = newGID
+	} else {
+		if col < 0 || col >= l.width || row < 0 || row >= l.height {
+			return
+		}
+		l.data[row*l.width+col] = newGID
 	}
-	l.data[row*l.width+col] = newGID
 
 	// If tile is within the current buffer range, rebuild.
 	if col >= l.bufStartCol && col < l.bufStartCol+l.bufCols &&
@@ -186,6 +311,9 @@ func (l *TileMapLayer) SetData(data []uint32, w, h int) {
 	l.data = data
 	l.width = w
 	l.height = h
+	if l.autotile != nil {
+		l.deriveTerrain()
+	}
 	l.InvalidateBuffer()
 }
 
@@ -196,6 +324,55 @@ func (l *TileMapLayer) InvalidateBuffer() {
 	l.bufStartRow = -1
 }
 
+// SetTileSets replaces the layer's tile sources and forces a full buffer
+// rebuild. Where GID ranges overlap, later sets win. Sets that share an
+// image share a geometry buffer.
+func (l *TileMapLayer) SetTileSets(sets []TileSet) {
+	n := 0
+	for _, ts := range sets {
+		n = max(n, int(ts.FirstGID)+len(ts.Regions))
+	}
+	l.regions = make([]TextureRegion, n)
+	l.regionPage = make([]uint16, n)
+	l.tileSize = nil
+	l.pages = l.pages[:0]
+	for _, ts := range sets {
+		page := l.pageFor(ts.Image)
+		copy(l.regions[ts.FirstGID:], ts.Regions)
+		for i := range ts.Regions {
+			l.regionPage[int(ts.FirstGID)+i] = page
+		}
+		if ts.TileWidth > 0 && ts.TileHeight > 0 {
+			if l.tileSize == nil {
+				l.tileSize = make([][2]float32, n)
+			}
+			for i := range ts.Regions {
+				l.tileSize[int(ts.FirstGID)+i] = [2]float32{float32(ts.TileWidth), float32(ts.TileHeight)}
+			}
+		}
+	}
+	l.InvalidateBuffer()
+}
+
+// pageFor returns the index of the page drawing img, adding one if needed.
+func (l *TileMapLayer) pageFor(img *ebiten.Image) uint16 {
+	for i := range l.pages {
+		if l.pages[i].image == img {
+			return uint16(i)
+		}
+	}
+	l.pages = append(l.pages, tilePage{image: img})
+	return uint16(len(l.pages) - 1)
+}
+
+// pageOf returns the page index for a masked GID.
+func (l *TileMapLayer) pageOf(tileID uint32) int {
+	if l.regionPage == nil {
+		return 0
+	}
+	return int(l.regionPage[tileID])
+}
+
 // SetAnimations sets the animation definitions for this layer.
 // The map is keyed by base GID (no flag bits).
 func (l *TileMapLayer) SetAnimations(anims map[uint32][]AnimFrame) {
@@ -215,27 +392,40 @@ func (v *TileMapViewport) update(dt float64) {
 	}
 
 	bounds := cam.VisibleBounds()
-	tw := float64(v.TileWidth)
-	th := float64(v.TileHeight)
 	zoom := v.MaxZoomOut
 	if zoom <= 0 {
 		zoom = 1.0
 	}
 
+	// Compute buffer dimensions based on viewport and zoom.
+	// +2 accounts for partial tiles visible at both edges when the
+	// camera is not tile-aligned.
+	spanCols, spanRows := v.bufferSpan(cam.Viewport.Width/zoom, cam.Viewport.Height/zoom)
+	bufCols := spanCols + 2 + 2*v.MarginTiles
+	bufRows := spanRows + 2 + 2*v.MarginTiles
+
 	for _, layer := range v.layers {
 		if !layer.node.Visible {
 			continue
 		}
-
-		// Compute buffer dimensions based on viewport and zoom.
-		// +2 accounts for partial tiles visible at both edges when the
-		// camera is not tile-aligned.
-		bufCols := int(math.Ceil(cam.Viewport.Width/tw/zoom)) + 2 + 2*v.MarginTiles
-		bufRows := int(math.Ceil(cam.Viewport.Height/th/zoom)) + 2 + 2*v.MarginTiles
-
-		// Compute visible tile range.
-		startCol := int(math.Floor(bounds.X/tw)) - v.MarginTiles
-		startRow := int(math.Floor(bounds.Y/th)) - v.MarginTiles
+		layer.syncTileInput()
+
+		// Compute the visible tile range in the layer's own space, so moved
+		// or offset layers buffer the tiles actually on screen.
+		minCol, minRow := v.minTile(layer.localBounds(bounds))
+		startCol := minCol - v.MarginTiles
+		startRow := minRow - v.MarginTiles
+
+		if layer.chunks != nil {
+			// Chunked layers are unbounded; stream chunks on boundary
+			// crossings instead of clamping.
+			if layer.bufDirty || startCol != layer.bufStartCol || startRow != layer.bufStartRow {
+				layer.streamChunks(startCol, startRow, bufCols, bufRows)
+				layer.ensureBuffer(bufCols, bufRows)
+				layer.rebuildBuffer(startCol, startRow, bufCols, bufRows)
+			}
+			continue
+		}
 
 		// Clamp to valid range.
 		if startCol < 0 {
@@ -278,32 +468,42 @@ func (v *TileMapViewport) update(dt float64) {
 	}
 }
 
-// ensureBuffer grows the geometry buffer if needed.
+// ensureBuffer grows each page's geometry buffer if needed. Every page is
+// sized for the whole buffered range, since any page may supply all tiles.
 func (l *TileMapLayer) ensureBuffer(cols, rows int) {
-	cap := cols * rows
-	if cap <= len(l.worldX) {
+	for i := range l.pages {
+		l.pages[i].ensure(cols * rows)
+	}
+}
+
+// ensure grows the page's geometry buffer to hold cap tiles.
+func (p *tilePage) ensure(cap int) {
+	if cap <= len(p.worldX) {
 		return
 	}
 
-	l.worldX = make([]float32, cap)
-	l.worldY = make([]float32, cap)
-	l.vertices = make([]ebiten.Vertex, cap*4)
+	p.worldX = make([]float32, cap)
+	p.worldY = make([]float32, cap)
+	p.sizes = make([][2]float32, cap)
+	p.gids = make([]uint32, cap)
+	p.vertices = make([]ebiten.Vertex, cap*4)
 
 	// Build index buffer (topology never changes).
-	l.indices = make([]uint16, cap*6)
+	p.indices = make([]uint16, cap*6)
 	for i := 0; i < cap; i++ {
 		base := uint16(i * 4)
 		off := i * 6
-		l.indices[off+0] = base + 0
-		l.indices[off+1] = base + 1
-		l.indices[off+2] = base + 2
-		l.indices[off+3] = base + 1
-		l.indices[off+4] = base + 3
-		l.indices[off+5] = base + 2
+		p.indices[off+0] = base + 0
+		p.indices[off+1] = base + 1
+		p.indices[off+2] = base + 2
+		p.indices[off+3] = base + 1
+		p.indices[off+4] = base + 3
+		p.indices[off+5] = base + 2
 	}
 }
 
 // rebuildBuffer fills the vertex buffer with tile data for the given range.
+// Tiles are written back to front for the viewport's orientation.
 func (l *TileMapLayer) rebuildBuffer(startCol, startRow, bufCols, bufRows int) {
 	l.bufStartCol = startCol
 	l.bufStartRow = startRow
@@ -311,51 +511,88 @@ func (l *TileMapLayer) rebuildBuffer(startCol, startRow, bufCols, bufRows int) {
 	l.bufRows = bufRows
 	l.bufDirty = false
 
-	tw := float32(l.viewport.TileWidth)
-	th := float32(l.viewport.TileHeight)
-
-	tileCount := 0
-	for br := 0; br < bufRows; br++ {
-		row := startRow + br
-		if row < 0 || row >= l.height {
-			continue
+	for i := range l.pages {
+		l.pages[i].tileCount = 0
+	}
+	l.runs = l.runs[:0]
+
+	v := l.viewport
+	switch {
+	case v.Orientation == TileIsometric:
+		// Diagonals (constant col+row) share a screen row; walk them top to
+		// bottom, left to right within each.
+		for d := 0; d < bufCols+bufRows-1; d++ {
+			for bc := max(0, d-bufRows+1); bc <= min(d, bufCols-1); bc++ {
+				l.placeTile(startCol+bc, startRow+d-bc)
+			}
 		}
-		rowOffset := row * l.width
-		for bc := 0; bc < bufCols; bc++ {
-			col := startCol + bc
-			if col < 0 || col >= l.width {
-				continue
+	case v.Orientation != TileOrthogonal && v.StaggerX:
+		// Unshifted columns sit half a tile above shifted ones.
+		for br := 0; br < bufRows; br++ {
+			for pass := 0; pass < 2; pass++ {
+				for bc := 0; bc < bufCols; bc++ {
+					col := startCol + bc
+					if v.staggered(col) == (pass == 0) {
+						continue
+					}
+					l.placeTile(col, startRow+br)
+				}
 			}
-
-			gid := l.data[rowOffset+col]
-			if gid == 0 {
-				continue // empty tile
+		}
+	default:
+		for br := 0; br < bufRows; br++ {
+			for bc := 0; bc < bufCols; bc++ {
+				l.placeTile(startCol+bc, startRow+br)
 			}
+		}
+	}
+}
 
-			flags := gid & tileFlagMask
-			tileID := gid &^ tileFlagMask
-
-			if int(tileID) >= len(l.regions) {
-				continue // invalid GID
-			}
-			region := l.regions[tileID]
+// placeTile appends the tile at (col, row) to its page's buffer. Empty,
+// invalid and out-of-range cells are skipped.
+func (l *TileMapLayer) placeTile(col, row int) {
+	gid := l.GetTile(col, row)
+	if gid == 0 {
+		return // empty tile
+	}
 
-			// Store world position for per-frame transform.
-			l.worldX[tileCount] = float32(col) * tw
-			l.worldY[tileCount] = float32(row) * th
+	flags := gid & tileFlagMask
+	tileID := gid &^ tileFlagMask
 
-			// Set UV coordinates from TextureRegion with flip flags.
-			setTileUVs(l.vertices[tileCount*4:], region, flags, tw, th)
+	if int(tileID) >= len(l.regions) {
+		return // invalid GID
+	}
+	region := l.regions[tileID]
+	pi := l.pageOf(tileID)
+	p := &l.pages[pi]
+	i := p.tileCount
+
+	// Store world position for per-frame transform.
+	wx, wy := l.viewport.tileOrigin(col, row)
+	p.worldX[i] = float32(wx)
+	p.worldY[i] = float32(wy)
+	p.gids[i] = gid
+
+	// Set UV coordinates from TextureRegion with flip flags.
+	tw := float32(l.viewport.TileWidth)
+	th := float32(l.viewport.TileHeight)
+	p.sizes[i] = [2]float32{tw, th}
+	if l.tileSize != nil && l.tileSize[tileID][0] > 0 {
+		p.sizes[i] = l.tileSize[tileID]
+	}
+	setTileUVs(p.vertices[i*4:], region, flags)
 
-			tileCount++
-		}
+	p.tileCount++
+	if n := len(l.runs); n > 0 && l.runs[n-1].page == pi {
+		l.runs[n-1].end = p.tileCount
+	} else {
+		l.runs = append(l.runs, tileRun{page: pi, start: i, end: p.tileCount})
 	}
-	l.tileCount = tileCount
 }
 
 // setTileUVs sets the UV (SrcX/SrcY) coordinates for 4 vertices of a tile,
 // applying flip flags via the lookup table.
-func setTileUVs(verts []ebiten.Vertex, region TextureRegion, flags uint32, tw, th float32) {
+func setTileUVs(verts []ebiten.Vertex, region TextureRegion, flags uint32) {
 	// Source UV corners from the TextureRegion.
 	sx := float32(region.X)
 	sy := float32(region.Y)
@@ -392,12 +629,9 @@ func setTileUVs(verts []ebiten.Vertex, region TextureRegion, flags uint32, tw, t
 
 // emitCommands is the customEmit callback for a TileMapLayer node.
 // It transforms vertex positions from world to screen space and emits
-// CommandTilemap commands into the scene's command pipeline.
+// CommandTilemap commands into the scene's command pipeline, one or more
+// per run of consecutive tiles on the same page.
 func (l *TileMapLayer) emitCommands(s *Scene, treeOrder *int) {
-	if l.tileCount == 0 || l.atlasImage == nil {
-		return
-	}
-
 	// Get the accumulated color from the node's parent chain.
 	r := float32(l.node.Color.R * l.node.worldAlpha)
 	g := float32(l.node.Color.G * l.node.worldAlpha)
@@ -409,23 +643,77 @@ func (l *TileMapLayer) emitCommands(s *Scene, treeOrder *int) {
 	cg := g * a
 	cb := b * a
 
-	tw := float32(l.viewport.TileWidth)
 	th := float32(l.viewport.TileHeight)
 
+	// Tile positions are in the layer node's local space.
+	vt := multiplyAffine(s.viewTransform, l.node.worldTransform)
+	nodeY := float32(l.node.worldTransform[5])
+
+	for pi := range l.pages {
+		if p := &l.pages[pi]; p.tileCount > 0 && p.image != nil {
+			p.transform(vt, th, cr, cg, cb, a)
+		}
+	}
+
+	// Emit runs in placement order, so overlapping tiles from different
+	// pages stack as they would in one batch. Runs are split at
+	// maxTilesPerDraw boundaries and, when depth sorting, wherever the
+	// screen row changes.
+	for _, run := range l.runs {
+		p := &l.pages[run.page]
+		if p.image == nil {
+			continue
+		}
+		for offset := run.start; offset < run.end; {
+			end := min(offset+maxTilesPerDraw, run.end)
+			order := l.node.GlobalOrder
+			if l.DepthSort {
+				y := p.worldY[offset]
+				for i := offset + 1; i < end; i++ {
+					if p.worldY[i] != y {
+						end = i
+						break
+					}
+				}
+				order = int(nodeY + y + th)
+			}
+			batchTiles := end - offset
+
+			*treeOrder++
+			s.commands = append(s.commands, RenderCommand{
+				Type:         CommandTilemap,
+				RenderLayer:  l.node.RenderLayer,
+				GlobalOrder:  order,
+				treeOrder:    *treeOrder,
+				BlendMode:    l.node.BlendMode,
+				tilemapVerts: p.vertices[offset*4 : end*4],
+				tilemapInds:  p.indices[:batchTiles*6],
+				tilemapImage: p.image,
+			})
+			offset = end
+		}
+	}
+}
+
+// transform writes screen-space positions and the premultiplied tint into
+// the page's active tile vertices. th is the grid's tile height.
+func (p *tilePage) transform(vt [6]float64, th, cr, cg, cb, a float32) {
 	// View transform for world-to-screen conversion.
-	vt := s.viewTransform
 	va, vb := float32(vt[0]), float32(vt[1])
 	vc, vd := float32(vt[2]), float32(vt[3])
 	vtx, vty := float32(vt[4]), float32(vt[5])
 
-	// Compute tile screen dimensions (for axis-aligned cameras).
-	tileScreenW := va * tw
-	tileScreenH := vd * th
-
 	// Transform all active tile vertices.
-	for i := 0; i < l.tileCount; i++ {
-		wx := l.worldX[i]
-		wy := l.worldY[i]
+	for i := 0; i < p.tileCount; i++ {
+		// Tiles sit on the bottom-left corner of their cell, so tiles
+		// taller than the grid extend upward.
+		size := p.sizes[i]
+		wx := p.worldX[i]
+		wy := p.worldY[i] + th - size[1]
+
+		// Tile screen dimensions (for axis-aligned cameras).
+		tileScreenW := va * size[0]
+		tileScreenH := vd * size[1]
 
 		// Apply view transform.
 		screenX := va*wx + vc*wy + vtx
@@ -433,113 +721,83 @@ func (l *TileMapLayer) emitCommands(s *Scene, treeOrder *int) {
 
 		vi := i * 4
 		// Top-left
-		l.vertices[vi+0].DstX = screenX
-		l.vertices[vi+0].DstY = screenY
+		p.vertices[vi+0].DstX = screenX
+		p.vertices[vi+0].DstY = screenY
 		// Top-right
-		l.vertices[vi+1].DstX = screenX + tileScreenW
-		l.vertices[vi+1].DstY = screenY
+		p.vertices[vi+1].DstX = screenX + tileScreenW
+		p.vertices[vi+1].DstY = screenY
 		// Bottom-left
-		l.vertices[vi+2].DstX = screenX
-		l.vertices[vi+2].DstY = screenY + tileScreenH
+		p.vertices[vi+2].DstX = screenX
+		p.vertices[vi+2].DstY = screenY + tileScreenH
 		// Bottom-right
-		l.vertices[vi+3].DstX = screenX + tileScreenW
-		l.vertices[vi+3].DstY = screenY + tileScreenH
+		p.vertices[vi+3].DstX = screenX + tileScreenW
+		p.vertices[vi+3].DstY = screenY + tileScreenH
 
 		// Apply tint color.
-		l.vertices[vi+0].ColorR = cr
-		l.vertices[vi+0].ColorG = cg
-		l.vertices[vi+0].ColorB = cb
-		l.vertices[vi+0].ColorA = a
-		l.vertices[vi+1].ColorR = cr
-		l.vertices[vi+1].ColorG = cg
-		l.vertices[vi+1].ColorB = cb
-		l.vertices[vi+1].ColorA = a
-		l.vertices[vi+2].ColorR = cr
-		l.vertices[vi+2].ColorG = cg
-		l.vertices[vi+2].ColorB = cb
-		l.vertices[vi+2].ColorA = a
-		l.vertices[vi+3].ColorR = cr
-		l.vertices[vi+3].ColorG = cg
-		l.vertices[vi+3].ColorB = cb
-		l.vertices[vi+3].ColorA = a
-	}
-
-	// Emit commands, splitting at maxTilesPerDraw boundaries.
-	totalTiles := l.tileCount
-	for offset := 0; offset < totalTiles; offset += maxTilesPerDraw {
-		end := offset + maxTilesPerDraw
-		if end > totalTiles {
-			end = totalTiles
-		}
-		batchTiles := end - offset
-
-		*treeOrder++
-		s.commands = append(s.commands, RenderCommand{
-			Type:         CommandTilemap,
-			RenderLayer:  l.node.RenderLayer,
-			GlobalOrder:  l.node.GlobalOrder,
-			treeOrder:    *treeOrder,
-			BlendMode:    l.node.BlendMode,
-			tilemapVerts: l.vertices[offset*4 : end*4],
-			tilemapInds:  l.indices[:batchTiles*6],
-			tilemapImage: l.atlasImage,
-		})
+		p.vertices[vi+0].ColorR = cr
+		p.vertices[vi+0].ColorG = cg
+		p.vertices[vi+0].ColorB = cb
+		p.vertices[vi+0].ColorA = a
+		p.vertices[vi+1].ColorR = cr
+		p.vertices[vi+1].ColorG = cg
+		p.vertices[vi+1].ColorB = cb
+		p.vertices[vi+1].ColorA = a
+		p.vertices[vi+2].ColorR = cr
+		p.vertices[vi+2].ColorG = cg
+		p.vertices[vi+2].ColorB = cb
+		p.vertices[vi+2].ColorA = a
+		p.vertices[vi+3].ColorR = cr
+		p.vertices[vi+3].ColorG = cg
+		p.vertices[vi+3].ColorB = cb
+		p.vertices[vi+3].ColorA = a
 	}
 }
 
 // updateAnimations scans layers for animated tiles and updates their UVs.
+// A frame whose GID lives on a different image than the tile's page is
+// skipped, since swapping pages requires a buffer rebuild.
 func (v *TileMapViewport) updateAnimations() {
 	for _, layer := range v.layers {
 		if layer.anims == nil || !layer.node.Visible {
 			continue
 		}
 
-		tw := float32(v.TileWidth)
-		th := float32(v.TileHeight)
-
-		for i := 0; i < layer.tileCount; i++ {
-			// Recover the grid position from world coordinates.
-			col := int(layer.worldX[i] / tw)
-			row := int(layer.worldY[i] / th)
-			if col < 0 || col >= layer.width || row < 0 || row >= layer.height {
-				continue
-			}
+		for pi := range layer.pages {
+			p := &layer.pages[pi]
+			for i := 0; i < p.tileCount; i++ {
+				gid := p.gids[i]
+				flags := gid & tileFlagMask
+				baseGID := gid &^ tileFlagMask
 
-			gid := layer.data[row*layer.width+col]
-			if gid == 0 {
-				continue
-			}
-			flags := gid & tileFlagMask
-			baseGID := gid &^ tileFlagMask
-
-			frames, ok := layer.anims[baseGID]
-			if !ok || len(frames) == 0 {
-				continue
-			}
+				frames, ok := layer.anims[baseGID]
+				if !ok || len(frames) == 0 {
+					continue
+				}
 
-			// Determine current animation frame.
-			totalDuration := 0
-			for _, f := range frames {
-				totalDuration += f.Duration
-			}
-			if totalDuration == 0 {
-				continue
-			}
+				// Determine current animation frame.
+				totalDuration := 0
+				for _, f := range frames {
+					totalDuration += f.Duration
+				}
+				if totalDuration == 0 {
+					continue
+				}
 
-			elapsed := v.animElapsed % totalDuration
-			currentGID := frames[0].GID
-			acc := 0
-			for _, f := range frames {
-				acc += f.Duration
-				if elapsed < acc {
-					currentGID = f.GID
-					break
+				elapsed := v.animElapsed % totalDuration
+				currentGID := frames[0].GID
+				acc := 0
+				for _, f := range frames {
+					acc += f.Duration
+					if elapsed < acc {
+						currentGID = f.GID
+						break
+					}
 				}
-			}
 
-			if int(currentGID) < len(layer.regions) {
-				region := layer.regions[currentGID]
-				setTileUVs(layer.vertices[i*4:], region, flags, tw, th)
+				if int(currentGID) < len(layer.regions) && layer.pageOf(currentGID) == pi {
+					region := layer.regions[currentGID]
+					setTileUVs(p.vertices[i*4:], region, flags)
+				}
 			}
 		}
 	}
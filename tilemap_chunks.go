@@ -0,0 +1,117 @@
+package willow
+
+import "log"
+
+// chunkKey identifies a chunk by its chunk coordinates.
+type chunkKey struct {
+	x, y int
+}
+
+// tileChunks holds the loaded chunks of an unbounded layer.
+type tileChunks struct {
+	w, h   int                   // chunk size in tiles
+	loaded map[chunkKey][]uint32 // nil data marks an empty loaded chunk
+}
+
+// AddChunkedLayer creates an unbounded tile layer whose tiles are streamed
+// in chunkW×chunkH chunks through the layer's LoadChunk callback. As the
+// camera moves, the viewport loads the chunks overlapping the buffered
+// range and evicts the rest through UnloadChunk. Tile coordinates may be
+// negative. Autotiling is not supported on chunked layers.
+func (v *TileMapViewport) AddChunkedLayer(name string, chunkW, chunkH int, sets []TileSet) *TileMapLayer {
+	layer := v.newTileLayer(name, 0, 0, nil)
+	layer.chunks = &tileChunks{w: max(chunkW, 1), h: max(chunkH, 1), loaded: make(map[chunkKey][]uint32)}
+	layer.SetTileSets(sets)
+	return layer
+}
+
+// ChunkSize returns the layer's chunk size in tiles, or 0, 0 for layers not
+// created by AddChunkedLayer.
+func (l *TileMapLayer) ChunkSize() (w, h int) {
+	if l.chunks == nil {
+		return 0, 0
+	}
+	return l.chunks.w, l.chunks.h
+}
+
+// Chunk returns the data of the loaded chunk (cx, cy), or nil if it is not
+// loaded or empty.
+func (l *TileMapLayer) Chunk(cx, cy int) []uint32 {
+	if l.chunks == nil {
+		return nil
+	}
+	return l.chunks.loaded[chunkKey{cx, cy}]
+}
+
+// ReloadChunks evicts every loaded chunk through UnloadChunk so the
+// buffered range is requested again from LoadChunk on the next update.
+func (l *TileMapLayer) ReloadChunks() {
+	if l.chunks == nil {
+		return
+	}
+	for k, data := range l.chunks.loaded {
+		l.unloadChunk(k, data)
+	}
+	l.InvalidateBuffer()
+}
+
+// chunkCell returns the loaded chunk holding tile (col, row) and the
+// tile's index in it, or nil if that chunk is not loaded or empty.
+func (l *TileMapLayer) chunkCell(col, row int) ([]uint32, int) {
+	c := l.chunks
+	cx, cy := floorDiv(col, c.w), floorDiv(row, c.h)
+	data := c.loaded[chunkKey{cx, cy}]
+	if data == nil {
+		return nil, 0
+	}
+	return data, (row-cy*c.h)*c.w + (col - cx*c.w)
+}
+
+// streamChunks loads the chunks overlapping the tile range and evicts the
+// loaded chunks outside it.
+func (l *TileMapLayer) streamChunks(startCol, startRow, cols, rows int) {
+	c := l.chunks
+	cx0, cy0 := floorDiv(startCol, c.w), floorDiv(startRow, c.h)
+	cx1, cy1 := floorDiv(startCol+cols-1, c.w), floorDiv(startRow+rows-1, c.h)
+
+	for k, data := range c.loaded {
+		if k.x < cx0 || k.x > cx1 || k.y < cy0 || k.y > cy1 {
+			l.unloadChunk(k, data)
+		}
+	}
+	for cy := cy0; cy <= cy1; cy++ {
+		for cx := cx0; cx <= cx1; cx++ {
+			k := chunkKey{cx, cy}
+			if _, ok := c.loaded[k]; ok {
+				continue
+			}
+			var data []uint32
+			if l.LoadChunk != nil {
+				data = l.LoadChunk(cx, cy)
+			}
+			if data != nil && len(data) != c.w*c.h {
+				if globalDebug {
+					log.Printf("willow: layer %q chunk (%d, %d) has %d tiles, want %d", l.node.Name, cx, cy, len(data), c.w*c.h)
+				}
+				data = nil
+			}
+			c.loaded[k] = data
+		}
+	}
+}
+
+func (l *TileMapLayer) unloadChunk(k chunkKey, data []uint32) {
+	delete(l.chunks.loaded, k)
+	if l.UnloadChunk != nil && data != nil {
+		l.UnloadChunk(k.x, k.y, data)
+	}
+}
+
+// floorDiv divides rounding toward negative infinity.
+func floorDiv(a, b int) int {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
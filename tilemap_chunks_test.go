@@ -0,0 +1,125 @@
+package willow
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+func TestChunkedLayerStreaming(t *testing.T) {
+	// 64×64 camera over 16px tiles, 4×4-tile chunks (64px each).
+	_, v := newTestViewport(64, 64)
+	v.MarginTiles = 0
+	layer := v.AddChunkedLayer("world", 4, 4, []TileSet{{FirstGID: 1, Regions: testRegions(2), Image: ebiten.NewImage(32, 16)}})
+
+	loaded := map[chunkKey]int{}
+	var unloaded []chunkKey
+	layer.LoadChunk = func(cx, cy int) []uint32 {
+		loaded[chunkKey{cx, cy}]++
+		data := make([]uint32, 16)
+		for i := range data {
+			data[i] = 1
+		}
+		return data
+	}
+	layer.UnloadChunk = func(cx, cy int, data []uint32) {
+		unloaded = append(unloaded, chunkKey{cx, cy})
+	}
+
+	// Camera centered on the origin: tiles -3..2 buffered, chunks -1..0.
+	v.camera.X, v.camera.Y = 0, 0
+	v.camera.Invalidate()
+	v.update(0)
+	if len(loaded) != 4 {
+		t.Fatalf("loaded %d chunks, want 4: %v", len(loaded), loaded)
+	}
+	for _, k := range []chunkKey{{-1, -1}, {0, -1}, {-1, 0}, {0, 0}} {
+		if loaded[k] != 1 {
+			t.Errorf("chunk %v loaded %d times, want 1", k, loaded[k])
+		}
+	}
+	if gid := layer.GetTile(-4, -1); gid != 1 {
+		t.Errorf("GetTile(-4,-1) = %d, want 1", gid)
+	}
+	if n := layer.pages[0].tileCount; n != 36 {
+		t.Errorf("buffered tiles = %d, want 36", n)
+	}
+
+	// Editing a loaded chunk reaches UnloadChunk's data.
+	layer.SetTile(-1, -1, 2)
+	if d := layer.Chunk(-1, -1); d[15] != 2 {
+		t.Errorf("chunk (-1,-1) last tile = %d, want 2", d[15])
+	}
+
+	// Moving two chunks right evicts the left column and loads new ones.
+	v.camera.X = 128
+	v.camera.Invalidate()
+	v.update(0)
+	if len(unloaded) != 4 {
+		t.Errorf("unloaded %v, want all four old chunks", unloaded)
+	}
+	if loaded[chunkKey{1, 0}] != 1 || loaded[chunkKey{2, -1}] != 1 {
+		t.Errorf("new chunks not loaded: %v", loaded)
+	}
+	if layer.Chunk(-1, -1) != nil {
+		t.Error("evicted chunk still loaded")
+	}
+
+	// A further small move inside the same chunks loads nothing new.
+	before := len(loaded)
+	v.camera.X = 136
+	v.camera.Invalidate()
+	v.update(0)
+	if len(loaded) != before {
+		t.Errorf("loaded %d new chunks on a small move", len(loaded)-before)
+	}
+}
+
+func TestChunkedLayerBadChunkIsEmpty(t *testing.T) {
+	_, v := newTestViewport(64, 64)
+	layer := v.AddChunkedLayer("world", 4, 4, []TileSet{{FirstGID: 1, Regions: testRegions(2), Image: ebiten.NewImage(32, 16)}})
+	layer.LoadChunk = func(cx, cy int) []uint32 { return []uint32{1, 1} }
+	v.update(0)
+	if gid := layer.GetTile(0, 0); gid != 0 {
+		t.Errorf("GetTile = %d, want 0 for a wrongly sized chunk", gid)
+	}
+}
+
+func TestFloorDiv(t *testing.T) {
+	for _, c := range [][3]int{{7, 4, 1}, {-1, 4, -1}, {-4, 4, -1}, {-5, 4, -2}, {0, 4, 0}} {
+		if got := floorDiv(c[0], c[1]); got != c[2] {
+			t.Errorf("floorDiv(%d, %d) = %d, want %d", c[0], c[1], got, c[2])
+		}
+	}
+}
+
+func TestTiledInfiniteMapNewViewport(t *testing.T) {
+	ts := &TiledTileset{FirstGID: 1, TileWidth: 16, TileHeight: 16, TileCount: 2, Columns: 2, Image: ebiten.NewImage(32, 16)}
+	chunk := make([]uint32, 16*16)
+	chunk[0] = 2
+	m := &TiledMap{
+		Orientation: "orthogonal", Infinite: true, TileWidth: 16, TileHeight: 16,
+		Tilesets: []*TiledTileset{ts},
+		Layers: []*TiledLayer{{Name: "ground", Type: TiledLayerTile, Visible: true, Opacity: 1,
+			Chunks: []TiledChunk{{X: -16, Y: 0, Width: 16, Height: 16, Data: chunk}}}},
+	}
+	v, err := m.NewViewport("world")
+	if err != nil {
+		t.Fatalf("NewViewport: %v", err)
+	}
+	layer := v.layers[0]
+	if w, h := layer.ChunkSize(); w != 16 || h != 16 {
+		t.Fatalf("chunk size = %d×%d, want 16×16", w, h)
+	}
+	if data := layer.LoadChunk(-1, 0); len(data) != 256 || data[0] != 2 {
+		t.Errorf("LoadChunk(-1, 0) = %d tiles", len(data))
+	}
+	if layer.LoadChunk(0, 0) != nil {
+		t.Error("missing chunk should load as empty")
+	}
+
+	m.Layers[0].Chunks[0].X = -8
+	if _, err := m.NewViewport("world"); err == nil {
+		t.Error("unaligned chunk should fail")
+	}
+}
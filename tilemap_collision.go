@@ -0,0 +1,304 @@
+package willow
+
+import "math"
+
+// TileCollisionKind identifies how a tile blocks movement in SweepAABB.
+type TileCollisionKind uint8
+
+const (
+	// TileCollisionNone lets boxes pass through the tile (default).
+	TileCollisionNone TileCollisionKind = iota
+	// TileCollisionSolid blocks the whole tile from every side.
+	TileCollisionSolid
+	// TileCollisionOneWay blocks only boxes falling onto the tile's top edge.
+	TileCollisionOneWay
+	// TileCollisionSlope blocks boxes from above along a straight surface
+	// between SlopeLeft and SlopeRight. The box's bottom-center rests on it.
+	TileCollisionSlope
+	// TileCollisionPolygon blocks a convex polygon in tile-local pixels.
+	TileCollisionPolygon
+)
+
+// TileCollision is the collision shape of one tile GID.
+type TileCollision struct {
+	Kind TileCollisionKind
+
+	// SlopeLeft and SlopeRight are the surface heights in pixels above the
+	// tile's bottom edge at its left and right edges (TileCollisionSlope).
+	SlopeLeft, SlopeRight float64
+
+	// Polygon is a convex polygon in tile-local pixels
+	// (TileCollisionPolygon).
+	Polygon []Vec2
+}
+
+// TileContact is one tile a swept box touched.
+type TileContact struct {
+	Col, Row int
+	GID      uint32 // tile GID as stored, including flip flags
+	Normal   Vec2   // unit normal pointing from the tile toward the box
+}
+
+// TileSweepResult is the outcome of TileMapLayer.SweepAABB.
+type TileSweepResult struct {
+	X, Y     float64       // corrected top-left position of the box
+	OnGround bool          // a contact pushed the box up
+	Contacts []TileContact // tiles that blocked the move, in hit order
+}
+
+// tileEpsilon keeps boxes resting exactly on a tile edge from counting as
+// overlapping it.
+const tileEpsilon = 1e-6
+
+// SetCollisions sets the collision shapes for this layer's tiles, keyed by
+// base GID (no flag bits). Horizontally flipped tiles mirror their slopes
+// and polygons.
+func (l *TileMapLayer) SetCollisions(shapes map[uint32]TileCollision) {
+	l.collisions = shapes
+}
+
+// CollisionAt returns the collision shape of the tile at (col, row).
+// Empty cells and cells outside the layer have TileCollisionNone.
+func (l *TileMapLayer) CollisionAt(col, row int) TileCollision {
+	gid := l.GetTile(col, row)
+	if gid == 0 || l.collisions == nil {
+		return TileCollision{}
+	}
+	return l.collisions[gid&^tileFlagMask]
+}
+
+// SweepAABB moves the world-space box by (dx, dy) through the layer's
+// collision shapes and returns where it ends up. Movement is resolved one
+// axis at a time, X first, in steps of at most half a tile so fast boxes
+// cannot tunnel through thin walls. The layer is treated as an orthogonal
+// grid at the world origin.
+func (l *TileMapLayer) SweepAABB(box Rect, dx, dy float64) TileSweepResult {
+	tw := float64(l.viewport.TileWidth)
+	th := float64(l.viewport.TileHeight)
+	steps := int(math.Ceil(math.Max(math.Abs(dx)/(tw/2), math.Abs(dy)/(th/2))))
+	if steps < 1 {
+		steps = 1
+	}
+	sx := dx / float64(steps)
+	sy := dy / float64(steps)
+
+	var res TileSweepResult
+	for i := 0; i < steps; i++ {
+		if sx != 0 {
+			box.X += sx
+			l.resolveX(&box, sx, &res)
+		}
+		prevBottom := box.Y + box.Height
+		box.Y += sy
+		l.resolveY(&box, sy, prevBottom, &res)
+	}
+	res.X, res.Y = box.X, box.Y
+	return res
+}
+
+// tileSpan returns the tile range a box overlaps.
+func (l *TileMapLayer) tileSpan(b Rect) (c0, r0, c1, r1 int) {
+	tw := float64(l.viewport.TileWidth)
+	th := float64(l.viewport.TileHeight)
+	c0 = int(math.Floor((b.X + tileEpsilon) / tw))
+	r0 = int(math.Floor((b.Y + tileEpsilon) / th))
+	c1 = int(math.Ceil((b.X+b.Width-tileEpsilon)/tw)) - 1
+	r1 = int(math.Ceil((b.Y+b.Height-tileEpsilon)/th)) - 1
+	if l.chunks != nil {
+		return c0, r0, c1, r1
+	}
+	return max(c0, 0), max(r0, 0), min(c1, l.width-1), min(r1, l.height-1)
+}
+
+// resolveX pushes the box out of solid tiles and polygons after a
+// horizontal step of sx.
+func (l *TileMapLayer) resolveX(b *Rect, sx float64, res *TileSweepResult) {
+	tw := float64(l.viewport.TileWidth)
+	th := float64(l.viewport.TileHeight)
+
+	// A box standing on a slope walks onto the solid tile level with the
+	// slope's high edge instead of being stopped by that tile's side.
+	stepCol, stepRow := -1, -1
+	cx := b.X + b.Width/2
+	sc := int(math.Floor(cx / tw))
+	sr := int(math.Floor((b.Y + b.Height - tileEpsilon) / th))
+	if c := l.CollisionAt(sc, sr); c.Kind == TileCollisionSlope {
+		hl, hr := l.slopeHeights(sc, sr, c)
+		if hr >= th-tileEpsilon {
+			stepCol, stepRow = sc+1, sr
+		} else if hl >= th-tileEpsilon {
+			stepCol, stepRow = sc-1, sr
+		}
+	}
+
+	c0, r0, c1, r1 := l.tileSpan(*b)
+	for row := r0; row <= r1; row++ {
+		for col := c0; col <= c1; col++ {
+			c := l.CollisionAt(col, row)
+			switch c.Kind {
+			case TileCollisionSolid:
+				if col == stepCol && row == stepRow {
+					continue
+				}
+				if sx > 0 {
+					b.X = float64(col)*tw - b.Width
+					res.addContact(l, col, row, Vec2{X: -1})
+				} else {
+					b.X = float64(col+1) * tw
+					res.addContact(l, col, row, Vec2{X: 1})
+				}
+			case TileCollisionPolygon:
+				l.resolvePolygon(b, col, row, c, res)
+			}
+		}
+	}
+}
+
+// resolveY pushes the box out of tiles after a vertical step of sy.
+// prevBottom is the box's bottom edge before the step.
+func (l *TileMapLayer) resolveY(b *Rect, sy, prevBottom float64, res *TileSweepResult) {
+	tw := float64(l.viewport.TileWidth)
+	th := float64(l.viewport.TileHeight)
+	c0, r0, c1, r1 := l.tileSpan(*b)
+	for row := r0; row <= r1; row++ {
+		top := float64(row) * th
+		for col := c0; col <= c1; col++ {
+			c := l.CollisionAt(col, row)
+			switch c.Kind {
+			case TileCollisionSolid:
+				bottom := b.Y + b.Height
+				if bottom <= top+tileEpsilon || b.Y >= top+th-tileEpsilon {
+					continue
+				}
+				// Without vertical movement, leave by the shallower side.
+				down := sy < 0 || (sy == 0 && top+th-b.Y < bottom-top)
+				if down {
+					b.Y = top + th
+					res.addContact(l, col, row, Vec2{Y: 1})
+				} else {
+					b.Y = top - b.Height
+					res.addContact(l, col, row, Vec2{Y: -1})
+				}
+			case TileCollisionOneWay:
+				if sy > 0 && prevBottom <= top+tileEpsilon && b.Y+b.Height > top {
+					b.Y = top - b.Height
+					res.addContact(l, col, row, Vec2{Y: -1})
+				}
+			case TileCollisionSlope:
+				left := float64(col) * tw
+				cx := b.X + b.Width/2
+				if cx < left || cx >= left+tw || prevBottom > top+th+tileEpsilon {
+					continue
+				}
+				hl, hr := l.slopeHeights(col, row, c)
+				t := (cx - left) / tw
+				surface := top + th - (hl + (hr-hl)*t)
+				if b.Y+b.Height > surface {
+					b.Y = surface - b.Height
+					n := Vec2{X: hl - hr, Y: -tw}
+					d := math.Hypot(n.X, n.Y)
+					res.addContact(l, col, row, Vec2{X: n.X / d, Y: n.Y / d})
+				}
+			case TileCollisionPolygon:
+				l.resolvePolygon(b, col, row, c, res)
+			}
+		}
+	}
+}
+
+// slopeHeights returns a slope tile's edge heights, mirrored for
+// horizontally flipped tiles.
+func (l *TileMapLayer) slopeHeights(col, row int, c TileCollision) (left, right float64) {
+	if l.GetTile(col, row)&tileFlipH != 0 {
+		return c.SlopeRight, c.SlopeLeft
+	}
+	return c.SlopeLeft, c.SlopeRight
+}
+
+// resolvePolygon pushes the box out of a tile's polygon along the minimum
+// translation vector (separating axis test).
+func (l *TileMapLayer) resolvePolygon(b *Rect, col, row int, c TileCollision, res *TileSweepResult) {
+	n := len(c.Polygon)
+	if n < 3 {
+		return
+	}
+	tw := float64(l.viewport.TileWidth)
+	ox := float64(col) * tw
+	oy := float64(row) * float64(l.viewport.TileHeight)
+	flip := l.GetTile(col, row)&tileFlipH != 0
+	var pts [16]Vec2
+	poly := pts[:0]
+	for _, p := range c.Polygon {
+		if flip {
+			p.X = tw - p.X
+		}
+		poly = append(poly, Vec2{X: ox + p.X, Y: oy + p.Y})
+	}
+
+	best := math.Inf(1)
+	var axis Vec2
+	test := func(ax, ay float64) bool {
+		bMin, bMax := projectRect(*b, ax, ay)
+		pMin, pMax := math.Inf(1), math.Inf(-1)
+		for _, p := range poly {
+			d := p.X*ax + p.Y*ay
+			pMin = math.Min(pMin, d)
+			pMax = math.Max(pMax, d)
+		}
+		overlap := math.Min(bMax, pMax) - math.Max(bMin, pMin)
+		if overlap <= tileEpsilon {
+			return false
+		}
+		if overlap < best {
+			best = overlap
+			// Point the axis from the polygon toward the box.
+			if (bMin+bMax)/2 < (pMin+pMax)/2 {
+				ax, ay = -ax, -ay
+			}
+			axis = Vec2{X: ax, Y: ay}
+		}
+		return true
+	}
+	if !test(1, 0) || !test(0, 1) {
+		return
+	}
+	for i := range poly {
+		j := (i + 1) % len(poly)
+		ex := poly[j].X - poly[i].X
+		ey := poly[j].Y - poly[i].Y
+		d := math.Hypot(ex, ey)
+		if d == 0 {
+			continue
+		}
+		if !test(-ey/d, ex/d) {
+			return
+		}
+	}
+	b.X += axis.X * best
+	b.Y += axis.Y * best
+	res.addContact(l, col, row, axis)
+}
+
+// projectRect returns the extent of r projected onto the axis (ax, ay).
+func projectRect(r Rect, ax, ay float64) (lo, hi float64) {
+	lo, hi = math.Inf(1), math.Inf(-1)
+	for _, p := range [4]Vec2{{r.X, r.Y}, {r.X + r.Width, r.Y}, {r.X, r.Y + r.Height}, {r.X + r.Width, r.Y + r.Height}} {
+		d := p.X*ax + p.Y*ay
+		lo = math.Min(lo, d)
+		hi = math.Max(hi, d)
+	}
+	return lo, hi
+}
+
+// addContact records a blocking tile, skipping repeats from later steps.
+func (res *TileSweepResult) addContact(l *TileMapLayer, col, row int, normal Vec2) {
+	if normal.Y < -0.5 {
+		res.OnGround = true
+	}
+	for _, c := range res.Contacts {
+		if c.Col == col && c.Row == row && c.Normal == normal {
+			return
+		}
+	}
+	res.Contacts = append(res.Contacts, TileContact{Col: col, Row: row, GID: l.GetTile(col, row), Normal: normal})
+}
@@ -0,0 +1,171 @@
+package willow
+
+import (
+	"math"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// newCollisionLayer returns a 16px-tile layer from rows of characters:
+// '#' solid, '-' one-way, '/' and '\' slopes, '_' polygon (lower
+// half of the tile), anything else empty.
+func newCollisionLayer(rows ...string) *TileMapLayer {
+	_, v := newTestViewport(256, 256)
+	w, h := len(rows[0]), len(rows)
+	data := make([]uint32, w*h)
+	gids := map[byte]uint32{'#': 1, '-': 2, '/': 3, '\\': 3 | tileFlipH, '_': 4}
+	for r, line := range rows {
+		for c := 0; c < len(line); c++ {
+			data[r*w+c] = gids[line[c]]
+		}
+	}
+	layer := v.AddTileLayer("solid", w, h, data, testRegions(5), ebiten.NewImage(80, 16))
+	layer.SetCollisions(map[uint32]TileCollision{
+		1: {Kind: TileCollisionSolid},
+		2: {Kind: TileCollisionOneWay},
+		3: {Kind: TileCollisionSlope, SlopeLeft: 0, SlopeRight: 16},
+		4: {Kind: TileCollisionPolygon, Polygon: []Vec2{{0, 8}, {16, 8}, {16, 16}, {0, 16}}},
+	})
+	return layer
+}
+
+func TestSweepAABBSolid(t *testing.T) {
+	layer := newCollisionLayer(
+		"......",
+		"....#.",
+		"######",
+	)
+	box := Rect{X: 4, Y: 10, Width: 8, Height: 12}
+
+	// Falling lands on the floor.
+	res := layer.SweepAABB(box, 0, 20)
+	if res.Y != 20 || !res.OnGround {
+		t.Errorf("landing: Y=%v OnGround=%v, want 20 true", res.Y, res.OnGround)
+	}
+	if len(res.Contacts) != 1 || res.Contacts[0].Normal != (Vec2{Y: -1}) || res.Contacts[0].Row != 2 {
+		t.Errorf("landing contacts = %+v", res.Contacts)
+	}
+
+	// Walking right stops at the wall.
+	box.Y = 20
+	res = layer.SweepAABB(box, 60, 0)
+	if res.X != 56 || res.Y != 20 || res.OnGround {
+		t.Errorf("wall: X=%v Y=%v OnGround=%v, want 56 20 false", res.X, res.Y, res.OnGround)
+	}
+	if len(res.Contacts) != 1 || res.Contacts[0].Normal != (Vec2{X: -1}) || res.Contacts[0].Col != 4 {
+		t.Errorf("wall contacts = %+v", res.Contacts)
+	}
+}
+
+func TestSweepAABBNoTunneling(t *testing.T) {
+	layer := newCollisionLayer(
+		"..#..",
+	)
+	res := layer.SweepAABB(Rect{X: 0, Y: 4, Width: 4, Height: 4}, 100, 0)
+	if res.X != 28 {
+		t.Errorf("X = %v, want 28 (stopped by the thin wall)", res.X)
+	}
+}
+
+func TestSweepAABBOneWay(t *testing.T) {
+	layer := newCollisionLayer(
+		"...",
+		"---",
+		"...",
+	)
+	// Falling from above lands on top.
+	if res := layer.SweepAABB(Rect{X: 4, Y: 0, Width: 8, Height: 8}, 0, 20); res.Y != 8 || !res.OnGround {
+		t.Errorf("from above: Y=%v OnGround=%v, want 8 true", res.Y, res.OnGround)
+	}
+	// Jumping from below passes through.
+	if res := layer.SweepAABB(Rect{X: 4, Y: 36, Width: 8, Height: 8}, 0, -30); res.Y != 6 || len(res.Contacts) != 0 {
+		t.Errorf("from below: Y=%v contacts=%d, want 6 0", res.Y, len(res.Contacts))
+	}
+}
+
+func TestSweepAABBSlope(t *testing.T) {
+	layer := newCollisionLayer(
+		"....",
+		"../#",
+		"####",
+	)
+	// Bottom-center at x=36 is a quarter into the slope: surface 4px up.
+	res := layer.SweepAABB(Rect{X: 32, Y: 0, Width: 8, Height: 8}, 0, 40)
+	if res.Y != 20 || !res.OnGround {
+		t.Fatalf("slope landing: Y=%v OnGround=%v, want 20 true", res.Y, res.OnGround)
+	}
+	n := res.Contacts[0].Normal
+	if math.Abs(n.X+math.Sqrt2/2) > 1e-9 || math.Abs(n.Y+math.Sqrt2/2) > 1e-9 {
+		t.Errorf("slope normal = %v, want up-left 45°", n)
+	}
+
+	// Walking up the slope continues onto the solid tile at its top.
+	box := Rect{X: 20, Y: 24, Width: 8, Height: 8}
+	for i := 0; i < 20; i++ {
+		res = layer.SweepAABB(box, 2, 1)
+		box.X, box.Y = res.X, res.Y
+	}
+	if box.X != 60 || box.Y != 8 {
+		t.Errorf("after walking up: %v,%v, want 60,8", box.X, box.Y)
+	}
+}
+
+func TestSweepAABBFlippedSlope(t *testing.T) {
+	layer := newCollisionLayer(
+		"..",
+		"\\.",
+	)
+	// Mirrored, the slope rises to the left: 12px up at x=4.
+	res := layer.SweepAABB(Rect{X: 0, Y: 0, Width: 8, Height: 8}, 0, 20)
+	if res.Y != 12 {
+		t.Errorf("Y = %v, want 12", res.Y)
+	}
+}
+
+func TestSweepAABBPolygon(t *testing.T) {
+	layer := newCollisionLayer(
+		"...",
+		"._.",
+	)
+	res := layer.SweepAABB(Rect{X: 20, Y: 0, Width: 8, Height: 8}, 0, 20)
+	if res.X != 20 || math.Abs(res.Y-16) > 1e-9 || !res.OnGround {
+		t.Fatalf("landing on the half block: %v,%v OnGround=%v, want 20,16 true", res.X, res.Y, res.OnGround)
+	}
+	if n := res.Contacts[0].Normal; n != (Vec2{Y: -1}) {
+		t.Errorf("normal = %v, want up", n)
+	}
+
+	// Walking into its side pushes back horizontally.
+	res = layer.SweepAABB(Rect{X: 4, Y: 24, Width: 8, Height: 8}, 10, 0)
+	if math.Abs(res.X-8) > 1e-9 || res.Y != 24 {
+		t.Errorf("side: %v,%v, want 8,24", res.X, res.Y)
+	}
+}
+
+func TestTiledCollisions(t *testing.T) {
+	ts := &TiledTileset{FirstGID: 10, TileWidth: 16, TileHeight: 16, Tiles: map[uint32]*TiledTile{
+		0: {Objects: []TiledObject{{Width: 16, Height: 16}}},
+		1: {Objects: []TiledObject{{Polygon: []Vec2{{0, 16}, {16, 16}, {16, 4}}}}},
+		2: {Objects: []TiledObject{{X: 2, Y: 8, Width: 12, Height: 8}}},
+		3: {Type: "oneway", Objects: []TiledObject{{Width: 16, Height: 4}}},
+		4: {Properties: map[string]string{"foo": "bar"}},
+	}}
+	m := &TiledMap{Tilesets: []*TiledTileset{ts}}
+	got := m.Collisions()
+	if len(got) != 4 {
+		t.Fatalf("collisions = %d, want 4", len(got))
+	}
+	if got[10].Kind != TileCollisionSolid {
+		t.Errorf("full rect = %v, want solid", got[10].Kind)
+	}
+	if c := got[11]; c.Kind != TileCollisionSlope || c.SlopeLeft != 0 || c.SlopeRight != 12 {
+		t.Errorf("triangle = %+v, want slope 0→12", c)
+	}
+	if c := got[12]; c.Kind != TileCollisionPolygon || len(c.Polygon) != 4 || c.Polygon[2] != (Vec2{14, 16}) {
+		t.Errorf("partial rect = %+v, want 4-point polygon", c)
+	}
+	if got[13].Kind != TileCollisionOneWay {
+		t.Errorf("oneway tile = %v", got[13].Kind)
+	}
+}
@@ -0,0 +1,139 @@
+package willow
+
+// TileContext carries tile pointer event data passed to TileMapLayer
+// callbacks.
+type TileContext struct {
+	Layer     *TileMapLayer // the layer the tile belongs to
+	Col, Row  int           // tile coordinates in the layer's grid
+	GID       uint32        // tile GID as stored, including flip flags
+	GlobalX   float64       // pointer X in world coordinates
+	GlobalY   float64       // pointer Y in world coordinates
+	Button    MouseButton   // which mouse button is involved
+	PointerID int           // 0 = mouse, 1-9 = touch contacts
+	Modifiers KeyModifiers  // keyboard modifier keys held during the event
+}
+
+// GetTile returns the GID stored at (col, row), including flip flags.
+// Returns 0 for empty cells, coordinates outside the layer and cells of
+// chunks that are not loaded.
+func (l *TileMapLayer) GetTile(col, row int) uint32 {
+	if l.chunks != nil {
+		if data, i := l.chunkCell(col, row); data != nil {
+			return data[i]
+		}
+		return 0
+	}
+	if col < 0 || col >= l.width || row < 0 || row >= l.height {
+		return 0
+	}
+	return l.data[row*l.width+col]
+}
+
+// TileAt returns the tile under the world point (x, y) and its GID,
+// accounting for the layer node's transform. The GID is 0 when the point
+// lies outside the layer or over an empty cell.
+func (l *TileMapLayer) TileAt(x, y float64) (col, row int, gid uint32) {
+	return l.tileAtLocal(l.node.WorldToLocal(x, y))
+}
+
+// tileAtLocal is TileAt for a point in the layer node's local space, where
+// tiles are laid out.
+func (l *TileMapLayer) tileAtLocal(lx, ly float64) (col, row int, gid uint32) {
+	col, row = l.viewport.WorldToTile(lx, ly)
+	return col, row, l.GetTile(col, row)
+}
+
+// tileLayerHitShape makes a layer hit-testable over its non-empty cells
+// while it has tile callbacks, so clicks on empty cells fall through to the
+// layers below.
+type tileLayerHitShape struct {
+	layer *TileMapLayer
+}
+
+// Contains reports whether the local point (x, y) lies over a non-empty
+// tile of a layer with tile callbacks.
+func (h tileLayerHitShape) Contains(x, y float64) bool {
+	l := h.layer
+	if !l.hasTileCallbacks() {
+		return false
+	}
+	_, _, gid := l.tileAtLocal(x, y)
+	return gid != 0
+}
+
+func (l *TileMapLayer) hasTileCallbacks() bool {
+	return l.OnTileClick != nil || l.OnTileEnter != nil || l.OnTileLeave != nil
+}
+
+// initTileInput hooks the layer node into the input system. The node's
+// public pointer callbacks are left to the user.
+func (l *TileMapLayer) initTileInput() {
+	l.node.HitShape = tileLayerHitShape{layer: l}
+	l.node.tileLayer = l
+}
+
+// syncTileInput makes the layer node interactable once tile callbacks are
+// set. Called from the viewport's update.
+func (l *TileMapLayer) syncTileInput() {
+	if !l.node.Interactable && l.hasTileCallbacks() {
+		l.node.Interactable = true
+	}
+}
+
+func (l *TileMapLayer) tileContext(wx, wy float64, button MouseButton, pointerID int, mods KeyModifiers) TileContext {
+	col, row, gid := l.TileAt(wx, wy)
+	return TileContext{
+		Layer: l, Col: col, Row: row, GID: gid,
+		GlobalX: wx, GlobalY: wy,
+		Button: button, PointerID: pointerID, Modifiers: mods,
+	}
+}
+
+func (l *TileMapLayer) tilePointerDown(ctx PointerContext) {
+	l.pressCol, l.pressRow, _ = l.TileAt(ctx.GlobalX, ctx.GlobalY)
+}
+
+// tileClick fires OnTileClick when the press and release land on the same
+// non-empty tile.
+func (l *TileMapLayer) tileClick(ctx ClickContext) {
+	if l.OnTileClick == nil {
+		return
+	}
+	tc := l.tileContext(ctx.GlobalX, ctx.GlobalY, ctx.Button, ctx.PointerID, ctx.Modifiers)
+	if tc.GID == 0 || tc.Col != l.pressCol || tc.Row != l.pressRow {
+		return
+	}
+	l.OnTileClick(tc)
+}
+
+// tileHover fires OnTileLeave and OnTileEnter when the hovered tile changes.
+func (l *TileMapLayer) tileHover(ctx PointerContext) {
+	tc := l.tileContext(ctx.GlobalX, ctx.GlobalY, ctx.Button, ctx.PointerID, ctx.Modifiers)
+	if l.hovering && tc.GID != 0 && tc.Col == l.hoverCol && tc.Row == l.hoverRow {
+		return
+	}
+	l.tileLeave(ctx)
+	if tc.GID == 0 {
+		return
+	}
+	l.hovering = true
+	l.hoverCol, l.hoverRow = tc.Col, tc.Row
+	if l.OnTileEnter != nil {
+		l.OnTileEnter(tc)
+	}
+}
+
+// tileLeave fires OnTileLeave for the hovered tile, if any.
+func (l *TileMapLayer) tileLeave(ctx PointerContext) {
+	if !l.hovering {
+		return
+	}
+	l.hovering = false
+	if l.OnTileLeave != nil {
+		l.OnTileLeave(TileContext{
+			Layer: l, Col: l.hoverCol, Row: l.hoverRow, GID: l.GetTile(l.hoverCol, l.hoverRow),
+			GlobalX: ctx.GlobalX, GlobalY: ctx.GlobalY,
+			Button: ctx.Button, PointerID: ctx.PointerID, Modifiers: ctx.Modifiers,
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package willow
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+func TestTileMapLayerGetTileAndTileAt(t *testing.T) {
+	_, v := newTestViewport(64, 64)
+	layer := v.AddTileLayer("ground", 3, 2, []uint32{
+		1, 0, 2,
+		3, 4 | tileFlipH, 0,
+	}, testRegions(5), ebiten.NewImage(80, 16))
+
+	if gid := layer.GetTile(1, 1); gid != 4|tileFlipH {
+		t.Errorf("GetTile(1,1) = %#x, want flags kept", gid)
+	}
+	for _, c := range [][2]int{{-1, 0}, {3, 0}, {0, 2}} {
+		if gid := layer.GetTile(c[0], c[1]); gid != 0 {
+			t.Errorf("GetTile%v out of bounds = %d, want 0", c, gid)
+		}
+	}
+	if col, row, gid := layer.TileAt(40, 5); col != 2 || row != 0 || gid != 2 {
+		t.Errorf("TileAt(40,5) = %d,%d,%d, want 2,0,2", col, row, gid)
+	}
+}
+
+func TestTileMapLayerPointerEvents(t *testing.T) {
+	s, v := newTestViewport(64, 64)
+	v.Node().Interactable = true
+	bg := v.AddTileLayer("bg", 3, 1, []uint32{1, 1, 1}, testRegions(3), ebiten.NewImage(48, 16))
+	fg := v.AddTileLayer("fg", 3, 1, []uint32{0, 2, 2}, testRegions(3), ebiten.NewImage(48, 16))
+
+	var clicks, enters, leaves []TileContext
+	bg.OnTileClick = func(ctx TileContext) { clicks = append(clicks, ctx) }
+	fg.OnTileClick = func(ctx TileContext) { clicks = append(clicks, ctx) }
+	fg.OnTileEnter = func(ctx TileContext) { enters = append(enters, ctx) }
+	fg.OnTileLeave = func(ctx TileContext) { leaves = append(leaves, ctx) }
+	v.update(0)
+
+	// The foreground wins where it has a tile; its empty cell falls through.
+	s.processPointer(0, 20, 8, 20, 8, true, MouseButtonLeft, 0)
+	s.processPointer(0, 20, 8, 20, 8, false, MouseButtonLeft, 0)
+	s.processPointer(0, 4, 8, 4, 8, true, MouseButtonLeft, 0)
+	s.processPointer(0, 4, 8, 4, 8, false, MouseButtonLeft, 0)
+	if len(clicks) != 2 {
+		t.Fatalf("clicks = %d, want 2", len(clicks))
+	}
+	if c := clicks[0]; c.Layer != fg || c.Col != 1 || c.Row != 0 || c.GID != 2 {
+		t.Errorf("first click = %+v, want fg tile 1,0 GID 2", c)
+	}
+	if c := clicks[1]; c.Layer != bg || c.Col != 0 || c.GID != 1 {
+		t.Errorf("second click = %+v, want bg tile 0,0 GID 1", c)
+	}
+	if len(enters) != 1 || len(leaves) != 1 || leaves[0].Col != 1 {
+		t.Fatalf("after clicks: enters=%d leaves=%d, want 1 each", len(enters), len(leaves))
+	}
+
+	// Hovering across tiles of the same layer fires leave/enter per tile.
+	s.processPointer(0, 20, 8, 20, 8, false, MouseButtonLeft, 0)
+	s.processPointer(0, 24, 8, 24, 8, false, MouseButtonLeft, 0)
+	s.processPointer(0, 36, 8, 36, 8, false, MouseButtonLeft, 0)
+	if len(enters) != 3 || enters[2].Col != 2 {
+		t.Errorf("enters = %d, want 3 ending on col 2", len(enters))
+	}
+	if len(leaves) != 2 || leaves[1].Col != 1 {
+		t.Errorf("leaves = %d, want 2 ending on col 1", len(leaves))
+	}
+}
+
+func TestTileMapLayerClickNeedsSameTile(t *testing.T) {
+	s, v := newTestViewport(64, 64)
+	v.Node().Interactable = true
+	layer := v.AddTileLayer("ground", 3, 1, []uint32{1, 1, 1}, testRegions(2), ebiten.NewImage(32, 16))
+	clicked := 0
+	layer.OnTileClick = func(TileContext) { clicked++ }
+	v.update(0)
+
+	s.SetDragDeadZone(100)
+	s.processPointer(0, 4, 8, 4, 8, true, MouseButtonLeft, 0)
+	s.processPointer(0, 20, 8, 20, 8, true, MouseButtonLeft, 0)
+	s.processPointer(0, 20, 8, 20, 8, false, MouseButtonLeft, 0)
+	if clicked != 0 {
+		t.Errorf("release on another tile should not click, got %d", clicked)
+	}
+}
+
+func TestTileMapLayerWithoutCallbacksNotHit(t *testing.T) {
+	s, v := newTestViewport(64, 64)
+	v.Node().Interactable = true
+	v.AddTileLayer("ground", 1, 1, []uint32{1}, testRegions(2), ebiten.NewImage(32, 16))
+	if n := s.hitTest(8, 8); n != nil {
+		t.Errorf("hitTest = %q, want nil for a layer without tile callbacks", n.Name)
+	}
+}
+
+func TestTileMapLayerInteractableOnlyWithCallbacks(t *testing.T) {
+	_, v := newTestViewport(64, 64)
+	layer := v.AddTileLayer("ground", 1, 1, []uint32{1}, testRegions(2), ebiten.NewImage(32, 16))
+	v.update(0)
+	if layer.Node().Interactable {
+		t.Error("layer without tile callbacks should not be interactable")
+	}
+	layer.OnTileEnter = func(TileContext) {}
+	v.update(0)
+	if !layer.Node().Interactable {
+		t.Error("setting OnTileEnter should make the layer interactable")
+	}
+}
+
+func TestTileMapLayerKeepsUserCallbacks(t *testing.T) {
+	s, v := newTestViewport(64, 64)
+	v.Node().Interactable = true
+	layer := v.AddTileLayer("ground", 2, 1, []uint32{1, 1}, testRegions(2), ebiten.NewImage(32, 16))
+	var tileClicks, nodeClicks int
+	layer.OnTileClick = func(TileContext) { tileClicks++ }
+	layer.Node().OnClick = func(ClickContext) { nodeClicks++ }
+	v.update(0)
+
+	clickAt(s, 4, 8)
+	if tileClicks != 1 || nodeClicks != 1 {
+		t.Errorf("tile clicks %d, node clicks %d; want 1, 1", tileClicks, nodeClicks)
+	}
+}
+
+func TestTileMapLayerHitTestTranslatedViewport(t *testing.T) {
+	s, v := newTestViewport(64, 64)
+	v.Node().Interactable = true
+	v.Node().X, v.Node().Y = 100, 50
+	layer := v.AddTileLayer("ground", 2, 1, []uint32{0, 3}, testRegions(4), ebiten.NewImage(64, 16))
+	var clicks []TileContext
+	layer.OnTileClick = func(ctx TileContext) { clicks = append(clicks, ctx) }
+	v.update(0)
+	updateWorldTransform(s.root, identityTransform, 1.0, false, false)
+
+	// World (120, 58) is local (20, 8): column 1.
+	if n := s.hitTest(120, 58); n != layer.Node() {
+		t.Fatalf("hitTest over a tile = %v, want the layer", n)
+	}
+	// World (20, 8) would be column 1 without the offset; it is off the map.
+	if n := s.hitTest(20, 8); n != nil {
+		t.Errorf("hitTest at the untranslated position = %q, want nil", n.Name)
+	}
+	if col, _, gid := layer.TileAt(104, 58); col != 0 || gid != 0 {
+		t.Errorf("TileAt(104,58) = col %d GID %d, want empty col 0", col, gid)
+	}
+	// Tiles render where they hit-test.
+	order := 0
+	s.viewTransform = identityTransform
+	layer.emitCommands(s, &order)
+	if vtx := layer.pages[0].vertices[0]; vtx.DstX != 116 || vtx.DstY != 50 {
+		t.Errorf("tile drawn at (%v, %v), want (116, 50)", vtx.DstX, vtx.DstY)
+	}
+
+	clickAt(s, 120, 58)
+	if len(clicks) != 1 || clicks[0].Col != 1 || clicks[0].GID != 3 {
+		t.Errorf("clicks = %+v, want one on col 1 GID 3", clicks)
+	}
+}
@@ -0,0 +1,148 @@
+package willow
+
+import "math"
+
+// TileOrientation selects how a TileMapViewport projects grid cells into
+// world space. The layouts match Tiled's map orientations.
+type TileOrientation uint8
+
+const (
+	// TileOrthogonal places tiles on a rectangular grid (default).
+	TileOrthogonal TileOrientation = iota
+	// TileIsometric places diamond tiles with tile (0, 0) at the top and
+	// columns running down-right, rows down-left.
+	TileIsometric
+	// TileStaggered places diamond tiles in rows (or columns, with
+	// StaggerX) where every other row is shifted by half a tile.
+	TileStaggered
+	// TileHexagonal places hexagonal tiles in staggered rows or columns.
+	// HexSideLength sets the flat side length.
+	TileHexagonal
+)
+
+// TileToWorld returns the world-space center of tile (col, row) for the
+// viewport's orientation. For isometric grids, tile (0, 0) has its top
+// corner at world (TileWidth/2, 0).
+func (v *TileMapViewport) TileToWorld(col, row int) (x, y float64) {
+	x, y = v.tileOrigin(col, row)
+	return x + float64(v.TileWidth)/2, y + float64(v.TileHeight)/2
+}
+
+// WorldToTile returns the tile containing the world point (x, y) for the
+// viewport's orientation. The result is not clamped to any layer's bounds.
+func (v *TileMapViewport) WorldToTile(x, y float64) (col, row int) {
+	tw := float64(v.TileWidth)
+	th := float64(v.TileHeight)
+	switch v.Orientation {
+	case TileIsometric:
+		u := (x - tw/2) / (tw / 2)
+		w := y / (th / 2)
+		return int(math.Floor((u + w) / 2)), int(math.Floor((w - u) / 2))
+	case TileStaggered, TileHexagonal:
+		// The cell's rectangular step gives a near guess; the owner is the
+		// neighbour whose center is closest (diamond distance for
+		// staggered tiles, Euclidean for hexagons).
+		cw, rh := v.cellStep()
+		c0 := int(math.Floor(x / cw))
+		r0 := int(math.Floor(y / rh))
+		best := math.Inf(1)
+		for r := r0 - 1; r <= r0+1; r++ {
+			for c := c0 - 1; c <= c0+1; c++ {
+				cx, cy := v.TileToWorld(c, r)
+				dx, dy := x-cx, y-cy
+				var d float64
+				if v.Orientation == TileStaggered {
+					d = math.Abs(dx)/(tw/2) + math.Abs(dy)/(th/2)
+				} else {
+					d = dx*dx + dy*dy
+				}
+				if d < best {
+					best, col, row = d, c, r
+				}
+			}
+		}
+		return col, row
+	default:
+		return int(math.Floor(x / tw)), int(math.Floor(y / th))
+	}
+}
+
+// tileOrigin returns the top-left corner of tile (col, row)'s bounding box,
+// which is where its quad is drawn.
+func (v *TileMapViewport) tileOrigin(col, row int) (x, y float64) {
+	tw := float64(v.TileWidth)
+	th := float64(v.TileHeight)
+	switch v.Orientation {
+	case TileIsometric:
+		return float64(col-row) * tw / 2, float64(col+row) * th / 2
+	case TileStaggered, TileHexagonal:
+		cw, rh := v.cellStep()
+		if v.StaggerX {
+			y = float64(row) * th
+			if v.staggered(col) {
+				y += th / 2
+			}
+			return float64(col) * cw, y
+		}
+		x = float64(col) * tw
+		if v.staggered(row) {
+			x += tw / 2
+		}
+		return x, float64(row) * rh
+	default:
+		return float64(col) * tw, float64(row) * th
+	}
+}
+
+// cellStep returns the distance between adjacent columns and rows of a
+// staggered or hexagonal grid. Along the stagger axis cells overlap, so the
+// step is half a tile plus half the hexagon side.
+func (v *TileMapViewport) cellStep() (colW, rowH float64) {
+	tw := float64(v.TileWidth)
+	th := float64(v.TileHeight)
+	side := 0.0
+	if v.Orientation == TileHexagonal {
+		side = float64(v.HexSideLength)
+	}
+	if v.StaggerX {
+		return (tw + side) / 2, th
+	}
+	return tw, (th + side) / 2
+}
+
+// staggered reports whether row (or column, with StaggerX) i is the one
+// shifted by half a tile.
+func (v *TileMapViewport) staggered(i int) bool {
+	odd := i&1 != 0
+	return odd != v.StaggerEven
+}
+
+// bufferSpan returns how many columns and rows cover a w×h world area,
+// before edge and margin padding.
+func (v *TileMapViewport) bufferSpan(w, h float64) (cols, rows int) {
+	tw := float64(v.TileWidth)
+	th := float64(v.TileHeight)
+	switch v.Orientation {
+	case TileIsometric:
+		// A rectangle spans w/tw + h/th along both diagonal axes.
+		n := int(math.Ceil(w/tw + h/th))
+		return n, n
+	case TileStaggered, TileHexagonal:
+		cw, rh := v.cellStep()
+		return int(math.Ceil(w / cw)), int(math.Ceil(h / rh))
+	default:
+		return int(math.Ceil(w / tw)), int(math.Ceil(h / th))
+	}
+}
+
+// minTile returns the smallest column and row touched by the corners of a
+// world-space rectangle.
+func (v *TileMapViewport) minTile(r Rect) (col, row int) {
+	col, row = v.WorldToTile(r.X, r.Y)
+	for _, p := range [3]Vec2{{r.X + r.Width, r.Y}, {r.X, r.Y + r.Height}, {r.X + r.Width, r.Y + r.Height}} {
+		c, rr := v.WorldToTile(p.X, p.Y)
+		col = min(col, c)
+		row = min(row, rr)
+	}
+	return col, row
+}
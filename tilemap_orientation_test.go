@@ -0,0 +1,165 @@
+package willow
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+func orientationViewports() map[string]*TileMapViewport {
+	mk := func(o TileOrientation, staggerX, even bool, side int) *TileMapViewport {
+		v := NewTileMapViewport("map", 64, 32)
+		v.Orientation = o
+		v.StaggerX = staggerX
+		v.StaggerEven = even
+		v.HexSideLength = side
+		return v
+	}
+	return map[string]*TileMapViewport{
+		"orthogonal":     mk(TileOrthogonal, false, false, 0),
+		"isometric":      mk(TileIsometric, false, false, 0),
+		"staggered-y":    mk(TileStaggered, false, false, 0),
+		"staggered-x":    mk(TileStaggered, true, true, 0),
+		"hexagonal-y":    mk(TileHexagonal, false, false, 16),
+		"hexagonal-x":    mk(TileHexagonal, true, false, 24),
+		"hexagonal-even": mk(TileHexagonal, false, true, 16),
+	}
+}
+
+func TestTileWorldRoundTrip(t *testing.T) {
+	for name, v := range orientationViewports() {
+		for row := -3; row < 6; row++ {
+			for col := -3; col < 6; col++ {
+				x, y := v.TileToWorld(col, row)
+				// The center and points slightly off it belong to the tile.
+				for _, d := range [][2]float64{{0, 0}, {4, 2}, {-4, -2}, {6, -3}} {
+					c, r := v.WorldToTile(x+d[0], y+d[1])
+					if c != col || r != row {
+						t.Fatalf("%s: WorldToTile(center of %d,%d + %v) = %d,%d", name, col, row, d, c, r)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestIsometricTileToWorld(t *testing.T) {
+	v := orientationViewports()["isometric"]
+	cases := []struct {
+		col, row int
+		x, y     float64
+	}{
+		{0, 0, 32, 16},
+		{1, 0, 64, 32}, // columns run down-right
+		{0, 1, 0, 32},  // rows run down-left
+		{2, 2, 32, 80},
+	}
+	for _, c := range cases {
+		if x, y := v.TileToWorld(c.col, c.row); x != c.x || y != c.y {
+			t.Errorf("TileToWorld(%d,%d) = %v,%v, want %v,%v", c.col, c.row, x, y, c.x, c.y)
+		}
+	}
+	// The diamond's top corner belongs to the tile; the bounding box's
+	// top-left corner belongs to the neighbour up-left.
+	if c, r := v.WorldToTile(32, 1); c != 0 || r != 0 {
+		t.Errorf("top corner = %d,%d", c, r)
+	}
+	if c, r := v.WorldToTile(1, 1); c != -1 || r != 0 {
+		t.Errorf("bounding box corner = %d,%d, want -1,0", c, r)
+	}
+}
+
+func TestStaggeredTileOrigin(t *testing.T) {
+	vs := orientationViewports()
+	if x, y := vs["staggered-y"].tileOrigin(0, 1); x != 32 || y != 16 {
+		t.Errorf("staggered odd row origin = %v,%v, want 32,16", x, y)
+	}
+	if x, y := vs["staggered-x"].tileOrigin(0, 0); x != 0 || y != 16 {
+		t.Errorf("staggered-x even column origin = %v,%v, want 0,16", x, y)
+	}
+	// Hex rows step by (tileHeight + side) / 2.
+	if _, y := vs["hexagonal-y"].tileOrigin(0, 2); y != 48 {
+		t.Errorf("hex row 2 y = %v, want 48", y)
+	}
+}
+
+// newOrientedLayer adds a fully filled w×h layer to v, bound to a 320×240
+// camera.
+func newOrientedLayer(t *testing.T, v *TileMapViewport, w, h int) (*Scene, *TileMapLayer) {
+	t.Helper()
+	s := NewScene()
+	cam := s.NewCamera(Rect{Width: 320, Height: 240})
+	v.SetCamera(cam)
+	s.Root().AddChild(v.Node())
+	data := make([]uint32, w*h)
+	for i := range data {
+		data[i] = 1
+	}
+	layer := v.AddTileLayer("ground", w, h, data, testRegions(2), ebiten.NewImage(64, 32))
+	return s, layer
+}
+
+func TestOrientedRebuildBackToFront(t *testing.T) {
+	for name, v := range orientationViewports() {
+		_, layer := newOrientedLayer(t, v, 20, 20)
+		cx, cy := v.TileToWorld(10, 10)
+		v.camera.X, v.camera.Y = cx, cy
+		v.camera.Invalidate()
+		v.update(0)
+
+		p := layer.pages[0]
+		if p.tileCount == 0 {
+			t.Fatalf("%s: no tiles buffered", name)
+		}
+		for i := 1; i < p.tileCount; i++ {
+			if p.worldY[i] < p.worldY[i-1] {
+				t.Fatalf("%s: slot %d y=%v drawn after y=%v", name, i, p.worldY[i], p.worldY[i-1])
+			}
+		}
+		// The tile under the camera center must be buffered.
+		found := false
+		ox, oy := v.tileOrigin(10, 10)
+		for i := 0; i < p.tileCount; i++ {
+			if p.worldX[i] == float32(ox) && p.worldY[i] == float32(oy) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("%s: tile under the camera not buffered", name)
+		}
+	}
+}
+
+func TestTileMapLayerDepthSort(t *testing.T) {
+	v := orientationViewports()["isometric"]
+	s, layer := newOrientedLayer(t, v, 3, 3)
+	v.camera.X, v.camera.Y = 32, 48
+	v.camera.Invalidate()
+	layer.DepthSort = true
+	v.update(0)
+
+	order := 0
+	s.viewTransform = identityTransform
+	layer.emitCommands(s, &order)
+	// A 3x3 diamond has 5 screen rows (diagonals).
+	if len(s.commands) != 5 {
+		t.Fatalf("commands = %d, want 5", len(s.commands))
+	}
+	for i, cmd := range s.commands {
+		want := i*16 + 32 // bottom edge of diagonal i
+		if cmd.GlobalOrder != want {
+			t.Errorf("command %d GlobalOrder = %d, want %d", i, cmd.GlobalOrder, want)
+		}
+	}
+}
+
+func TestTiledMapNewViewportOrientation(t *testing.T) {
+	m := &TiledMap{Orientation: "hexagonal", StaggerAxis: "x", StaggerIndex: "even", HexSideLength: 12, TileWidth: 32, TileHeight: 28}
+	v, err := m.NewViewport("hex")
+	if err != nil {
+		t.Fatalf("NewViewport: %v", err)
+	}
+	if v.Orientation != TileHexagonal || !v.StaggerX || !v.StaggerEven || v.HexSideLength != 12 {
+		t.Errorf("viewport = orientation %d staggerX %v even %v side %d", v.Orientation, v.StaggerX, v.StaggerEven, v.HexSideLength)
+	}
+}
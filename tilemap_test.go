@@ -0,0 +1,195 @@
+package willow
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// newTestViewport returns a 16px-tile viewport bound to a camera showing
+// the map's top-left corner.
+func newTestViewport(w, h float64) (*Scene, *TileMapViewport) {
+	s := NewScene()
+	cam := s.NewCamera(Rect{Width: w, Height: h})
+	cam.X, cam.Y = w/2, h/2
+	v := NewTileMapViewport("map", 16, 16)
+	v.SetCamera(cam)
+	s.Root().AddChild(v.Node())
+	return s, v
+}
+
+func testRegions(n int) []TextureRegion {
+	r := make([]TextureRegion, n)
+	for i := range r {
+		r[i] = TextureRegion{X: uint16(i * 16), Width: 16, Height: 16}
+	}
+	return r
+}
+
+func TestTileMapLayerSetsSplitByPage(t *testing.T) {
+	s, v := newTestViewport(64, 64)
+	imgA := ebiten.NewImage(64, 16)
+	imgB := ebiten.NewImage(64, 16)
+	data := []uint32{
+		1, 2, 5,
+		0, 6 | tileFlipH, 1,
+	}
+	layer := v.AddTileLayerSets("ground", 3, 2, data, []TileSet{
+		{FirstGID: 1, Regions: testRegions(4), Image: imgA},
+		{FirstGID: 5, Regions: testRegions(2), Image: imgB},
+	})
+	v.update(0)
+
+	if len(layer.pages) != 2 {
+		t.Fatalf("pages = %d, want 2", len(layer.pages))
+	}
+	if a, b := layer.pages[0].tileCount, layer.pages[1].tileCount; a != 3 || b != 2 {
+		t.Errorf("tiles per page = %d, %d; want 3, 2", a, b)
+	}
+	// GID 6 is the second region of set B, drawn at col 1, row 1.
+	pb := layer.pages[1]
+	if pb.worldX[1] != 16 || pb.worldY[1] != 16 {
+		t.Errorf("GID 6 slot at (%v, %v), want (16, 16)", pb.worldX[1], pb.worldY[1])
+	}
+
+	order := 0
+	s.viewTransform = identityTransform
+	layer.emitCommands(s, &order)
+	// Tiles draw in row order, switching image between runs: 1 2 | 5 6 | 1.
+	if len(s.commands) != 3 {
+		t.Fatalf("commands = %d, want one per run of same-page tiles", len(s.commands))
+	}
+	if s.commands[0].tilemapImage != imgA || s.commands[1].tilemapImage != imgB || s.commands[2].tilemapImage != imgA {
+		t.Error("commands should draw each run from its own image, in row order")
+	}
+	if n := len(s.commands[1].tilemapVerts); n != 8 {
+		t.Errorf("second run has %d vertices, want 2 tiles", n/4)
+	}
+}
+
+func TestTileMapLayerTallTilesOverlapAcrossSets(t *testing.T) {
+	s, v := newTestViewport(64, 64)
+	tall := ebiten.NewImage(16, 32)
+	flat := ebiten.NewImage(64, 16)
+	// A 16x32 tree (set on page 0) in row 1 reaches up over the flat tile
+	// (page 1) in row 0, so it must draw after it.
+	layer := v.AddTileLayerSets("ground", 1, 2, []uint32{5, 1}, []TileSet{
+		{FirstGID: 1, Regions: testRegions(1), Image: tall, TileWidth: 16, TileHeight: 32},
+		{FirstGID: 5, Regions: testRegions(4), Image: flat},
+	})
+	v.update(0)
+	updateWorldTransform(s.root, identityTransform, 1.0, false, false)
+	order := 0
+	s.viewTransform = identityTransform
+	layer.emitCommands(s, &order)
+	if len(s.commands) != 2 || s.commands[0].tilemapImage != flat || s.commands[1].tilemapImage != tall {
+		t.Fatalf("commands should draw row 0's flat tile before row 1's tree")
+	}
+	if s.commands[0].treeOrder >= s.commands[1].treeOrder {
+		t.Error("tree should have a later tree order than the tile it overlaps")
+	}
+}
+
+func TestTileMapLayerSetsShareImage(t *testing.T) {
+	_, v := newTestViewport(64, 64)
+	img := ebiten.NewImage(64, 64)
+	layer := v.AddTileLayerSets("ground", 2, 1, []uint32{1, 3}, []TileSet{
+		{FirstGID: 1, Regions: testRegions(2), Image: img},
+		{FirstGID: 3, Regions: testRegions(2), Image: img},
+	})
+	v.update(0)
+	if len(layer.pages) != 1 || layer.pages[0].tileCount != 2 {
+		t.Errorf("sets sharing an image should share a page: pages=%d", len(layer.pages))
+	}
+}
+
+func TestTileMapLayerNativeTileSize(t *testing.T) {
+	s, v := newTestViewport(64, 64)
+	layer := v.AddTileLayerSets("ground", 2, 2, []uint32{1, 0, 0, 5}, []TileSet{
+		{FirstGID: 1, Regions: testRegions(4), Image: ebiten.NewImage(64, 16)},
+		{FirstGID: 5, Regions: testRegions(1), Image: ebiten.NewImage(32, 48), TileWidth: 32, TileHeight: 48},
+	})
+	v.update(0)
+	updateWorldTransform(s.root, identityTransform, 1.0, false, false)
+	order := 0
+	s.viewTransform = identityTransform
+	layer.emitCommands(s, &order)
+
+	// Map-sized tiles fill their cell.
+	if vs := layer.pages[0].vertices; vs[0].DstX != 0 || vs[0].DstY != 0 || vs[3].DstX != 16 || vs[3].DstY != 16 {
+		t.Errorf("16x16 tile quad = (%v, %v)-(%v, %v)", vs[0].DstX, vs[0].DstY, vs[3].DstX, vs[3].DstY)
+	}
+	// The 32x48 tile at cell (1, 1) keeps its size and shares the cell's
+	// bottom-left corner (16, 32).
+	if vs := layer.pages[1].vertices; vs[0].DstX != 16 || vs[0].DstY != -16 || vs[3].DstX != 48 || vs[3].DstY != 32 {
+		t.Errorf("32x48 tile quad = (%v, %v)-(%v, %v), want (16, -16)-(48, 32)", vs[0].DstX, vs[0].DstY, vs[3].DstX, vs[3].DstY)
+	}
+}
+
+func TestTileMapLayerPageChunking(t *testing.T) {
+	// Enough visible tiles on one page to need two draw calls.
+	const w, h = 130, 130
+	s, v := newTestViewport(w*16, h*16)
+	v.MarginTiles = 0
+	data := make([]uint32, w*h)
+	for i := range data {
+		data[i] = 1
+	}
+	img := ebiten.NewImage(16, 16)
+	layer := v.AddTileLayerSets("big", w, h, data, []TileSet{{FirstGID: 1, Regions: testRegions(1), Image: img}})
+	v.update(0)
+
+	order := 0
+	s.viewTransform = identityTransform
+	layer.emitCommands(s, &order)
+	if len(s.commands) != 2 {
+		t.Fatalf("commands = %d, want 2 (%d tiles, %d per draw)", len(s.commands), layer.pages[0].tileCount, maxTilesPerDraw)
+	}
+	if n := len(s.commands[0].tilemapInds) / 6; n != maxTilesPerDraw {
+		t.Errorf("first batch = %d tiles, want %d", n, maxTilesPerDraw)
+	}
+}
+
+func TestTileMapLayerAddTileLayerSinglePage(t *testing.T) {
+	_, v := newTestViewport(64, 64)
+	img := ebiten.NewImage(64, 16)
+	layer := v.AddTileLayer("ground", 2, 1, []uint32{1, 2}, testRegions(3), img)
+	v.update(0)
+	if len(layer.pages) != 1 || layer.pages[0].image != img || layer.pages[0].tileCount != 2 {
+		t.Errorf("AddTileLayer should build one page with both tiles")
+	}
+
+	// SetTile inside the buffered range marks the layer for rebuild.
+	layer.SetTile(1, 0, 0)
+	if !layer.bufDirty {
+		t.Error("SetTile in buffered range should set bufDirty")
+	}
+	v.update(0)
+	if layer.pages[0].tileCount != 1 {
+		t.Errorf("tileCount = %d after clearing a tile, want 1", layer.pages[0].tileCount)
+	}
+}
+
+func TestTileMapLayerAnimationStaysOnPage(t *testing.T) {
+	_, v := newTestViewport(64, 64)
+	imgA := ebiten.NewImage(64, 16)
+	imgB := ebiten.NewImage(64, 16)
+	layer := v.AddTileLayerSets("ground", 2, 1, []uint32{1, 2}, []TileSet{
+		{FirstGID: 1, Regions: testRegions(2), Image: imgA},
+		{FirstGID: 3, Regions: testRegions(2), Image: imgB},
+	})
+	layer.SetAnimations(map[uint32][]AnimFrame{
+		1: {{GID: 1, Duration: 100}, {GID: 2, Duration: 100}}, // same page
+		2: {{GID: 2, Duration: 100}, {GID: 3, Duration: 100}}, // crosses pages
+	})
+	v.update(0)
+	v.update(0.15)
+
+	p := layer.pages[0]
+	if p.vertices[0].SrcX != 16 {
+		t.Errorf("GID 1 should animate to GID 2's region, SrcX = %v", p.vertices[0].SrcX)
+	}
+	if p.vertices[4].SrcX != 16 {
+		t.Errorf("cross-page frame should be skipped, SrcX = %v", p.vertices[4].SrcX)
+	}
+}
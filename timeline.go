@@ -0,0 +1,356 @@
+package willow
+
+import "sort"
+
+// TimelineItem is anything a Timeline can schedule: a *TweenGroup or a
+// nested *Timeline.
+type TimelineItem interface {
+	timelineDuration() float32
+	timelineEnter()
+	timelineSeek(t float32, fire bool)
+}
+
+// timelineEntry is one scheduled item or callback.
+type timelineEntry struct {
+	start   float32
+	item    TimelineItem // nil for callbacks
+	fn      func()
+	entered bool    // the item has captured its start values
+	local   float32 // last local time the item was seeked to
+}
+
+// Timeline composes tweens sequentially and in parallel, with delays,
+// labels and callbacks at points in time. Build it with the chainable
+// Then, With, Insert, Delay, Label and Call methods, then call Update(dt)
+// each frame.
+//
+// Each tween captures its start values the first time the playhead
+// reaches it, so a tween placed after another on the same fields starts
+// where the first one ended. Seeking and reversing replay the captured
+// values. A nested timeline is driven by its parent; its own Update,
+// Paused and TimeScale are not used.
+type Timeline struct {
+	// TimeScale multiplies dt in Update. 1 is normal speed.
+	TimeScale float32
+	// Repeat is the number of extra iterations; -1 repeats forever.
+	Repeat int
+	// Yoyo plays every other iteration backwards.
+	Yoyo bool
+	// Paused stops Update from advancing the timeline.
+	Paused bool
+	// Done is true once playback reaches the end (or the start, when
+	// reversed).
+	Done bool
+
+	// OnComplete is called when Done becomes true.
+	OnComplete func()
+	// OnRepeat is called each time playback crosses into a new iteration.
+	OnRepeat func()
+
+	entries   []*timelineEntry
+	labels    map[string]float32
+	cursor    float32 // where Then places the next item
+	lastStart float32 // where With places the next item
+	duration  float32 // length of one iteration
+
+	pos      float32 // playhead across all iterations
+	local    float32 // playhead within the current iteration
+	reversed bool
+	atEdge   bool // callbacks at local have not fired yet
+}
+
+// NewTimeline creates an empty Timeline at normal speed.
+func NewTimeline() *Timeline {
+	return &Timeline{TimeScale: 1, atEdge: true}
+}
+
+// Then schedules item to start when the previously scheduled items end.
+func (tl *Timeline) Then(item TimelineItem) *Timeline {
+	return tl.Insert(tl.cursor, item)
+}
+
+// With schedules item to start alongside the last item added.
+func (tl *Timeline) With(item TimelineItem) *Timeline {
+	return tl.Insert(tl.lastStart, item)
+}
+
+// Insert schedules item to start at the given time in seconds.
+func (tl *Timeline) Insert(at float32, item TimelineItem) *Timeline {
+	at = max(at, 0)
+	tl.add(&timelineEntry{start: at, item: item})
+	tl.lastStart = at
+	end := at + item.timelineDuration()
+	tl.cursor = max(tl.cursor, end)
+	tl.duration = max(tl.duration, end)
+	return tl
+}
+
+// Delay moves the position of the next Then by d seconds.
+func (tl *Timeline) Delay(d float32) *Timeline {
+	tl.cursor = max(tl.cursor+d, 0)
+	tl.duration = max(tl.duration, tl.cursor)
+	return tl
+}
+
+// Label names the current position of the next Then for SeekLabel and
+// LabelTime.
+func (tl *Timeline) Label(name string) *Timeline {
+	if tl.labels == nil {
+		tl.labels = make(map[string]float32)
+	}
+	tl.labels[name] = tl.cursor
+	return tl
+}
+
+// Call schedules fn at the position of the next Then.
+func (tl *Timeline) Call(fn func()) *Timeline {
+	return tl.CallAt(tl.cursor, fn)
+}
+
+// CallAt schedules fn at the given time in seconds. Callbacks fire when
+// playback crosses their time in either direction, but not on Seek.
+func (tl *Timeline) CallAt(at float32, fn func()) *Timeline {
+	at = max(at, 0)
+	tl.add(&timelineEntry{start: at, fn: fn})
+	tl.duration = max(tl.duration, at)
+	return tl
+}
+
+// add inserts e after every entry starting at or before it.
+func (tl *Timeline) add(e *timelineEntry) {
+	i := sort.Search(len(tl.entries), func(i int) bool { return tl.entries[i].start > e.start })
+	tl.entries = append(tl.entries, nil)
+	copy(tl.entries[i+1:], tl.entries[i:])
+	tl.entries[i] = e
+}
+
+// LabelTime returns the time of a label and whether it exists.
+func (tl *Timeline) LabelTime(name string) (float32, bool) {
+	t, ok := tl.labels[name]
+	return t, ok
+}
+
+// Duration returns the length of one iteration in seconds.
+func (tl *Timeline) Duration() float32 {
+	return tl.duration
+}
+
+// TotalDuration returns the length of all iterations in seconds, or -1
+// when the timeline repeats forever.
+func (tl *Timeline) TotalDuration() float32 {
+	if tl.Repeat < 0 {
+		return -1
+	}
+	return tl.duration * float32(tl.Repeat+1)
+}
+
+// Elapsed returns the playhead position across all iterations in seconds.
+func (tl *Timeline) Elapsed() float32 {
+	return tl.pos
+}
+
+// Update advances the timeline by dt seconds scaled by TimeScale, backwards
+// when reversed, firing the callbacks crossed on the way.
+func (tl *Timeline) Update(dt float32) {
+	if tl.Done || tl.Paused {
+		return
+	}
+	d := dt * tl.TimeScale
+	if tl.reversed {
+		d = -d
+	}
+	tl.seekPos(tl.pos+d, true)
+
+	total := tl.TotalDuration()
+	if (tl.reversed && tl.pos <= 0) || (!tl.reversed && total >= 0 && tl.pos >= total) {
+		tl.Done = true
+		if tl.OnComplete != nil {
+			tl.OnComplete()
+		}
+	}
+}
+
+// Seek jumps the playhead to t seconds across all iterations without
+// firing callbacks. Callbacks exactly at t fire on the next Update.
+func (tl *Timeline) Seek(t float32) {
+	tl.seekPos(t, false)
+	tl.Done = false
+}
+
+// SeekLabel seeks to a label in the current iteration. It reports false
+// when the label does not exist.
+func (tl *Timeline) SeekLabel(name string) bool {
+	t, ok := tl.labels[name]
+	if !ok {
+		return false
+	}
+	if tl.duration > 0 {
+		iter := int(tl.pos / tl.duration)
+		if tl.Repeat >= 0 {
+			iter = min(iter, tl.Repeat)
+		}
+		if tl.Yoyo && iter%2 == 1 {
+			t = tl.duration - t
+		}
+		t += float32(iter) * tl.duration
+	}
+	tl.Seek(t)
+	return true
+}
+
+// Reverse flips the playback direction, so Update plays back toward the
+// start. A finished timeline resumes in the new direction.
+func (tl *Timeline) Reverse() {
+	tl.reversed = !tl.reversed
+	tl.Done = false
+}
+
+// Reversed reports whether the timeline plays backwards.
+func (tl *Timeline) Reversed() bool {
+	return tl.reversed
+}
+
+// Restart rewinds to the start, playing forwards.
+func (tl *Timeline) Restart() {
+	tl.reversed = false
+	tl.Seek(0)
+}
+
+// iterAt returns the iteration and local time of playhead position pos.
+func (tl *Timeline) iterAt(pos float32) (int, float32) {
+	if tl.duration <= 0 {
+		return 0, 0
+	}
+	iter := int(pos / tl.duration)
+	local := pos - float32(iter)*tl.duration
+	if tl.Repeat >= 0 && iter > tl.Repeat {
+		iter, local = tl.Repeat, tl.duration
+	}
+	if tl.Yoyo && iter%2 == 1 {
+		local = tl.duration - local
+	}
+	return iter, local
+}
+
+// iterStart returns the local time at which iteration iter begins.
+func (tl *Timeline) iterStart(iter int) float32 {
+	if tl.Yoyo && iter%2 == 1 {
+		return tl.duration
+	}
+	return 0
+}
+
+// seekPos moves the playhead to pos, stepping through the iteration
+// boundaries in between.
+func (tl *Timeline) seekPos(pos float32, fire bool) {
+	pos = max(pos, 0)
+	if total := tl.TotalDuration(); total >= 0 {
+		pos = min(pos, total)
+	}
+	iter, _ := tl.iterAt(tl.pos)
+	newIter, newLocal := tl.iterAt(pos)
+	for iter != newIter {
+		var next int
+		if newIter > iter {
+			next = iter + 1
+			tl.render(tl.duration-tl.iterStart(iter), fire)
+			tl.jump(tl.iterStart(next))
+		} else {
+			next = iter - 1
+			tl.render(tl.iterStart(iter), fire)
+			tl.jump(tl.duration - tl.iterStart(next))
+		}
+		iter = next
+		if fire && tl.OnRepeat != nil {
+			tl.OnRepeat()
+		}
+	}
+	tl.render(newLocal, fire)
+	tl.pos = pos
+}
+
+// jump moves to local time t without firing callbacks.
+func (tl *Timeline) jump(t float32) {
+	if t != tl.local {
+		tl.render(t, false)
+	}
+}
+
+// render applies local time t to every item. Items not yet reached are
+// rewound latest first, then reached items are applied in start order so
+// the latest one writing a field wins. When fire is set, callbacks crossed
+// between the previous local time and t fire in the direction of travel.
+func (tl *Timeline) render(t float32, fire bool) {
+	prev := tl.local
+	dirty := false
+	for i := len(tl.entries) - 1; i >= 0; i-- {
+		e := tl.entries[i]
+		if e.item == nil || e.start <= t || !e.entered || e.local == 0 {
+			continue
+		}
+		e.local = 0
+		e.item.timelineSeek(0, fire)
+		dirty = true
+	}
+	for _, e := range tl.entries {
+		if e.start > t {
+			break
+		}
+		if e.item == nil {
+			continue
+		}
+		if !e.entered {
+			e.entered = true
+			e.item.timelineEnter()
+			dirty = true
+		}
+		local := min(t-e.start, e.item.timelineDuration())
+		if dirty || local != e.local {
+			e.local = local
+			e.item.timelineSeek(local, fire)
+			dirty = true
+		}
+	}
+	tl.local = t
+
+	if !fire {
+		if t != prev {
+			tl.atEdge = true
+		}
+		return
+	}
+	if t >= prev {
+		for _, e := range tl.entries {
+			if e.fn != nil && (e.start > prev || (tl.atEdge && e.start == prev)) && e.start <= t {
+				e.fn()
+			}
+		}
+	} else {
+		for i := len(tl.entries) - 1; i >= 0; i-- {
+			e := tl.entries[i]
+			if e.fn != nil && (e.start < prev || (tl.atEdge && e.start == prev)) && e.start >= t {
+				e.fn()
+			}
+		}
+	}
+	tl.atEdge = false
+}
+
+func (tl *Timeline) timelineDuration() float32 {
+	if tl.Repeat < 0 {
+		return tl.duration
+	}
+	return tl.TotalDuration()
+}
+
+func (tl *Timeline) timelineEnter() {}
+
+func (tl *Timeline) timelineSeek(t float32, fire bool) {
+	tl.seekPos(t, fire)
+}
+
+func (g *TweenGroup) timelineDuration() float32 { return g.duration }
+
+func (g *TweenGroup) timelineEnter() { g.rebase() }
+
+func (g *TweenGroup) timelineSeek(t float32, _ bool) { g.seek(t) }
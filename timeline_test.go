@@ -0,0 +1,161 @@
+package willow
+
+import (
+	"math"
+	"testing"
+
+	"github.com/tanema/gween/ease"
+)
+
+func near(a, b float64) bool { return math.Abs(a-b) < 1e-3 }
+
+func TestTimelineSequenceAndParallel(t *testing.T) {
+	n := NewContainer("n")
+	tl := NewTimeline().
+		Then(TweenPosition(n, 100, 0, 1, ease.Linear)).
+		With(TweenAlpha(n, 0, 1, ease.Linear)).
+		Then(TweenPosition(n, 100, 50, 1, ease.Linear))
+
+	if tl.Duration() != 2 {
+		t.Fatalf("Duration = %v, want 2", tl.Duration())
+	}
+	tl.Update(0.5)
+	if !near(n.X, 50) || !near(n.Alpha, 0.5) {
+		t.Errorf("at 0.5: X=%v Alpha=%v, want 50, 0.5", n.X, n.Alpha)
+	}
+	tl.Update(1)
+	// The second tween starts from where the first ended.
+	if !near(n.X, 100) || !near(n.Y, 25) {
+		t.Errorf("at 1.5: X=%v Y=%v, want 100, 25", n.X, n.Y)
+	}
+	tl.Update(1)
+	if !tl.Done || !near(n.Y, 50) {
+		t.Errorf("Done=%v Y=%v, want true, 50", tl.Done, n.Y)
+	}
+}
+
+func TestTimelineCallbacksAndDelay(t *testing.T) {
+	n := NewContainer("n")
+	var got []string
+	tl := NewTimeline().
+		Call(func() { got = append(got, "start") }).
+		Delay(0.5).
+		Label("move").
+		Then(TweenPosition(n, 10, 0, 1, ease.Linear)).
+		Call(func() { got = append(got, "end") })
+	tl.OnComplete = func() { got = append(got, "complete") }
+
+	tl.Update(0.25)
+	if n.X != 0 || len(got) != 1 || got[0] != "start" {
+		t.Fatalf("after delay: X=%v got=%v", n.X, got)
+	}
+	if at, ok := tl.LabelTime("move"); !ok || at != 0.5 {
+		t.Errorf("LabelTime = %v, %v", at, ok)
+	}
+	tl.Update(2)
+	if len(got) != 3 || got[1] != "end" || got[2] != "complete" {
+		t.Errorf("callbacks = %v", got)
+	}
+}
+
+func TestTimelineSeekAndReverse(t *testing.T) {
+	n := NewContainer("n")
+	calls := 0
+	tl := NewTimeline().
+		Then(TweenPosition(n, 100, 0, 1, ease.Linear)).
+		Call(func() { calls++ }).
+		Label("back").
+		Then(TweenPosition(n, 0, 0, 1, ease.Linear))
+
+	tl.Seek(1.5)
+	if !near(n.X, 50) || calls != 0 {
+		t.Errorf("Seek(1.5): X=%v calls=%d, want 50, 0", n.X, calls)
+	}
+	tl.Seek(0.25)
+	if !near(n.X, 25) {
+		t.Errorf("Seek(0.25): X=%v, want 25", n.X)
+	}
+	if !tl.SeekLabel("back") || !near(n.X, 100) {
+		t.Errorf("SeekLabel: X=%v, want 100", n.X)
+	}
+
+	tl.Seek(2)
+	tl.Reverse()
+	tl.Update(1.5)
+	if !near(n.X, 50) || calls != 1 {
+		t.Errorf("reversed to 0.5: X=%v calls=%d, want 50, 1", n.X, calls)
+	}
+	tl.Update(1)
+	if !tl.Done || n.X != 0 {
+		t.Errorf("reversed end: Done=%v X=%v", tl.Done, n.X)
+	}
+}
+
+func TestTimelineRepeatYoyo(t *testing.T) {
+	n := NewContainer("n")
+	repeats := 0
+	tl := NewTimeline().Then(TweenPosition(n, 10, 0, 1, ease.Linear))
+	tl.Repeat = 2
+	tl.Yoyo = true
+	tl.OnRepeat = func() { repeats++ }
+
+	tl.Update(1.25)
+	if !near(n.X, 7.5) || repeats != 1 {
+		t.Errorf("yoyo back: X=%v repeats=%d, want 7.5, 1", n.X, repeats)
+	}
+	tl.Update(1)
+	if !near(n.X, 2.5) || repeats != 2 {
+		t.Errorf("third pass: X=%v repeats=%d, want 2.5, 2", n.X, repeats)
+	}
+	tl.Update(5)
+	if !tl.Done || !near(n.X, 10) || tl.Elapsed() != 3 {
+		t.Errorf("end: Done=%v X=%v Elapsed=%v", tl.Done, n.X, tl.Elapsed())
+	}
+
+	// Without yoyo each iteration restarts from the captured start.
+	n.X = 0
+	tl = NewTimeline().Then(TweenPosition(n, 10, 0, 1, ease.Linear))
+	tl.Repeat = -1
+	tl.Update(2.5)
+	if !near(n.X, 5) || tl.Done {
+		t.Errorf("repeat forever: X=%v Done=%v", n.X, tl.Done)
+	}
+}
+
+func TestTimelineTimeScaleAndNesting(t *testing.T) {
+	n := NewContainer("n")
+	inner := NewTimeline().
+		Then(TweenPosition(n, 10, 0, 1, ease.Linear)).
+		Then(TweenPosition(n, 10, 10, 1, ease.Linear))
+	fired := false
+	inner.CallAt(2, func() { fired = true })
+
+	tl := NewTimeline().Delay(1).Then(inner)
+	tl.TimeScale = 2
+	if tl.Duration() != 3 {
+		t.Fatalf("Duration = %v, want 3", tl.Duration())
+	}
+	tl.Update(1)
+	if !near(n.X, 10) || n.Y != 0 {
+		t.Errorf("at 2s: X=%v Y=%v, want 10, 0", n.X, n.Y)
+	}
+	tl.Paused = true
+	tl.Update(1)
+	tl.Paused = false
+	tl.Update(0.5)
+	if !tl.Done || !near(n.Y, 10) || !fired {
+		t.Errorf("end: Done=%v Y=%v fired=%v", tl.Done, n.Y, fired)
+	}
+}
+
+func TestTimelineSkipsDisposedTarget(t *testing.T) {
+	n := NewContainer("n")
+	tl := NewTimeline().Then(TweenPosition(n, 10, 0, 1, ease.Linear))
+	tl.Update(0.5)
+	n.Dispose()
+	x := n.X
+	tl.Update(0.25)
+	if n.X != x {
+		t.Errorf("disposed node written: X=%v, want %v", n.X, x)
+	}
+}
@@ -0,0 +1,182 @@
+package willow
+
+// Tween is anything the scene's TweenRunner can tick: a *TweenGroup or a
+// *Timeline.
+type Tween interface {
+	Update(dt float32)
+	tweenDone() bool
+	// tweenTargets calls fn for each target node until fn returns false.
+	tweenTargets(fn func(*Node) bool) bool
+}
+
+// tweenRun is one tween registered with a TweenRunner.
+type tweenRun struct {
+	tween  Tween
+	tags   []string
+	paused bool
+	killed bool
+}
+
+// TweenRunner ticks the tweens registered with a Scene during Scene.Update.
+// Tweens are removed once they finish or all their target nodes are
+// disposed. Get a scene's runner with Scene.Tweens.
+type TweenRunner struct {
+	// TimeScale multiplies dt for every tween in the runner. 1 is normal
+	// speed, 0 freezes all tweens.
+	TimeScale float32
+
+	runs []*tweenRun
+}
+
+// Tweens returns the scene's tween runner.
+func (s *Scene) Tweens() *TweenRunner {
+	return &s.tweens
+}
+
+// Add registers t with the runner under the given tags and returns t.
+func (r *TweenRunner) Add(t Tween, tags ...string) Tween {
+	r.runs = append(r.runs, &tweenRun{tween: t, tags: tags})
+	return t
+}
+
+// Len returns the number of registered tweens.
+func (r *TweenRunner) Len() int {
+	n := 0
+	for _, run := range r.runs {
+		if !run.killed {
+			n++
+		}
+	}
+	return n
+}
+
+// Has reports whether t is registered.
+func (r *TweenRunner) Has(t Tween) bool {
+	for _, run := range r.runs {
+		if run.tween == t && !run.killed {
+			return true
+		}
+	}
+	return false
+}
+
+// Kill removes t, leaving its targets where they are.
+func (r *TweenRunner) Kill(t Tween) {
+	r.each(func(run *tweenRun) bool { return run.tween == t }, func(run *tweenRun) { run.killed = true })
+}
+
+// PauseNode pauses every tween animating n.
+func (r *TweenRunner) PauseNode(n *Node) {
+	r.each(targetsNode(n), func(run *tweenRun) { run.paused = true })
+}
+
+// ResumeNode resumes every tween animating n.
+func (r *TweenRunner) ResumeNode(n *Node) {
+	r.each(targetsNode(n), func(run *tweenRun) { run.paused = false })
+}
+
+// KillNode removes every tween animating n.
+func (r *TweenRunner) KillNode(n *Node) {
+	r.each(targetsNode(n), func(run *tweenRun) { run.killed = true })
+}
+
+// PauseTag pauses every tween registered with tag.
+func (r *TweenRunner) PauseTag(tag string) {
+	r.each(hasTag(tag), func(run *tweenRun) { run.paused = true })
+}
+
+// ResumeTag resumes every tween registered with tag.
+func (r *TweenRunner) ResumeTag(tag string) {
+	r.each(hasTag(tag), func(run *tweenRun) { run.paused = false })
+}
+
+// KillTag removes every tween registered with tag.
+func (r *TweenRunner) KillTag(tag string) {
+	r.each(hasTag(tag), func(run *tweenRun) { run.killed = true })
+}
+
+// KillAll removes every tween.
+func (r *TweenRunner) KillAll() {
+	r.each(func(*tweenRun) bool { return true }, func(run *tweenRun) { run.killed = true })
+}
+
+func (r *TweenRunner) each(match func(*tweenRun) bool, apply func(*tweenRun)) {
+	for _, run := range r.runs {
+		if !run.killed && match(run) {
+			apply(run)
+		}
+	}
+}
+
+func targetsNode(n *Node) func(*tweenRun) bool {
+	return func(run *tweenRun) bool {
+		return !run.tween.tweenTargets(func(t *Node) bool { return t != n })
+	}
+}
+
+func hasTag(tag string) func(*tweenRun) bool {
+	return func(run *tweenRun) bool {
+		for _, t := range run.tags {
+			if t == tag {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// update ticks every unpaused tween and drops finished ones. Tweens added
+// during the tick start on the next frame.
+func (r *TweenRunner) update(dt float32) {
+	dt *= r.TimeScale
+	n := len(r.runs)
+	for i := 0; i < n; i++ {
+		run := r.runs[i]
+		if run.killed || run.paused {
+			continue
+		}
+		if allTargetsDisposed(run.tween) {
+			run.killed = true
+			continue
+		}
+		run.tween.Update(dt)
+		if run.tween.tweenDone() {
+			run.killed = true
+		}
+	}
+	live := r.runs[:0]
+	for _, run := range r.runs {
+		if !run.killed {
+			live = append(live, run)
+		}
+	}
+	clear(r.runs[len(live):])
+	r.runs = live
+}
+
+// allTargetsDisposed reports whether t has targets and all are disposed.
+func allTargetsDisposed(t Tween) bool {
+	found := false
+	disposed := t.tweenTargets(func(n *Node) bool {
+		found = true
+		return n.IsDisposed()
+	})
+	return found && disposed
+}
+
+func (g *TweenGroup) tweenDone() bool { return g.Done }
+
+func (g *TweenGroup) tweenTargets(fn func(*Node) bool) bool {
+	return g.target == nil || fn(g.target)
+}
+
+func (tl *Timeline) tweenDone() bool { return tl.Done }
+
+func (tl *Timeline) tweenTargets(fn func(*Node) bool) bool {
+	for _, e := range tl.entries {
+		if t, ok := e.item.(Tween); ok && !t.tweenTargets(fn) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,95 @@
+package willow
+
+import (
+	"testing"
+
+	"github.com/tanema/gween/ease"
+)
+
+func TestTweenRunnerTicksAndRemoves(t *testing.T) {
+	s := NewScene()
+	n := NewContainer("n")
+	s.Root().AddChild(n)
+	g := TweenPosition(n, 100, 0, 0.1, ease.Linear)
+	s.Tweens().Add(g)
+
+	s.Update()
+	if n.X == 0 {
+		t.Fatal("tween was not ticked by Scene.Update")
+	}
+	for i := 0; i < 10; i++ {
+		s.Update()
+	}
+	if !g.Done || s.Tweens().Has(g) || n.X != 100 {
+		t.Errorf("Done=%v registered=%v X=%v", g.Done, s.Tweens().Has(g), n.X)
+	}
+}
+
+func TestTweenRunnerDisposedTarget(t *testing.T) {
+	s := NewScene()
+	a, b := NewContainer("a"), NewContainer("b")
+	tl := NewTimeline().
+		Then(TweenAlpha(a, 0, 1, ease.Linear)).
+		With(TweenAlpha(b, 0, 1, ease.Linear))
+	s.Tweens().Add(tl)
+
+	a.Dispose()
+	s.Update()
+	if !s.Tweens().Has(tl) {
+		t.Fatal("timeline removed while one target is alive")
+	}
+	b.Dispose()
+	s.Update()
+	if s.Tweens().Len() != 0 {
+		t.Error("timeline kept after all targets were disposed")
+	}
+}
+
+func TestTweenRunnerPauseKillByNodeAndTag(t *testing.T) {
+	s := NewScene()
+	a, b := NewContainer("a"), NewContainer("b")
+	ga := TweenPosition(a, 100, 0, 1, ease.Linear)
+	gb := TweenPosition(b, 100, 0, 1, ease.Linear)
+	r := s.Tweens()
+	r.Add(ga, "ui")
+	r.Add(gb, "ui", "b")
+
+	r.PauseNode(a)
+	s.Update()
+	if a.X != 0 || b.X == 0 {
+		t.Errorf("PauseNode: a.X=%v b.X=%v", a.X, b.X)
+	}
+	r.ResumeNode(a)
+	r.PauseTag("ui")
+	bx := b.X
+	s.Update()
+	if a.X != 0 || b.X != bx {
+		t.Error("PauseTag did not pause tagged tweens")
+	}
+	r.ResumeTag("ui")
+	r.KillTag("b")
+	if r.Has(gb) || !r.Has(ga) {
+		t.Error("KillTag removed the wrong tweens")
+	}
+	r.KillNode(a)
+	if r.Len() != 0 {
+		t.Errorf("Len = %d after KillNode, want 0", r.Len())
+	}
+}
+
+func TestTweenRunnerTimeScale(t *testing.T) {
+	s := NewScene()
+	n := NewContainer("n")
+	g := TweenPosition(n, 100, 0, 1, ease.Linear)
+	s.Tweens().Add(g)
+	s.Tweens().TimeScale = 0
+	s.Update()
+	if n.X != 0 {
+		t.Errorf("X = %v with TimeScale 0, want 0", n.X)
+	}
+	s.Tweens().TimeScale = 2
+	s.tweens.update(0.25)
+	if !near(n.X, 50) {
+		t.Errorf("X = %v, want 50", n.X)
+	}
+}
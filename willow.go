@@ -137,6 +137,11 @@ const (
 	EventPinch                         // fires during a two-finger pinch/rotate gesture
 	EventPointerEnter                  // fires when the pointer enters a node's bounds
 	EventPointerLeave                  // fires when the pointer leaves a node's bounds
+	EventFocus                         // fires when a node gains keyboard focus
+	EventBlur                          // fires when a node loses keyboard focus
+	EventWheel                         // fires when the mouse wheel scrolls over a node
+	EventDoubleClick                   // fires on the second of two quick clicks on the same node
+	EventLongPress                     // fires when a pointer is held still on a node
 )
 
 // MouseButton identifies a mouse button.